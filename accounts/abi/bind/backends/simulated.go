@@ -70,6 +70,8 @@ type SimulatedBackend struct {
 
 	events *filters.EventSystem // Event system for filtering log events live
 
+	l1FeeOverride *fees.GPOOverride // L1GasPriceOracle values used instead of the chain state, if set
+
 	config *params.ChainConfig
 }
 
@@ -582,14 +584,40 @@ func (b *SimulatedBackend) EstimateGas(ctx context.Context, call ethereum.CallMs
 	return hi, nil
 }
 
-// callContract implements common code between normal and pending contract calls.
-// state is modified during execution, make sure to copy it if necessary.
-func (b *SimulatedBackend) callContract(ctx context.Context, call ethereum.CallMsg, block *types.Block, stateDB *state.StateDB) (*core.ExecutionResult, error) {
+// SetL1FeeOverride makes subsequent calls, gas and L1 data fee estimations use
+// the given L1GasPriceOracle values instead of the ones in the chain state.
+// Passing nil restores the default behaviour.
+func (b *SimulatedBackend) SetL1FeeOverride(override *fees.GPOOverride) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.l1FeeOverride = override
+}
+
+// EstimateL1DataFee returns the L1 data fee of the given call against the
+// currently pending block/state, split into its commit and blob components.
+func (b *SimulatedBackend) EstimateL1DataFee(ctx context.Context, call ethereum.CallMsg) (*fees.L1DataFeeBreakdown, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	defer b.pendingState.RevertToSnapshot(b.pendingState.Snapshot())
+
+	head := b.blockchain.CurrentHeader()
+	call, err := b.prepareCallMsg(call, head)
+	if err != nil {
+		return nil, err
+	}
+	b.l1FeeOverride.Apply(b.pendingState)
+	signer := types.MakeSigner(b.blockchain.Config(), head.Number)
+	return fees.EstimateL1DataFeeBreakdownForMessage(callMsg{call}, head.BaseFee, b.blockchain.Config(), signer, b.pendingState, head.Number)
+}
+
+// prepareCallMsg fills in the gas price fields of a call according to the
+// fork active at the given header.
+func (b *SimulatedBackend) prepareCallMsg(call ethereum.CallMsg, head *types.Header) (ethereum.CallMsg, error) {
 	// Gas prices post 1559 need to be initialized
 	if call.GasPrice != nil && (call.GasFeeCap != nil || call.GasTipCap != nil) {
-		return nil, errors.New("both gasPrice and (maxFeePerGas or maxPriorityFeePerGas) specified")
+		return call, errors.New("both gasPrice and (maxFeePerGas or maxPriorityFeePerGas) specified")
 	}
-	head := b.blockchain.CurrentHeader()
 	if !b.blockchain.Config().IsCurie(head.Number) {
 		// If there's no basefee, then it must be a non-1559 execution
 		if call.GasPrice == nil {
@@ -627,6 +655,18 @@ func (b *SimulatedBackend) callContract(ctx context.Context, call ethereum.CallM
 	if call.Value == nil {
 		call.Value = new(big.Int)
 	}
+	return call, nil
+}
+
+// callContract implements common code between normal and pending contract calls.
+// state is modified during execution, make sure to copy it if necessary.
+func (b *SimulatedBackend) callContract(ctx context.Context, call ethereum.CallMsg, block *types.Block, stateDB *state.StateDB) (*core.ExecutionResult, error) {
+	head := b.blockchain.CurrentHeader()
+	call, err := b.prepareCallMsg(call, head)
+	if err != nil {
+		return nil, err
+	}
+	b.l1FeeOverride.Apply(stateDB)
 	// Set infinite balance to the fake caller account.
 	from := stateDB.GetOrNewStateObject(call.From)
 	from.SetBalance(math.MaxBig256)
@@ -893,6 +933,8 @@ func (fb *filterBackend) ServiceFilter(ctx context.Context, ms *bloombits.Matche
 	panic("not supported")
 }
 
+func (fb *filterBackend) LogIndexStatus() (uint64, uint64) { return 0, 0 }
+
 func nullSubscription() event.Subscription {
 	return event.NewSubscription(func(quit <-chan struct{}) error {
 		<-quit
@@ -35,6 +35,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/crypto"
 	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rollup/fees"
 )
 
 func TestSimulatedBackend(t *testing.T) {
@@ -1356,3 +1357,50 @@ func TestForkResendTx(t *testing.T) {
 		t.Errorf("TX included in wrong block: %d", h)
 	}
 }
+
+func TestL1FeeOverride(t *testing.T) {
+	testAddr := crypto.PubkeyToAddress(testKey.PublicKey)
+	sim := simTestBackend(testAddr)
+	defer sim.Close()
+
+	call := ethereum.CallMsg{From: testAddr, To: &testAddr, Value: big.NewInt(1), Data: []byte{0x01, 0x02}}
+
+	fee, err := sim.EstimateL1DataFee(context.Background(), call)
+	if err != nil {
+		t.Fatalf("could not estimate L1 data fee: %v", err)
+	}
+	if fee.L1DataFee.Sign() != 0 {
+		t.Fatalf("expected zero L1 data fee without oracle values, got %v", fee.L1DataFee)
+	}
+
+	override := &fees.GPOOverride{
+		L1BaseFee:     big.NewInt(1000000000),
+		Scalar:        big.NewInt(1000000000),
+		L1BlobBaseFee: big.NewInt(0),
+		CommitScalar:  big.NewInt(1000000000),
+	}
+	sim.SetL1FeeOverride(override)
+	base, err := sim.EstimateL1DataFee(context.Background(), call)
+	if err != nil {
+		t.Fatalf("could not estimate L1 data fee: %v", err)
+	}
+	if base.L1DataFee.Sign() == 0 {
+		t.Fatal("expected non-zero L1 data fee with oracle override")
+	}
+
+	// Doubling the L1 base fee must double the L1 data fee.
+	override.L1BaseFee = big.NewInt(2000000000)
+	doubled, err := sim.EstimateL1DataFee(context.Background(), call)
+	if err != nil {
+		t.Fatalf("could not estimate L1 data fee: %v", err)
+	}
+	if want := new(big.Int).Mul(base.L1DataFee, big.NewInt(2)); doubled.L1DataFee.Cmp(want) != 0 {
+		t.Errorf("unexpected L1 data fee: have %v, want %v", doubled.L1DataFee, want)
+	}
+
+	// The override must not leak into the pending state.
+	sim.SetL1FeeOverride(nil)
+	if fee, _ := sim.EstimateL1DataFee(context.Background(), call); fee.L1DataFee.Sign() != 0 {
+		t.Errorf("override leaked into pending state, L1 data fee %v", fee.L1DataFee)
+	}
+}
@@ -163,6 +163,49 @@ It's deprecated, please use "geth db import" instead.
 		Description: `
 The export-preimages command exports hash preimages to an RLP encoded stream.
 It's deprecated, please use "geth db export" instead.
+`,
+	}
+	importRollupCommand = cli.Command{
+		Action:    utils.MigrateFlags(importRollup),
+		Name:      "import-rollup",
+		Usage:     "Import the L1 message and rollup event state from an RLP stream",
+		ArgsUsage: "<datafile>",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.CacheFlag,
+			utils.SyncModeFlag,
+			utils.ScrollAlphaFlag,
+			utils.ScrollSepoliaFlag,
+			utils.ScrollFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The import-rollup command imports the L1 messages, batch chunk ranges, finalized
+batch metadata and sync cursors written by export-rollup into a datadir without
+rollup state. The checksum and the continuity of the L1 message queue are verified
+before anything is written. The L1 sync services resume from the exported cursors.
+Together with "geth import" of the chain segments, it bootstraps a follower node
+without re-scanning L1.
+`,
+	}
+	exportRollupCommand = cli.Command{
+		Action:    utils.MigrateFlags(exportRollup),
+		Name:      "export-rollup",
+		Usage:     "Export the L1 message and rollup event state into an RLP stream",
+		ArgsUsage: "<dumpfile>",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.CacheFlag,
+			utils.SyncModeFlag,
+			utils.ScrollAlphaFlag,
+			utils.ScrollSepoliaFlag,
+			utils.ScrollFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The export-rollup command exports the L1 messages, batch chunk ranges, finalized
+batch metadata and the L1 sync cursors they are anchored to, followed by a checksum.
+If the file ends with .gz, the output will be gzipped.
 `,
 	}
 	dumpCommand = cli.Command{
@@ -390,6 +433,44 @@ func exportPreimages(ctx *cli.Context) error {
 	return nil
 }
 
+// importRollup imports the rollup state from the specified file.
+func importRollup(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, false)
+	defer db.Close()
+	start := time.Now()
+
+	if err := utils.ImportRollup(db, ctx.Args().First()); err != nil {
+		utils.Fatalf("Import error: %v\n", err)
+	}
+	fmt.Printf("Import done in %v\n", time.Since(start))
+	return nil
+}
+
+// exportRollup dumps the rollup state to the specified file.
+func exportRollup(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	defer db.Close()
+	start := time.Now()
+
+	if err := utils.ExportRollup(db, ctx.Args().First()); err != nil {
+		utils.Fatalf("Export error: %v\n", err)
+	}
+	fmt.Printf("Export done in %v\n", time.Since(start))
+	return nil
+}
+
 func parseDumpConfig(ctx *cli.Context, stack *node.Node) (*state.DumpConfig, ethdb.Database, common.Hash, error) {
 	db := utils.MakeChainDatabase(ctx, stack, true)
 	var header *types.Header
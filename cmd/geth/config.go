@@ -172,6 +172,12 @@ func makeFullNode(ctx *cli.Context) (*node.Node, ethapi.Backend) {
 			utils.Fatalf("%v", err)
 		}
 	}
+	if ctx.GlobalBool(utils.ScrollEngineFlag.Name) {
+		if eth == nil {
+			utils.Fatalf("Scroll engine API does not work in light client mode.")
+		}
+		catalyst.RegisterScrollEngine(stack, eth)
+	}
 
 	// Configure GraphQL if requested
 	if ctx.GlobalIsSet(utils.GraphQLEnabledFlag.Name) {
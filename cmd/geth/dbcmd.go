@@ -18,6 +18,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -35,9 +36,13 @@ import (
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/common/hexutil"
 	"github.com/scroll-tech/go-ethereum/console/prompt"
+	"github.com/scroll-tech/go-ethereum/core"
 	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/eth"
 	"github.com/scroll-tech/go-ethereum/ethdb"
 	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/params"
 	"github.com/scroll-tech/go-ethereum/trie"
 )
 
@@ -70,6 +75,9 @@ Remove blockchain and state databases`,
 			dbDumpFreezerIndex,
 			dbImportCmd,
 			dbExportCmd,
+			dbRollupCmd,
+			dbRebuildLogIndexCmd,
+			dbRebuildAccountHistoryCmd,
 		},
 	}
 	dbInspectCmd = cli.Command{
@@ -264,6 +272,103 @@ WARNING: This is a low-level operation which may cause database corruption!`,
 		},
 		Description: "Exports the specified chain data to an RLP encoded stream, optionally gzip-compressed.",
 	}
+	dbRebuildLogIndexCmd = cli.Command{
+		Action: utils.MigrateFlags(dbRebuildLogIndex),
+		Name:   "rebuild-logindex",
+		Usage:  "Rebuild the address/topic index of the logs from scratch",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.SyncModeFlag,
+			utils.ScrollAlphaFlag,
+			utils.ScrollSepoliaFlag,
+			utils.ScrollFlag,
+			utils.CacheFlag,
+			utils.CacheDatabaseFlag,
+		},
+		Description: `This command drops the log index maintained with --logindex and rebuilds it
+for the whole canonical chain. A node started with --logindex catches up from
+the last rebuilt section on its own.`,
+	}
+	dbRebuildAccountHistoryCmd = cli.Command{
+		Action: utils.MigrateFlags(dbRebuildAccountHistory),
+		Name:   "rebuild-accounthistory",
+		Usage:  "Rebuild the index of the transactions by account from scratch",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.SyncModeFlag,
+			utils.GCModeFlag,
+			utils.ScrollAlphaFlag,
+			utils.ScrollSepoliaFlag,
+			utils.ScrollFlag,
+			utils.CacheFlag,
+			utils.CacheDatabaseFlag,
+		},
+		Description: `This command drops the account history maintained with --accounthistory and
+rebuilds it for the whole canonical chain. The internal calls of a transaction
+are only indexed if the state of its parent block is available, so they are
+indexed for every block on archive nodes only. A node started with
+--accounthistory catches up from the last rebuilt block on its own.`,
+	}
+	dbRollupFlags = []cli.Flag{
+		utils.DataDirFlag,
+		utils.SyncModeFlag,
+		utils.ScrollAlphaFlag,
+		utils.ScrollSepoliaFlag,
+		utils.ScrollFlag,
+	}
+	dbRollupCmd = cli.Command{
+		Name:      "rollup",
+		Usage:     "Inspect and repair the rollup metadata of the database",
+		ArgsUsage: "",
+		Subcommands: []cli.Command{
+			{
+				Action:      utils.MigrateFlags(dbRollupL1Messages),
+				Name:        "l1messages",
+				Usage:       "List the L1 messages of the message queue",
+				ArgsUsage:   "<start (int)> <count (int, optional)>",
+				Flags:       dbRollupFlags,
+				Description: "This command lists the L1 messages starting at the given enqueue index.",
+			},
+			{
+				Action:    utils.MigrateFlags(dbRollupVerifyL1Messages),
+				Name:      "verify-l1messages",
+				Usage:     "Verify the continuity of the L1 message queue",
+				ArgsUsage: "<start (int, optional)>",
+				Flags:     dbRollupFlags,
+				Description: `This command checks that the L1 messages from the given enqueue index up to
+the highest synced one are all present and stored under their own index.`,
+			},
+			{
+				Action:      utils.MigrateFlags(dbRollupBatches),
+				Name:        "batches",
+				Usage:       "Dump the chunk ranges and finalized metadata of batches",
+				ArgsUsage:   "<start (int)> <end (int)>",
+				Flags:       dbRollupFlags,
+				Description: "This command dumps the chunk block ranges and finalized metadata of the given batches.",
+			},
+			{
+				Action:    utils.MigrateFlags(dbRollupResetL1Sync),
+				Name:      "reset-l1-sync",
+				Usage:     "Reset the L1 message sync cursor to an L1 block (WARNING: may corrupt your database)",
+				ArgsUsage: "<L1 block number (int)>",
+				Flags:     dbRollupFlags,
+				Description: `This command sets the last synced L1 block of the L1 message sync service,
+which resumes syncing L1 messages from the next block on restart.
+WARNING: This is a low-level operation which may cause database corruption!`,
+			},
+			{
+				Action:    utils.MigrateFlags(dbRollupRederiveQueueIndex),
+				Name:      "rederive-queue-index",
+				Usage:     "Re-derive the first L1 message not included in a range of L2 blocks",
+				ArgsUsage: "<start (int)> <end (int)>",
+				Flags:     dbRollupFlags,
+				Description: `This command recomputes the first L1 message enqueue index not included in
+each canonical L2 block of the given range from the block bodies, and rewrites
+the missing or lagging indices. The index of the parent of the first block must
+be present.`,
+			},
+		},
+	}
 )
 
 func removeDB(ctx *cli.Context) error {
@@ -716,3 +821,217 @@ func exportChaindata(ctx *cli.Context) error {
 	db := utils.MakeChainDatabase(ctx, stack, true)
 	return utils.ExportChaindata(ctx.Args().Get(1), kind, exporter(db), stop)
 }
+
+// parseUint64Args parses the given positional arguments as decimal integers.
+func parseUint64Args(ctx *cli.Context, names ...string) ([]uint64, error) {
+	values := make([]uint64, len(names))
+	for i, name := range names {
+		value, err := strconv.ParseUint(ctx.Args().Get(i), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", name, err)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// dbRollupL1Messages lists the L1 messages starting at a given enqueue index.
+func dbRollupL1Messages(ctx *cli.Context) error {
+	if ctx.NArg() < 1 || ctx.NArg() > 2 {
+		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
+	}
+	args, err := parseUint64Args(ctx, "start")
+	if err != nil {
+		return err
+	}
+	count := uint64(100)
+	if ctx.NArg() == 2 {
+		if count, err = strconv.ParseUint(ctx.Args().Get(1), 10, 64); err != nil {
+			return fmt.Errorf("invalid count: %v", err)
+		}
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	defer db.Close()
+
+	it := rawdb.IterateL1MessagesFrom(db, args[0])
+	defer it.Release()
+
+	for n := uint64(0); n < count && it.Next(); n++ {
+		msg := it.L1Message()
+		fmt.Printf("%d: hash %v sender %v target %v value %v gas %d data %d bytes\n",
+			it.QueueIndex(), types.NewTx(&msg).Hash(), msg.Sender, msg.To, msg.Value, msg.Gas, len(msg.Data))
+	}
+	fmt.Printf("Highest synced queue index: %d\n", rawdb.ReadHighestSyncedQueueIndex(db))
+	return it.Error()
+}
+
+// dbRollupVerifyL1Messages checks the continuity of the L1 message queue.
+func dbRollupVerifyL1Messages(ctx *cli.Context) error {
+	if ctx.NArg() > 1 {
+		return fmt.Errorf("Max 1 argument: %v", ctx.Command.ArgsUsage)
+	}
+	var start uint64
+	if ctx.NArg() == 1 {
+		args, err := parseUint64Args(ctx, "start")
+		if err != nil {
+			return err
+		}
+		start = args[0]
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	defer db.Close()
+
+	verified, err := rawdb.VerifyL1MessageQueue(db, start)
+	if err != nil {
+		log.Error("L1 message queue corrupted", "start", start, "verified", verified, "err", err)
+		return err
+	}
+	log.Info("L1 message queue verified", "start", start, "messages", verified, "highest", rawdb.ReadHighestSyncedQueueIndex(db))
+	return nil
+}
+
+// dbRollupBatches dumps the chunk ranges and finalized metadata of batches.
+func dbRollupBatches(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
+	}
+	args, err := parseUint64Args(ctx, "start", "end")
+	if err != nil {
+		return err
+	}
+	if args[0] > args[1] {
+		return fmt.Errorf("start %d after end %d", args[0], args[1])
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	defer db.Close()
+
+	for index := args[0]; index <= args[1]; index++ {
+		fmt.Printf("batch %d:\n", index)
+		if ranges := rawdb.ReadBatchChunkRanges(db, index); ranges != nil {
+			for i, r := range ranges {
+				fmt.Printf("  chunk %d: blocks %d-%d\n", i, r.StartBlockNumber, r.EndBlockNumber)
+			}
+		} else {
+			fmt.Println("  chunk ranges: missing")
+		}
+		if meta := rawdb.ReadFinalizedBatchMeta(db, index); meta != nil {
+			fmt.Printf("  batch hash: %v\n  state root: %v\n  withdraw root: %v\n  total L1 messages popped: %d\n",
+				meta.BatchHash, meta.StateRoot, meta.WithdrawRoot, meta.TotalL1MessagePopped)
+		} else {
+			fmt.Println("  finalized meta: missing")
+		}
+	}
+	if finalized := rawdb.ReadFinalizedL2BlockNumber(db); finalized != nil {
+		fmt.Printf("Finalized L2 block: %d\n", *finalized)
+	}
+	return nil
+}
+
+// dbRollupResetL1Sync resets the L1 message sync cursor to a given L1 block.
+func dbRollupResetL1Sync(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
+	}
+	args, err := parseUint64Args(ctx, "L1 block number")
+	if err != nil {
+		return err
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, false)
+	defer db.Close()
+
+	if prev := rawdb.ReadSyncedL1BlockNumber(db); prev != nil {
+		fmt.Printf("Previous synced L1 block: %d\n", *prev)
+	}
+	rawdb.WriteSyncedL1BlockNumber(db, args[0])
+	log.Info("Reset L1 message sync cursor", "block", args[0])
+	return nil
+}
+
+// dbRollupRederiveQueueIndex re-derives the first L1 message not included in a
+// range of L2 blocks.
+func dbRollupRederiveQueueIndex(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
+	}
+	args, err := parseUint64Args(ctx, "start", "end")
+	if err != nil {
+		return err
+	}
+	if args[0] > args[1] {
+		return fmt.Errorf("start %d after end %d", args[0], args[1])
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, false)
+	defer db.Close()
+
+	start := time.Now()
+	fixed, err := rawdb.RederiveFirstQueueIndexes(db, args[0], args[1])
+	if err != nil {
+		return err
+	}
+	log.Info("Re-derived first queue indices", "start", args[0], "end", args[1], "rewritten", fixed, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// dbRebuildLogIndex drops the log index and rebuilds it from scratch.
+func dbRebuildLogIndex(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	cctx, release := interruptibleContext("Interrupted during log index rebuild, stopping at next section")
+	defer release()
+
+	db := utils.MakeChainDatabase(ctx, stack, false)
+	defer db.Close()
+
+	return core.RebuildLogIndex(cctx, db, params.LogIndexBlocks, params.BloomConfirms)
+}
+
+// dbRebuildAccountHistory drops the account history and rebuilds it from scratch.
+func dbRebuildAccountHistory(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	cctx, release := interruptibleContext("Interrupted during account history rebuild, stopping at next block")
+	defer release()
+
+	chain, db := utils.MakeChain(ctx, stack)
+	defer db.Close()
+	defer chain.Stop()
+
+	return eth.RebuildAccountHistory(cctx, chain)
+}
+
+// interruptibleContext returns a context cancelled on SIGINT or SIGTERM, logging
+// the given message, along with the function releasing it.
+func interruptibleContext(msg string) (context.Context, func()) {
+	var (
+		interrupt   = make(chan os.Signal, 1)
+		cctx, abort = context.WithCancel(context.Background())
+	)
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		if _, ok := <-interrupt; ok {
+			log.Info(msg)
+		}
+		abort()
+	}()
+	return cctx, func() {
+		signal.Stop(interrupt)
+		close(interrupt)
+	}
+}
@@ -19,6 +19,7 @@ package utils
 
 import (
 	"bufio"
+	"bytes"
 	"compress/gzip"
 	"errors"
 	"fmt"
@@ -553,3 +554,296 @@ func ExportChaindata(fn string, kind string, iter ChainDataIterator, interrupt c
 		"elapsed", common.PrettyDuration(time.Since(start)))
 	return nil
 }
+
+// rollupExportHeader is the first element of a rollup state export. It anchors
+// the exported state to the L1 blocks scanned by the sync services, so that
+// they resume from there after an import.
+type rollupExportHeader struct {
+	Magic                    string // Always set to 'gethrollupdump' for disambiguation
+	Version                  uint64
+	UnixTime                 uint64
+	SyncedL1Block            uint64 // Last L1 block scanned for L1 messages
+	RollupEventSyncedL1Block uint64 // Last L1 block scanned for rollup events, zero if none
+	HighestQueueIndex        uint64
+	FinalizedL2Block         uint64 // Zero if no batch was finalized
+}
+
+// l1Anchor returns the L1 block up to which all the exported state was synced.
+func (h *rollupExportHeader) l1Anchor() uint64 {
+	if h.RollupEventSyncedL1Block != 0 && h.RollupEventSyncedL1Block < h.SyncedL1Block {
+		return h.RollupEventSyncedL1Block
+	}
+	return h.SyncedL1Block
+}
+
+// rollupExportItem is a single entry of a rollup state export.
+type rollupExportItem struct {
+	Kind  uint64
+	Index uint64 // Enqueue index or batch index
+	Data  []byte // RLP encoding of the entry, keccak256 of the header and all previous items for the checksum
+}
+
+const rollupExportMagic = "gethrollupdump"
+
+const (
+	rollupExportL1Message = iota
+	rollupExportChunkRanges
+	rollupExportBatchMeta
+	rollupExportChecksum // Always the last item
+)
+
+// ExportRollup exports the L1 messages, batch chunk ranges and finalized batch
+// metadata together with the sync cursors into the specified file, truncating
+// any data already present in the file. If the suffix is 'gz', gzip compression
+// is used.
+func ExportRollup(db ethdb.Database, fn string) error {
+	log.Info("Exporting rollup state", "file", fn)
+
+	synced := rawdb.ReadSyncedL1BlockNumber(db)
+	if synced == nil {
+		return errors.New("no L1 messages synced")
+	}
+	header := &rollupExportHeader{
+		Magic:             rollupExportMagic,
+		UnixTime:          uint64(time.Now().Unix()),
+		SyncedL1Block:     *synced,
+		HighestQueueIndex: rawdb.ReadHighestSyncedQueueIndex(db),
+	}
+	if number := rawdb.ReadRollupEventSyncedL1BlockNumber(db); number != nil {
+		header.RollupEventSyncedL1Block = *number
+	}
+	if number := rawdb.ReadFinalizedL2BlockNumber(db); number != nil {
+		header.FinalizedL2Block = *number
+	}
+	// Open the file handle and potentially wrap with a gzip stream
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var writer io.Writer = fh
+	if strings.HasSuffix(fn, ".gz") {
+		writer = gzip.NewWriter(writer)
+		defer writer.(*gzip.Writer).Close()
+	}
+	var (
+		hasher = crypto.NewKeccakState()
+		items  = io.MultiWriter(writer, hasher)
+		count  int
+		start  = time.Now()
+	)
+	// The header is covered by the checksum too
+	if err := rlp.Encode(items, header); err != nil {
+		return err
+	}
+	write := func(kind uint64, index uint64, val interface{}) error {
+		data, err := rlp.EncodeToBytes(val)
+		if err != nil {
+			return err
+		}
+		count++
+		return rlp.Encode(items, &rollupExportItem{Kind: kind, Index: index, Data: data})
+	}
+	// Export the L1 message queue, which must be contiguous
+	it := rawdb.IterateL1MessagesFrom(db, 0)
+	defer it.Release()
+
+	var next uint64
+	for ; it.Next() && it.QueueIndex() <= header.HighestQueueIndex; next++ {
+		if it.QueueIndex() != next {
+			return fmt.Errorf("L1 message queue gap at index %d", next)
+		}
+		if err := write(rollupExportL1Message, next, it.L1Message()); err != nil {
+			return err
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if next != header.HighestQueueIndex+1 && (next != 0 || header.HighestQueueIndex != 0) {
+		return fmt.Errorf("L1 message queue incomplete: have %d messages, highest index %d", next, header.HighestQueueIndex)
+	}
+	// Export the chunk ranges of committed batches and the finalized batch metadata
+	var last uint64
+	for _, index := range []*uint64{rawdb.ReadLastBatchChunkRangesIndex(db), rawdb.ReadLastFinalizedBatchIndex(db)} {
+		if index != nil && *index+1 > last {
+			last = *index + 1
+		}
+	}
+	for index := uint64(0); index < last; index++ {
+		if ranges := rawdb.ReadBatchChunkRanges(db, index); ranges != nil {
+			if err := write(rollupExportChunkRanges, index, ranges); err != nil {
+				return err
+			}
+		}
+		if meta := rawdb.ReadFinalizedBatchMeta(db, index); meta != nil {
+			if err := write(rollupExportBatchMeta, index, meta); err != nil {
+				return err
+			}
+		}
+	}
+	var checksum common.Hash
+	hasher.Read(checksum[:])
+	if err := rlp.Encode(writer, &rollupExportItem{Kind: rollupExportChecksum, Data: checksum[:]}); err != nil {
+		return err
+	}
+	log.Info("Exported rollup state", "file", fn, "items", count, "anchor", header.l1Anchor(),
+		"checksum", checksum, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// rollupImporter checks the continuity of a rollup state export and optionally
+// writes it into a database.
+type rollupImporter struct {
+	batch ethdb.Batch // Nil if the export is only verified
+
+	nextQueueIndex uint64
+	lastRanges     *uint64
+	lastMeta       *uint64
+}
+
+func (imp *rollupImporter) process(item *rollupExportItem) error {
+	switch item.Kind {
+	case rollupExportL1Message:
+		var msg types.L1MessageTx
+		if err := rlp.DecodeBytes(item.Data, &msg); err != nil {
+			return fmt.Errorf("invalid L1 message %d: %v", item.Index, err)
+		}
+		if item.Index != imp.nextQueueIndex || msg.QueueIndex != item.Index {
+			return fmt.Errorf("L1 message queue not contiguous: have %d, want %d", msg.QueueIndex, imp.nextQueueIndex)
+		}
+		imp.nextQueueIndex++
+		if imp.batch != nil {
+			rawdb.WriteL1Message(imp.batch, msg)
+		}
+	case rollupExportChunkRanges:
+		var ranges []*rawdb.ChunkBlockRange
+		if err := rlp.DecodeBytes(item.Data, &ranges); err != nil {
+			return fmt.Errorf("invalid chunk ranges of batch %d: %v", item.Index, err)
+		}
+		if imp.lastRanges != nil && item.Index <= *imp.lastRanges {
+			return fmt.Errorf("batch %d chunk ranges out of order", item.Index)
+		}
+		imp.lastRanges = &item.Index
+		if imp.batch != nil {
+			rawdb.WriteBatchChunkRanges(imp.batch, item.Index, ranges)
+		}
+	case rollupExportBatchMeta:
+		var meta rawdb.FinalizedBatchMeta
+		if err := rlp.DecodeBytes(item.Data, &meta); err != nil {
+			return fmt.Errorf("invalid metadata of batch %d: %v", item.Index, err)
+		}
+		if imp.lastMeta != nil && item.Index <= *imp.lastMeta {
+			return fmt.Errorf("batch %d metadata out of order", item.Index)
+		}
+		imp.lastMeta = &item.Index
+		if imp.batch != nil {
+			rawdb.WriteFinalizedBatchMeta(imp.batch, item.Index, &meta)
+		}
+	default:
+		return fmt.Errorf("unknown rollup export item kind %d", item.Kind)
+	}
+	if imp.batch != nil && imp.batch.ValueSize() > ethdb.IdealBatchSize {
+		if err := imp.batch.Write(); err != nil {
+			return err
+		}
+		imp.batch.Reset()
+	}
+	return nil
+}
+
+// readRollupExport streams all items of a rollup state export through the
+// importer, verifying the checksum and the continuity of the L1 message queue.
+func readRollupExport(fn string, imp *rollupImporter) (*rollupExportHeader, error) {
+	// Open the file handle and potentially unwrap the gzip stream
+	fh, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+
+	var reader io.Reader = bufio.NewReader(fh)
+	if strings.HasSuffix(fn, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return nil, err
+		}
+	}
+	stream := rlp.NewStream(reader, 0)
+
+	var header rollupExportHeader
+	if err := stream.Decode(&header); err != nil {
+		return nil, fmt.Errorf("could not decode header: %v", err)
+	}
+	if header.Magic != rollupExportMagic {
+		return nil, errors.New("incompatible data, wrong magic")
+	}
+	if header.Version != 0 {
+		return nil, fmt.Errorf("incompatible version %d, (support only 0)", header.Version)
+	}
+	hasher := crypto.NewKeccakState()
+	if err := rlp.Encode(hasher, &header); err != nil {
+		return nil, err
+	}
+	for {
+		var item rollupExportItem
+		if err := stream.Decode(&item); err != nil {
+			if err == io.EOF {
+				return nil, errors.New("truncated data, checksum missing")
+			}
+			return nil, err
+		}
+		if item.Kind == rollupExportChecksum {
+			var checksum common.Hash
+			hasher.Read(checksum[:])
+			if !bytes.Equal(item.Data, checksum[:]) {
+				return nil, fmt.Errorf("checksum mismatch: have %#x, want %#x", checksum, item.Data)
+			}
+			break
+		}
+		if err := rlp.Encode(hasher, &item); err != nil {
+			return nil, err
+		}
+		if err := imp.process(&item); err != nil {
+			return nil, err
+		}
+	}
+	if imp.nextQueueIndex != header.HighestQueueIndex+1 && (imp.nextQueueIndex != 0 || header.HighestQueueIndex != 0) {
+		return nil, fmt.Errorf("L1 message queue truncated: have %d messages, highest index %d", imp.nextQueueIndex, header.HighestQueueIndex)
+	}
+	return &header, nil
+}
+
+// ImportRollup imports a rollup state export into a database without any rollup
+// state. The export is verified before anything is written, and the sync cursors
+// are written last, so that the sync services resume from the L1 anchor.
+func ImportRollup(db ethdb.Database, fn string) error {
+	log.Info("Importing rollup state", "file", fn)
+
+	if rawdb.ReadSyncedL1BlockNumber(db) != nil || rawdb.ReadRollupEventSyncedL1BlockNumber(db) != nil {
+		return errors.New("database already contains rollup state")
+	}
+	start := time.Now()
+	if _, err := readRollupExport(fn, new(rollupImporter)); err != nil {
+		return err
+	}
+	imp := &rollupImporter{batch: db.NewBatch()}
+	header, err := readRollupExport(fn, imp)
+	if err != nil {
+		return err
+	}
+	rawdb.WriteSyncedL1BlockNumber(imp.batch, header.SyncedL1Block)
+	if header.RollupEventSyncedL1Block != 0 {
+		rawdb.WriteRollupEventSyncedL1BlockNumber(imp.batch, header.RollupEventSyncedL1Block)
+	}
+	if header.FinalizedL2Block != 0 {
+		rawdb.WriteFinalizedL2BlockNumber(imp.batch, header.FinalizedL2Block)
+	}
+	if err := imp.batch.Write(); err != nil {
+		return err
+	}
+	log.Info("Imported rollup state", "file", fn, "messages", imp.nextQueueIndex, "anchor", header.l1Anchor(),
+		"data age", common.PrettyDuration(time.Since(time.Unix(int64(header.UnixTime), 0))),
+		"elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
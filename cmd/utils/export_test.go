@@ -18,12 +18,18 @@ package utils
 
 import (
 	"fmt"
+	"io"
+	"math/big"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
 	"github.com/scroll-tech/go-ethereum/rlp"
 )
 
@@ -196,3 +202,147 @@ func TestImportFutureFormat(t *testing.T) {
 		t.Fatalf("wrong error: %v", err)
 	}
 }
+
+// TestExportRollup checks that the rollup state survives an export/import.
+func TestExportRollup(t *testing.T) {
+	testExportRollup(t, fmt.Sprintf("%v/rollupdump", t.TempDir()))
+}
+
+func TestExportRollupGzip(t *testing.T) {
+	testExportRollup(t, fmt.Sprintf("%v/rollupdump.gz", t.TempDir()))
+}
+
+func testExportRollup(t *testing.T, f string) {
+	db := rawdb.NewMemoryDatabase()
+	for i := uint64(0); i < 10; i++ {
+		rawdb.WriteL1Message(db, types.L1MessageTx{QueueIndex: i, To: &common.Address{}, Value: big.NewInt(int64(i)), Data: []byte{byte(i)}})
+	}
+	rawdb.WriteSyncedL1BlockNumber(db, 100)
+	rawdb.WriteRollupEventSyncedL1BlockNumber(db, 90)
+	for i := uint64(3); i < 6; i++ {
+		rawdb.WriteBatchChunkRanges(db, i, []*rawdb.ChunkBlockRange{{StartBlockNumber: i * 10, EndBlockNumber: i*10 + 9}})
+		if i < 5 {
+			rawdb.WriteFinalizedBatchMeta(db, i, &rawdb.FinalizedBatchMeta{BatchHash: common.Hash{byte(i)}, TotalL1MessagePopped: i})
+		}
+	}
+	rawdb.WriteFinalizedL2BlockNumber(db, 49)
+
+	if err := ExportRollup(db, f); err != nil {
+		t.Fatal(err)
+	}
+	imported := rawdb.NewMemoryDatabase()
+	if err := ImportRollup(imported, f); err != nil {
+		t.Fatal(err)
+	}
+	if have := rawdb.ReadL1MessagesFrom(imported, 0, 100); !reflect.DeepEqual(have, rawdb.ReadL1MessagesFrom(db, 0, 100)) {
+		t.Fatalf("L1 messages mismatch: have %v", have)
+	}
+	if have := rawdb.ReadHighestSyncedQueueIndex(imported); have != 9 {
+		t.Fatalf("highest queue index mismatch: have %d, want 9", have)
+	}
+	if have := rawdb.ReadSyncedL1BlockNumber(imported); have == nil || *have != 100 {
+		t.Fatalf("synced L1 block mismatch: have %v, want 100", have)
+	}
+	if have := rawdb.ReadRollupEventSyncedL1BlockNumber(imported); have == nil || *have != 90 {
+		t.Fatalf("rollup event synced L1 block mismatch: have %v, want 90", have)
+	}
+	if have := rawdb.ReadFinalizedL2BlockNumber(imported); have == nil || *have != 49 {
+		t.Fatalf("finalized L2 block mismatch: have %v, want 49", have)
+	}
+	for i := uint64(0); i < 7; i++ {
+		if have, want := rawdb.ReadBatchChunkRanges(imported, i), rawdb.ReadBatchChunkRanges(db, i); !reflect.DeepEqual(have, want) {
+			t.Errorf("batch %d chunk ranges mismatch: have %v, want %v", i, have, want)
+		}
+		if have, want := rawdb.ReadFinalizedBatchMeta(imported, i), rawdb.ReadFinalizedBatchMeta(db, i); !reflect.DeepEqual(have, want) {
+			t.Errorf("batch %d metadata mismatch: have %v, want %v", i, have, want)
+		}
+	}
+	// Importing twice must be rejected
+	if err := ImportRollup(imported, f); err == nil {
+		t.Fatal("expected error importing into a database with rollup state")
+	}
+}
+
+// TestImportRollupCorrupted tests that corrupted rollup exports are rejected
+// without writing anything.
+func TestImportRollupCorrupted(t *testing.T) {
+	var (
+		f      = fmt.Sprintf("%v/rollupdump", t.TempDir())
+		hasher = crypto.NewKeccakState()
+		items  []*rollupExportItem
+	)
+	for _, index := range []uint64{0, 1, 3} {
+		data, _ := rlp.EncodeToBytes(types.L1MessageTx{QueueIndex: index, To: &common.Address{}, Value: big.NewInt(0)})
+		items = append(items, &rollupExportItem{Kind: rollupExportL1Message, Index: index, Data: data})
+	}
+	write := func(items []*rollupExportItem, checksum bool, header *rollupExportHeader) {
+		fh, err := os.Create(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer fh.Close()
+
+		hasher.Reset()
+		rlp.Encode(io.MultiWriter(fh, hasher), &rollupExportHeader{Magic: rollupExportMagic, SyncedL1Block: 1, HighestQueueIndex: items[len(items)-1].Index})
+		if header != nil {
+			// Replace the hashed header without updating the checksum
+			fh.Truncate(0)
+			fh.Seek(0, io.SeekStart)
+			rlp.Encode(fh, header)
+		}
+		for _, item := range items {
+			rlp.Encode(io.MultiWriter(fh, hasher), item)
+		}
+		if checksum {
+			var sum common.Hash
+			hasher.Read(sum[:])
+			rlp.Encode(fh, &rollupExportItem{Kind: rollupExportChecksum, Data: sum[:]})
+		}
+	}
+	tests := []struct {
+		items    []*rollupExportItem
+		checksum bool
+		err      string
+	}{
+		{items[:2], false, "truncated data"},
+		{items, true, "L1 message queue not contiguous"},
+	}
+	for i, tt := range tests {
+		write(tt.items, tt.checksum, nil)
+		db := rawdb.NewMemoryDatabase()
+		if err := ImportRollup(db, f); err == nil || !strings.HasPrefix(err.Error(), tt.err) {
+			t.Errorf("test %d: wrong error: %v", i, err)
+		}
+		if rawdb.ReadL1Message(db, 0) != nil || rawdb.ReadSyncedL1BlockNumber(db) != nil {
+			t.Errorf("test %d: corrupted export partially imported", i)
+		}
+	}
+	// A tampered item must fail the checksum
+	write(items[:2], true, nil)
+	data, _ := os.ReadFile(f)
+	data[len(data)-40] ^= 0xff
+	os.WriteFile(f, data, 0644)
+	if err := ImportRollup(rawdb.NewMemoryDatabase(), f); err == nil {
+		t.Error("expected error importing tampered export")
+	}
+	// A tampered header must fail the checksum as well
+	write(items[:2], true, &rollupExportHeader{Magic: rollupExportMagic, SyncedL1Block: 2, HighestQueueIndex: 1})
+	if err := ImportRollup(rawdb.NewMemoryDatabase(), f); err == nil || !strings.HasPrefix(err.Error(), "checksum mismatch") {
+		t.Errorf("wrong error importing tampered header: %v", err)
+	}
+}
+
+// TestExportRollupIncomplete tests that an L1 message queue ending before the
+// highest synced queue index is not exported.
+func TestExportRollupIncomplete(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	for i := uint64(0); i < 3; i++ {
+		rawdb.WriteL1Message(db, types.L1MessageTx{QueueIndex: i, To: &common.Address{}, Value: big.NewInt(0)})
+	}
+	rawdb.WriteHighestSyncedQueueIndex(db, 5)
+	rawdb.WriteSyncedL1BlockNumber(db, 100)
+
+	if err := ExportRollup(db, fmt.Sprintf("%v/rollupdump", t.TempDir())); err == nil || !strings.HasPrefix(err.Error(), "L1 message queue incomplete") {
+		t.Fatalf("wrong error exporting incomplete queue: %v", err)
+	}
+}
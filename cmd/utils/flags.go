@@ -25,6 +25,7 @@ import (
 	"io/ioutil"
 	"math"
 	"math/big"
+	"net/url"
 	"os"
 	"path/filepath"
 	godebug "runtime/debug"
@@ -73,6 +74,8 @@ import (
 	"github.com/scroll-tech/go-ethereum/p2p/nat"
 	"github.com/scroll-tech/go-ethereum/p2p/netutil"
 	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rollup/sequencer"
+	"github.com/scroll-tech/go-ethereum/rollup/sync_service"
 	"github.com/scroll-tech/go-ethereum/rollup/tracing"
 	"github.com/scroll-tech/go-ethereum/rpc"
 )
@@ -128,6 +131,10 @@ var (
 		Name:  "datadir.ancient",
 		Usage: "Data directory for ancient chain segments (default = inside chaindata)",
 	}
+	DBEngineFlag = cli.StringFlag{
+		Name:  "db.engine",
+		Usage: "Backing database implementation to use ('leveldb' or 'pebble', default = engine of the existing database or leveldb)",
+	}
 	MinFreeDiskSpaceFlag = DirectoryFlag{
 		Name:  "datadir.minfreedisk",
 		Usage: "Minimum free disk space in MB, once reached triggers auto shut down (default = --cache.gc converted to MB, 0 = disabled)",
@@ -254,6 +261,18 @@ var (
 		Usage: "Number of recent blocks to maintain transactions index for (default = about one year, 0 = entire chain)",
 		Value: ethconfig.Defaults.TxLookupLimit,
 	}
+	LogIndexFlag = cli.BoolFlag{
+		Name:  "logindex",
+		Usage: "Maintain an address/topic index of the logs for fast log filtering over large block ranges",
+	}
+	AccountHistoryFlag = cli.BoolFlag{
+		Name:  "accounthistory",
+		Usage: "Maintain an index of the transactions by account, including internal calls (eth_getTransactionsByAddress)",
+	}
+	BlockFeesFlag = cli.BoolFlag{
+		Name:  "blockfees",
+		Usage: "Persist the L2 execution, L1 data and burned base fees of every block (scroll_getFeeBreakdown)",
+	}
 	LightKDFFlag = cli.BoolFlag{
 		Name:  "lightkdf",
 		Usage: "Reduce key-derivation RAM & CPU usage at some expense of KDF strength",
@@ -406,6 +425,11 @@ var (
 		Usage: "Maximum amount of time non-executable transaction are queued",
 		Value: ethconfig.Defaults.TxPool.Lifetime,
 	}
+	TxPoolCircuitCheckFlag = cli.DurationFlag{
+		Name:  "txpool.circuitcheck",
+		Usage: "Interval of the circuit capacity check evicting pending transactions that can never be included (0 = disabled)",
+		Value: ethconfig.Defaults.TxPool.CircuitCheckInterval,
+	}
 	// Performance tuning settings
 	CacheFlag = cli.IntFlag{
 		Name:  "cache",
@@ -505,6 +529,23 @@ var (
 		Usage: "Maximum number of accounts that miner will fetch the pending transactions of when building a new block",
 		Value: math.MaxInt,
 	}
+	MinerLeaseFileFlag = cli.StringFlag{
+		Name:  "miner.lease.file",
+		Usage: "Path of the sequencer leader lease shared with standby sequencers (enables failover)",
+	}
+	MinerLeaseIDFlag = cli.StringFlag{
+		Name:  "miner.lease.id",
+		Usage: "Identifier of this node in the sequencer leader lease (default = hostname and process id)",
+	}
+	MinerLeaseTTLFlag = cli.DurationFlag{
+		Name:  "miner.lease.ttl",
+		Usage: "Time after which a standby sequencer takes over an unrenewed leader lease",
+		Value: sequencer.DefaultLeaseTTL,
+	}
+	MinerSignerFlag = cli.StringFlag{
+		Name:  "miner.signer",
+		Usage: "External signer (clef) endpoint that signs clique blocks with the etherbase key, instead of the local keystore",
+	}
 	// Account settings
 	UnlockedAccountFlag = cli.StringFlag{
 		Name:  "unlock",
@@ -643,6 +684,46 @@ var (
 		Usage: "HTTP path prefix on which JSON-RPC is served. Use '/' to serve on all paths.",
 		Value: "",
 	}
+	AuthListenFlag = cli.StringFlag{
+		Name:  "authrpc.addr",
+		Usage: "Listening address for the JWT-authenticated HTTP and WS-RPC server",
+		Value: node.DefaultAuthHost,
+	}
+	AuthPortFlag = cli.IntFlag{
+		Name:  "authrpc.port",
+		Usage: "Listening port for the JWT-authenticated HTTP and WS-RPC server",
+		Value: node.DefaultAuthPort,
+	}
+	AuthVirtualHostsFlag = cli.StringFlag{
+		Name:  "authrpc.vhosts",
+		Usage: "Comma separated list of virtual hostnames from which to accept requests to the authenticated server (server enforced). Accepts '*' wildcard.",
+		Value: strings.Join(node.DefaultConfig.AuthVirtualHosts, ","),
+	}
+	AuthApiFlag = cli.StringFlag{
+		Name:  "authrpc.api",
+		Usage: "API's offered over the authenticated RPC interface to JWTs without a role claim (roles are set in the config file)",
+		Value: "",
+	}
+	JWTSecretFlag = cli.StringFlag{
+		Name:  "authrpc.jwtsecret",
+		Usage: "Path to a hex-encoded JWT secret enabling the authenticated RPC server",
+		Value: "",
+	}
+	RPCBudgetRateFlag = cli.Float64Flag{
+		Name:  "rpc.budget.rate",
+		Usage: "Request budget refilled per second for every RPC client, expensive methods costing more than one (0=unlimited)",
+		Value: 0,
+	}
+	RPCBudgetBurstFlag = cli.IntFlag{
+		Name:  "rpc.budget.burst",
+		Usage: "Maximum request budget of every RPC client",
+		Value: 1000,
+	}
+	RPCSlowCallFlag = cli.DurationFlag{
+		Name:  "rpc.slowcall",
+		Usage: "Logs the RPC calls taking longer than this along with their parameters (0=disabled)",
+		Value: 0,
+	}
 	ExecFlag = cli.StringFlag{
 		Name:  "exec",
 		Usage: "Execute JavaScript statement",
@@ -821,11 +902,20 @@ var (
 		Name:  "catalyst",
 		Usage: "Catalyst mode (eth2 integration testing)",
 	}
+	ScrollEngineFlag = cli.BoolFlag{
+		Name:  "scroll.engine",
+		Usage: "Enable the Scroll engine API to let an external sequencer drive block production (experimental)",
+	}
 
 	// L1Settings
 	L1EndpointFlag = cli.StringFlag{
 		Name:  "l1.endpoint",
-		Usage: "Endpoint of L1 HTTP-RPC server",
+		Usage: "Endpoint of L1 HTTP-RPC server, or comma separated list of endpoints to fail over between",
+	}
+	L1QuorumFlag = cli.IntFlag{
+		Name:  "l1.quorum",
+		Usage: "Number of L1 endpoints that must return identical logs and headers",
+		Value: 1,
 	}
 	L1ConfirmationsFlag = cli.StringFlag{
 		Name:  "l1.confirmations",
@@ -1049,6 +1139,48 @@ func setHTTP(ctx *cli.Context, cfg *node.Config) {
 	}
 }
 
+// setAuthRPC configures the JWT-authenticated RPC listener from the set command
+// line flags. The listener is disabled unless a JWT secret is given.
+func setAuthRPC(ctx *cli.Context, cfg *node.Config) {
+	if ctx.GlobalIsSet(AuthListenFlag.Name) {
+		cfg.AuthAddr = ctx.GlobalString(AuthListenFlag.Name)
+	}
+	if ctx.GlobalIsSet(AuthPortFlag.Name) {
+		cfg.AuthPort = ctx.GlobalInt(AuthPortFlag.Name)
+	}
+	if ctx.GlobalIsSet(AuthVirtualHostsFlag.Name) {
+		cfg.AuthVirtualHosts = SplitAndTrim(ctx.GlobalString(AuthVirtualHostsFlag.Name))
+	}
+	if ctx.GlobalIsSet(AuthApiFlag.Name) {
+		cfg.AuthModules = SplitAndTrim(ctx.GlobalString(AuthApiFlag.Name))
+	}
+	if ctx.GlobalIsSet(JWTSecretFlag.Name) {
+		cfg.JWTSecret = ctx.GlobalString(JWTSecretFlag.Name)
+	}
+}
+
+// setRPCLimits configures the request budgets and the slow call logging of the
+// RPC servers from the set command line flags.
+func setRPCLimits(ctx *cli.Context, cfg *node.Config) {
+	if ctx.GlobalIsSet(RPCBudgetRateFlag.Name) {
+		if rate := ctx.GlobalFloat64(RPCBudgetRateFlag.Name); rate > 0 {
+			cfg.RPCBudget = &rpc.BudgetConfig{
+				Costs: node.DefaultRPCCosts,
+				Rate:  rate,
+				Burst: ctx.GlobalInt(RPCBudgetBurstFlag.Name),
+			}
+		} else {
+			cfg.RPCBudget = nil
+		}
+	}
+	if cfg.RPCBudget != nil && ctx.GlobalIsSet(RPCBudgetBurstFlag.Name) {
+		cfg.RPCBudget.Burst = ctx.GlobalInt(RPCBudgetBurstFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCSlowCallFlag.Name) {
+		cfg.RPCSlowCallThreshold = ctx.GlobalDuration(RPCSlowCallFlag.Name)
+	}
+}
+
 // setGraphQL creates the GraphQL listener interface string from the set
 // command line flags, returning empty if the GraphQL endpoint is disabled.
 func setGraphQL(ctx *cli.Context, cfg *node.Config) {
@@ -1292,8 +1424,11 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	setHTTP(ctx, cfg)
 	setGraphQL(ctx, cfg)
 	setWS(ctx, cfg)
+	setAuthRPC(ctx, cfg)
+	setRPCLimits(ctx, cfg)
 	setNodeUserIdent(ctx, cfg)
 	setDataDir(ctx, cfg)
+	setDBEngine(ctx, cfg)
 	setSmartCard(ctx, cfg)
 	setL1(ctx, cfg)
 
@@ -1355,6 +1490,9 @@ func setL1(ctx *cli.Context, cfg *node.Config) {
 	if ctx.GlobalIsSet(L1DeploymentBlockFlag.Name) {
 		cfg.L1DeploymentBlock = ctx.GlobalUint64(L1DeploymentBlockFlag.Name)
 	}
+	if ctx.GlobalIsSet(L1QuorumFlag.Name) {
+		cfg.L1Quorum = ctx.GlobalInt(L1QuorumFlag.Name)
+	}
 }
 
 func setSmartCard(ctx *cli.Context, cfg *node.Config) {
@@ -1377,6 +1515,15 @@ func setSmartCard(ctx *cli.Context, cfg *node.Config) {
 	cfg.SmartCardDaemonPath = path
 }
 
+func setDBEngine(ctx *cli.Context, cfg *node.Config) {
+	if ctx.GlobalIsSet(DBEngineFlag.Name) {
+		cfg.DBEngine = ctx.GlobalString(DBEngineFlag.Name)
+	}
+	if cfg.DBEngine != "" && cfg.DBEngine != rawdb.DBLeveldb && cfg.DBEngine != rawdb.DBPebble {
+		Fatalf("Invalid choice for db.engine '%s', allowed '%s' or '%s'", cfg.DBEngine, rawdb.DBLeveldb, rawdb.DBPebble)
+	}
+}
+
 func setDataDir(ctx *cli.Context, cfg *node.Config) {
 	switch {
 	case ctx.GlobalIsSet(DataDirFlag.Name):
@@ -1472,6 +1619,9 @@ func setTxPool(ctx *cli.Context, cfg *core.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.GlobalDuration(TxPoolLifetimeFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolCircuitCheckFlag.Name) {
+		cfg.CircuitCheckInterval = ctx.GlobalDuration(TxPoolCircuitCheckFlag.Name)
+	}
 }
 
 func setEthash(ctx *cli.Context, cfg *ethconfig.Config) {
@@ -1527,6 +1677,18 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 	if ctx.GlobalIsSet(MinerMaxAccountsNumFlag.Name) {
 		cfg.MaxAccountsNum = ctx.GlobalInt(MinerMaxAccountsNumFlag.Name)
 	}
+	if ctx.GlobalIsSet(MinerLeaseFileFlag.Name) {
+		cfg.LeaseFile = ctx.GlobalString(MinerLeaseFileFlag.Name)
+	}
+	if ctx.GlobalIsSet(MinerLeaseIDFlag.Name) {
+		cfg.LeaseID = ctx.GlobalString(MinerLeaseIDFlag.Name)
+	}
+	if ctx.GlobalIsSet(MinerLeaseTTLFlag.Name) {
+		cfg.LeaseTTL = ctx.GlobalDuration(MinerLeaseTTLFlag.Name)
+	}
+	if ctx.GlobalIsSet(MinerSignerFlag.Name) {
+		cfg.Signer = ctx.GlobalString(MinerSignerFlag.Name)
+	}
 	if ctx.GlobalIsSet(LegacyMinerGasTargetFlag.Name) {
 		log.Warn("The generic --miner.gastarget flag is deprecated and will be removed in the future!")
 	}
@@ -1696,6 +1858,15 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.GlobalIsSet(TxLookupLimitFlag.Name) {
 		cfg.TxLookupLimit = ctx.GlobalUint64(TxLookupLimitFlag.Name)
 	}
+	if ctx.GlobalIsSet(LogIndexFlag.Name) {
+		cfg.LogIndex = ctx.GlobalBool(LogIndexFlag.Name)
+	}
+	if ctx.GlobalIsSet(AccountHistoryFlag.Name) {
+		cfg.AccountHistory = ctx.GlobalBool(AccountHistoryFlag.Name)
+	}
+	if ctx.GlobalIsSet(BlockFeesFlag.Name) {
+		cfg.BlockFees = ctx.GlobalBool(BlockFeesFlag.Name)
+	}
 	if ctx.GlobalIsSet(CacheFlag.Name) || ctx.GlobalIsSet(CacheTrieFlag.Name) {
 		cfg.TrieCleanCache = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheTrieFlag.Name) / 100
 	}
@@ -1920,18 +2091,7 @@ func RegisterEthService(stack *node.Node, cfg *ethconfig.Config) (ethapi.Backend
 
 	// initialize L1 client for sync service
 	// note: we need to do this here to avoid circular dependency
-	l1EndpointUrl := stack.Config().L1Endpoint
-	var l1Client *ethclient.Client
-
-	if l1EndpointUrl != "" {
-		var err error
-		l1Client, err = ethclient.Dial(l1EndpointUrl)
-		if err != nil {
-			Fatalf("Unable to connect to L1 endpoint at %v: %v", l1EndpointUrl, err)
-		}
-
-		log.Info("Initialized L1 client", "endpoint", l1EndpointUrl)
-	}
+	l1Client := makeL1Client(stack.Config())
 
 	backend, err := eth.New(stack, cfg, l1Client)
 	if err != nil {
@@ -1948,6 +2108,47 @@ func RegisterEthService(stack *node.Node, cfg *ethconfig.Config) (ethapi.Backend
 	return backend.APIBackend, backend
 }
 
+// makeL1Client connects to the configured L1 endpoints. Multiple endpoints are
+// combined into a client failing over between them, which requires a quorum of
+// them to agree on logs and headers.
+func makeL1Client(cfg *node.Config) sync_service.EthClient {
+	if cfg.L1Endpoint == "" {
+		return nil
+	}
+	var (
+		clients []sync_service.EthClient
+		names   []string
+	)
+	for _, endpoint := range strings.Split(cfg.L1Endpoint, ",") {
+		endpoint = strings.TrimSpace(endpoint)
+		client, err := ethclient.Dial(endpoint)
+		if err != nil {
+			Fatalf("Unable to connect to L1 endpoint at %v: %v", endpoint, err)
+		}
+		// Only log the host, URLs of providers often contain API keys
+		name := fmt.Sprintf("#%d", len(clients))
+		if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+			name = u.Host
+		}
+		clients = append(clients, client)
+		names = append(names, name)
+	}
+	if len(clients) == 1 && cfg.L1Quorum <= 1 {
+		log.Info("Initialized L1 client", "endpoint", cfg.L1Endpoint)
+		return clients[0]
+	}
+	quorum := cfg.L1Quorum
+	if quorum == 0 {
+		quorum = 1
+	}
+	client, err := sync_service.NewMultiClient(clients, names, quorum, sync_service.DefaultL1RequestTimeout)
+	if err != nil {
+		Fatalf("Invalid L1 endpoints: %v", err)
+	}
+	log.Info("Initialized L1 client", "endpoints", names, "quorum", quorum)
+	return client
+}
+
 // RegisterEthStatsService configures the Ethereum Stats daemon and adds it to
 // the given node.
 func RegisterEthStatsService(stack *node.Node, backend ethapi.Backend, url string) {
@@ -35,6 +35,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/common/hexutil"
 	"github.com/scroll-tech/go-ethereum/consensus"
 	"github.com/scroll-tech/go-ethereum/consensus/misc"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
 	"github.com/scroll-tech/go-ethereum/core/state"
 	"github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/crypto"
@@ -138,8 +139,34 @@ var (
 	// errRecentlySigned is returned if a header is signed by an authorized entity
 	// that already signed a header recently, thus is temporarily not allowed to.
 	errRecentlySigned = errors.New("recently signed")
+
+	// errSignedHeight is returned if the local signer is asked to seal a block at
+	// a height it already signed a different header for.
+	errSignedHeight = errors.New("already signed a block at this height")
 )
 
+// lastSignedPrefix + signer address -> lastSigned
+var lastSignedPrefix = []byte("clique-last-signed-")
+
+// lastSigned is the header most recently signed by a local signer, persisted to
+// never sign two different headers at the same height, e.g. after a restart.
+type lastSigned struct {
+	Number   uint64
+	SealHash common.Hash
+	Hash     common.Hash // Hash of the signed header, to check whether it was committed
+}
+
+// SealGuard decides whether the local signer may seal a block, e.g. because it
+// holds the sequencer leader lease.
+type SealGuard interface {
+	// AllowSeal returns an error if a block with the given number must not be sealed.
+	AllowSeal(number uint64) error
+
+	// Sealed is called after a block with the given number has been signed,
+	// before it is released. An error prevents the release.
+	Sealed(number uint64) error
+}
+
 // SignerFn hashes and signs the data to be signed by a backing account.
 type SignerFn func(signer accounts.Account, mimeType string, message []byte) ([]byte, error)
 
@@ -181,6 +208,7 @@ type Clique struct {
 
 	signer common.Address // Ethereum address of the signing key
 	signFn SignerFn       // Signer function to authorize hashes with
+	guard  SealGuard      // Optional guard deciding whether sealing is allowed
 	lock   sync.RWMutex   // Protects the signer and proposals fields
 
 	// The fields below are for testing only
@@ -594,6 +622,39 @@ func (c *Clique) Authorize(signer common.Address, signFn SignerFn) {
 	c.signFn = signFn
 }
 
+// SetSealGuard sets a guard that is consulted before sealing every block.
+func (c *Clique) SetSealGuard(guard SealGuard) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.guard = guard
+}
+
+// readLastSigned retrieves the header most recently signed by signer.
+func (c *Clique) readLastSigned(signer common.Address) (*lastSigned, error) {
+	blob, err := c.db.Get(append(lastSignedPrefix, signer.Bytes()...))
+	if err != nil {
+		if rawdb.IsNotFoundErr(err) {
+			return nil, nil // Nothing signed yet
+		}
+		return nil, err
+	}
+	last := new(lastSigned)
+	if err := rlp.DecodeBytes(blob, last); err != nil {
+		return nil, err
+	}
+	return last, nil
+}
+
+// writeLastSigned stores the header most recently signed by signer.
+func (c *Clique) writeLastSigned(signer common.Address, last *lastSigned) error {
+	blob, err := rlp.EncodeToBytes(last)
+	if err != nil {
+		return err
+	}
+	return c.db.Put(append(lastSignedPrefix, signer.Bytes()...), blob)
+}
+
 // Seal implements consensus.Engine, attempting to create a sealed block using
 // the local signing credentials.
 func (c *Clique) Seal(chain consensus.ChainHeaderReader, block *types.Block, results chan<- *types.Block, stop <-chan struct{}) error {
@@ -610,7 +671,7 @@ func (c *Clique) Seal(chain consensus.ChainHeaderReader, block *types.Block, res
 	}
 	// Don't hold the signer fields for the entire sealing procedure
 	c.lock.RLock()
-	signer, signFn := c.signer, c.signFn
+	signer, signFn, guard := c.signer, c.signFn, c.guard
 	c.lock.RUnlock()
 
 	// Bail out if we're unauthorized to sign a block
@@ -639,12 +700,41 @@ func (c *Clique) Seal(chain consensus.ChainHeaderReader, block *types.Block, res
 
 		log.Trace("Out-of-turn signing requested", "wiggle", common.PrettyDuration(wiggle))
 	}
+	// Never sign two different headers at the same height. A signed header that
+	// was never committed to the local chain was never released either, so it
+	// may be replaced, e.g. when the previous attempt failed to be written.
+	sealHash := SealHash(header)
+	last, err := c.readLastSigned(signer)
+	if err != nil {
+		return err
+	}
+	if last != nil {
+		switch {
+		case number < last.Number:
+			return fmt.Errorf("%w: number %d, last signed %d", errSignedHeight, number, last.Number)
+		case number == last.Number && sealHash != last.SealHash && chain.GetHeader(last.Hash, last.Number) != nil:
+			return fmt.Errorf("%w: number %d, committed %x", errSignedHeight, number, last.Hash)
+		}
+	}
+	if guard != nil {
+		if err := guard.AllowSeal(number); err != nil {
+			return err
+		}
+	}
 	// Sign all the things!
 	sighash, err := signFn(accounts.Account{Address: signer}, accounts.MimetypeClique, CliqueRLP(header))
 	if err != nil {
 		return err
 	}
 	copy(header.Extra[len(header.Extra)-extraSeal:], sighash)
+	if err := c.writeLastSigned(signer, &lastSigned{Number: number, SealHash: sealHash, Hash: header.Hash()}); err != nil {
+		return err
+	}
+	if guard != nil {
+		if err := guard.Sealed(number); err != nil {
+			return err
+		}
+	}
 	// Wait until sealing is terminated or delay timeout.
 	log.Trace("Waiting for slot to sign and propagate", "delay", common.PrettyDuration(delay))
 	go func() {
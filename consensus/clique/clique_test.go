@@ -17,15 +17,20 @@
 package clique
 
 import (
+	"bytes"
+	"errors"
 	"math/big"
 	"testing"
+	"time"
 
+	"github.com/scroll-tech/go-ethereum/accounts"
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/core"
 	"github.com/scroll-tech/go-ethereum/core/rawdb"
 	"github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/core/vm"
 	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/ethdb"
 	"github.com/scroll-tech/go-ethereum/params"
 )
 
@@ -125,3 +130,120 @@ func TestSealHash(t *testing.T) {
 		t.Errorf("have %x, want %x", have, want)
 	}
 }
+
+type testSealGuard struct {
+	allow  error
+	sealed []uint64
+}
+
+func (g *testSealGuard) AllowSeal(number uint64) error { return g.allow }
+
+func (g *testSealGuard) Sealed(number uint64) error {
+	g.sealed = append(g.sealed, number)
+	return nil
+}
+
+// Tests that a signer never seals two different headers at the same height and
+// that the seal guard is consulted before signing.
+func TestSealSignedHeight(t *testing.T) {
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		key, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		config = *params.AllCliqueProtocolChanges.Clique
+	)
+	config.Period = 1
+	engine := New(&config, db)
+	engine.Authorize(addr, func(account accounts.Account, mimeType string, message []byte) ([]byte, error) {
+		return crypto.Sign(crypto.Keccak256(message), key)
+	})
+	genspec := &core.Genesis{
+		ExtraData: make([]byte, extraVanity+common.AddressLength+extraSeal),
+		BaseFee:   big.NewInt(params.InitialBaseFee),
+	}
+	copy(genspec.ExtraData[extraVanity:], addr[:])
+	genesis := genspec.MustCommit(db)
+
+	chain, _ := core.NewBlockChain(db, nil, params.AllCliqueProtocolChanges, engine, vm.Config{}, nil, nil)
+	defer chain.Stop()
+
+	seal := func(time uint64) (*types.Block, error) {
+		header := &types.Header{
+			ParentHash: genesis.Hash(),
+			Number:     big.NewInt(1),
+			Difficulty: diffInTurn,
+			Extra:      make([]byte, extraVanity+extraSeal),
+			Time:       time,
+		}
+		results := make(chan *types.Block, 1)
+		if err := engine.Seal(chain, types.NewBlockWithHeader(header), results, make(chan struct{})); err != nil {
+			return nil, err
+		}
+		return <-results, nil
+	}
+	restart := func() {
+		engine = New(&config, db)
+		engine.Authorize(addr, func(account accounts.Account, mimeType string, message []byte) ([]byte, error) {
+			return crypto.Sign(crypto.Keccak256(message), key)
+		})
+	}
+	past := uint64(time.Now().Unix()) - 10
+
+	// A refusing guard must prevent signing altogether
+	guard := &testSealGuard{allow: errors.New("not leader")}
+	engine.SetSealGuard(guard)
+	if _, err := seal(past); err != guard.allow {
+		t.Fatalf("seal error mismatch: have %v, want %v", err, guard.allow)
+	}
+	guard.allow = nil
+	if _, err := seal(past); err != nil {
+		t.Fatalf("failed to seal block: %v", err)
+	}
+	if len(guard.sealed) != 1 || guard.sealed[0] != 1 {
+		t.Fatalf("sealed heights mismatch: have %v, want [1]", guard.sealed)
+	}
+	// Until the signed header is committed, a different one may replace it at
+	// the same height, also after a restart
+	if _, err := seal(past + 1); err != nil {
+		t.Fatalf("failed to replace uncommitted block: %v", err)
+	}
+	restart()
+	block, err := seal(past + 2)
+	if err != nil {
+		t.Fatalf("failed to replace uncommitted block after restart: %v", err)
+	}
+	// Once committed, only the same header may be sealed again at that height
+	rawdb.WriteHeader(db, block.Header())
+	if _, err := seal(past + 2); err != nil {
+		t.Fatalf("failed to re-seal committed block: %v", err)
+	}
+	if _, err := seal(past + 3); !errors.Is(err, errSignedHeight) {
+		t.Fatalf("seal error mismatch: have %v, want %v", err, errSignedHeight)
+	}
+	restart()
+	if _, err := seal(past + 3); !errors.Is(err, errSignedHeight) {
+		t.Fatalf("seal error after restart mismatch: have %v, want %v", err, errSignedHeight)
+	}
+	// Database failures must not be mistaken for an empty signing history
+	dbErr := errors.New("database failure")
+	engine = New(&config, &failingGetDB{Database: db, err: dbErr})
+	engine.Authorize(addr, func(account accounts.Account, mimeType string, message []byte) ([]byte, error) {
+		return crypto.Sign(crypto.Keccak256(message), key)
+	})
+	if _, err := seal(past + 3); err != dbErr {
+		t.Fatalf("seal error mismatch: have %v, want %v", err, dbErr)
+	}
+}
+
+// failingGetDB is a database failing all reads of the last signed header.
+type failingGetDB struct {
+	ethdb.Database
+	err error
+}
+
+func (db *failingGetDB) Get(key []byte) ([]byte, error) {
+	if bytes.HasPrefix(key, lastSignedPrefix) {
+		return nil, db.err
+	}
+	return db.Database.Get(key)
+}
@@ -20,12 +20,17 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/math"
 	"github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/params"
 )
 
-// Protocol-enforced maximum L2 base fee.
+// Default protocol-enforced maximum L2 base fee.
 // We would only go above this if L1 base fee hits 700 Gwei.
+//
+// Deprecated: the maximum is configurable per fork, use
+// ChainConfig.Scroll.L2BaseFeeConfigAt(number).MaxBaseFee instead.
 const MaximumL2BaseFee = 10000000000
 
 // VerifyEip1559Header verifies some header attributes which were changed in EIP-1559,
@@ -43,30 +48,74 @@ func VerifyEip1559Header(config *params.ChainConfig, parent, header *types.Heade
 	// note: we do not verify L2 base fee, the sequencer has the
 	// right to set any base fee below the maximum. L2 base fee
 	// is not subject to L2 consensus or zk verification.
-	if header.BaseFee.Cmp(big.NewInt(MaximumL2BaseFee)) > 0 {
-		return fmt.Errorf("invalid baseFee: have %s, maximum %d", header.BaseFee, MaximumL2BaseFee)
+	// The maximum is protocol-enforced and can only be changed
+	// through ScrollConfig.L2BaseFee.
+	if maxBaseFee := config.Scroll.L2BaseFeeConfigAt(header.Number).MaxBaseFee; header.BaseFee.Cmp(maxBaseFee) > 0 {
+		return fmt.Errorf("invalid baseFee: have %s, maximum %d", header.BaseFee, maxBaseFee)
 	}
 	return nil
 }
 
-// CalcBaseFee calculates the basefee of the header.
+// CalcBaseFee calculates the basefee of the header, using the L2 base fee
+// parameters scheduled for it in the chain config. The parent is nil when
+// calculating the base fee of the genesis block.
 func CalcBaseFee(config *params.ChainConfig, parent *types.Header, parentL1BaseFee *big.Int) *big.Int {
-	l2SequencerFee := big.NewInt(10000000) // 0.01 Gwei
-	provingFee := big.NewInt(140000000)    // 0.14 Gwei
+	number := common.Big0
+	if parent != nil {
+		number = new(big.Int).Add(parent.Number, common.Big1)
+	}
+	cfg := config.Scroll.L2BaseFeeConfigAt(number)
 
-	// L1_base_fee * 0.014
-	verificationFee := parentL1BaseFee
-	verificationFee = new(big.Int).Mul(verificationFee, big.NewInt(14))
-	verificationFee = new(big.Int).Div(verificationFee, big.NewInt(1000))
+	// L1_base_fee * l1BaseFeeScalar
+	verificationFee := new(big.Int).Mul(parentL1BaseFee, cfg.L1BaseFeeScalar)
+	verificationFee.Div(verificationFee, big.NewInt(params.L2BaseFeePrecision))
 
 	baseFee := big.NewInt(0)
-	baseFee.Add(baseFee, l2SequencerFee)
-	baseFee.Add(baseFee, provingFee)
+	baseFee.Add(baseFee, cfg.SequencerFee)
+	baseFee.Add(baseFee, cfg.ProvingFee)
 	baseFee.Add(baseFee, verificationFee)
 
-	if baseFee.Cmp(big.NewInt(MaximumL2BaseFee)) > 0 {
-		baseFee = big.NewInt(MaximumL2BaseFee)
+	if cfg.CongestionPricing() && parent != nil && parent.BaseFee != nil {
+		if congestionFee := calcCongestionBaseFee(parent, cfg); congestionFee.Cmp(baseFee) > 0 {
+			baseFee = congestionFee
+		}
+	}
+
+	if baseFee.Cmp(cfg.MaxBaseFee) > 0 {
+		baseFee = new(big.Int).Set(cfg.MaxBaseFee)
 	}
 
 	return baseFee
 }
+
+// calcCongestionBaseFee applies the EIP-1559 update rule to the parent's base
+// fee: it increases if the parent used more than its gas target and decreases
+// if it used less.
+func calcCongestionBaseFee(parent *types.Header, cfg params.L2BaseFeeConfig) *big.Int {
+	parentGasTarget := parent.GasLimit / *cfg.ElasticityMultiplier
+	if parentGasTarget == 0 || parent.GasUsed == parentGasTarget {
+		return new(big.Int).Set(parent.BaseFee)
+	}
+	var (
+		num   = new(big.Int)
+		denom = new(big.Int)
+	)
+	if parent.GasUsed > parentGasTarget {
+		// max(1, parentBaseFee * gasUsedDelta / parentGasTarget / baseFeeChangeDenominator)
+		num.SetUint64(parent.GasUsed - parentGasTarget)
+		num.Mul(num, parent.BaseFee)
+		num.Div(num, denom.SetUint64(parentGasTarget))
+		num.Div(num, denom.SetUint64(cfg.BaseFeeChangeDenominator))
+		baseFeeDelta := math.BigMax(num, common.Big1)
+
+		return num.Add(parent.BaseFee, baseFeeDelta)
+	}
+	// max(0, parentBaseFee - parentBaseFee * gasUsedDelta / parentGasTarget / baseFeeChangeDenominator)
+	num.SetUint64(parentGasTarget - parent.GasUsed)
+	num.Mul(num, parent.BaseFee)
+	num.Div(num, denom.SetUint64(parentGasTarget))
+	num.Div(num, denom.SetUint64(cfg.BaseFeeChangeDenominator))
+	baseFee := num.Sub(parent.BaseFee, num)
+
+	return math.BigMax(baseFee, common.Big0)
+}
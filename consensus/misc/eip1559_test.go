@@ -125,3 +125,109 @@ func TestCalcBaseFee(t *testing.T) {
 		}
 	}
 }
+
+// TestCalcBaseFeeVectors checks the L2 base fee model against configured,
+// fork-scheduled parameters and the optional congestion component.
+func TestCalcBaseFeeVectors(t *testing.T) {
+	elasticity, disabled := uint64(2), uint64(0)
+	cfg := config()
+	cfg.Scroll.L2BaseFee = []*params.L2BaseFeeConfig{
+		{
+			// custom static fees from block 10
+			Block:           big.NewInt(10),
+			SequencerFee:    big.NewInt(20000000),
+			ProvingFee:      big.NewInt(0),
+			L1BaseFeeScalar: big.NewInt(1000000000), // 1.0
+			MaxBaseFee:      big.NewInt(5000000000),
+		},
+		{
+			// congestion component from block 20, inheriting the custom fees
+			Block:                big.NewInt(20),
+			ElasticityMultiplier: &elasticity,
+		},
+		{
+			// congestion component disabled again from block 30, new max
+			Block:                big.NewInt(30),
+			MaxBaseFee:           big.NewInt(8000000000),
+			ElasticityMultiplier: &disabled,
+		},
+	}
+	tests := []struct {
+		parentNumber    int64
+		parentGasLimit  uint64
+		parentGasUsed   uint64
+		parentBaseFee   int64
+		parentL1BaseFee int64
+		expected        int64
+	}{
+		// default parameters before the first scheduled config
+		{8, 10000000, 10000000, 150000000, 1000000000, 164000000},
+		// custom parameters
+		{9, 10000000, 10000000, 150000000, 0, 20000000},
+		{9, 10000000, 10000000, 150000000, 1000000000, 1020000000},
+		{15, 10000000, 0, 150000000, 1000000000, 1020000000},
+		{15, 10000000, 0, 150000000, 6000000000, 5000000000}, // cap at custom max
+		// congestion: parent at target keeps the parent base fee
+		{19, 10000000, 5000000, 200000000, 0, 200000000},
+		// congestion: full parent raises the base fee by 1/8
+		{19, 10000000, 10000000, 200000000, 0, 225000000},
+		// congestion: empty parent lowers the base fee by 1/8
+		{19, 10000000, 0, 200000000, 0, 175000000},
+		// congestion never drops below the static formula
+		{19, 10000000, 0, 150000000, 0, 131250000},
+		{19, 10000000, 0, 150000000, 1000000000, 1020000000},
+		// congestion is capped at the inherited max
+		{19, 10000000, 10000000, 10000000000, 0, 5000000000},
+		// static fees only, inherited parameters with the new max
+		{29, 10000000, 10000000, 200000000, 0, 20000000},
+		{29, 10000000, 10000000, 200000000, 6000000000, 6020000000},
+		{29, 10000000, 10000000, 200000000, 9000000000, 8000000000},
+	}
+	for i, test := range tests {
+		parent := &types.Header{
+			Number:   big.NewInt(test.parentNumber),
+			GasLimit: test.parentGasLimit,
+			GasUsed:  test.parentGasUsed,
+			BaseFee:  big.NewInt(test.parentBaseFee),
+		}
+		if have, want := CalcBaseFee(cfg, parent, big.NewInt(test.parentL1BaseFee)), big.NewInt(test.expected); have.Cmp(want) != 0 {
+			t.Errorf("test %d: have %d  want %d, ", i, have, want)
+		}
+	}
+}
+
+// TestVerifyEip1559HeaderMaxBaseFee checks that the configured maximum L2 base
+// fee is enforced.
+func TestVerifyEip1559HeaderMaxBaseFee(t *testing.T) {
+	cfg := config()
+	cfg.Scroll.L2BaseFee = []*params.L2BaseFeeConfig{{Block: big.NewInt(10), MaxBaseFee: big.NewInt(params.GWei)}}
+
+	for i, tc := range []struct {
+		number  int64
+		baseFee int64
+		ok      bool
+	}{
+		{9, 10000000000, true},
+		{9, 10000000001, false},
+		{10, 1000000000, true},
+		{10, 1000000001, false},
+	} {
+		parent := &types.Header{GasLimit: 10000000, Number: big.NewInt(tc.number - 1)}
+		header := &types.Header{GasLimit: 10000000, Number: big.NewInt(tc.number), BaseFee: big.NewInt(tc.baseFee)}
+		err := VerifyEip1559Header(cfg, parent, header)
+		if tc.ok && err != nil {
+			t.Errorf("test %d: Expected valid header: %s", i, err)
+		}
+		if !tc.ok && err == nil {
+			t.Errorf("test %d: Expected invalid header", i)
+		}
+	}
+}
+
+// TestDefaultMaxBaseFee checks that the deprecated maximum L2 base fee matches
+// the default of the schedule.
+func TestDefaultMaxBaseFee(t *testing.T) {
+	if have, want := params.DefaultL2MaxBaseFee, big.NewInt(MaximumL2BaseFee); have.Cmp(want) != 0 {
+		t.Fatalf("default max base fee mismatch: have %v, want %v", have, want)
+	}
+}
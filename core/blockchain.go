@@ -45,6 +45,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/scroll-tech/go-ethereum/metrics"
 	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rollup/fees"
 	"github.com/scroll-tech/go-ethereum/trie"
 	"github.com/scroll-tech/go-ethereum/trie/zkproof"
 )
@@ -136,6 +137,7 @@ type CacheConfig struct {
 	SnapshotLimit       int           // Memory allowance (MB) to use for caching snapshot entries in memory
 	Preimages           bool          // Whether to store preimage of trie key to the disk
 	MPTWitness          int           // How to generate witness data for mpt circuit, 0: nothing, 1: natural
+	BlockFees           bool          // Whether to store the fee accounting of every block
 
 	SnapshotWait bool // Wait for snapshot construction on startup. TODO(karalabe): This is a dirty hack for testing, nuke it
 }
@@ -186,8 +188,10 @@ type BlockChain struct {
 	chainFeed     event.Feed
 	chainSideFeed event.Feed
 	chainHeadFeed event.Feed
+	reorgFeed     event.Feed
 	logsFeed      event.Feed
 	blockProcFeed event.Feed
+	skippedTxFeed event.Feed
 	scope         event.SubscriptionScope
 	genesisBlock  *types.Block
 
@@ -766,6 +770,7 @@ func (bc *BlockChain) writeHeadBlock(block *types.Block) {
 	batch := bc.db.NewBatch()
 	rawdb.WriteCanonicalHash(batch, block.Hash(), block.NumberU64())
 	rawdb.WriteTxLookupEntriesByBlock(batch, block)
+	rawdb.WriteL1MessageLookupEntriesByBlock(batch, block)
 	rawdb.WriteHeadBlockHash(batch, block.Hash())
 
 	// If the block is better than our head or is on a different chain, force update heads
@@ -787,6 +792,48 @@ func (bc *BlockChain) writeHeadBlock(block *types.Block) {
 	headBlockGauge.Update(int64(block.NumberU64()))
 }
 
+// WriteSkippedTransaction stores a transaction skipped by the local sequencer
+// along with the reason it was skipped for, and notifies the subscribers of
+// SkippedTxEvent.
+func (bc *BlockChain) WriteSkippedTransaction(tx *types.Transaction, traces *types.BlockTrace, reason string, blockNumber uint64, blockHash *common.Hash) {
+	rawdb.WriteSkippedTransaction(bc.db, tx, traces, reason, blockNumber, blockHash)
+	bc.skippedTxFeed.Send(SkippedTxEvent{Tx: tx, Reason: reason, BlockNumber: blockNumber, BlockHash: blockHash})
+}
+
+// postSkippedL1Messages notifies the subscribers of SkippedTxEvent of the L1
+// messages skipped by a block written to the canonical chain. The messages are
+// recorded as skipped when validating the block, which may be retried or fail,
+// so only the records of the given block are notified.
+func (bc *BlockChain) postSkippedL1Messages(block *types.Block) {
+	if !block.ContainsL1Messages() {
+		return
+	}
+	first := rawdb.ReadFirstQueueIndexNotInL2Block(bc.db, block.ParentHash())
+	if first == nil {
+		return
+	}
+	hash := block.Hash()
+	queueIndex := *first
+	for _, tx := range block.Transactions() {
+		msg := tx.AsL1MessageTx()
+		if msg == nil {
+			break
+		}
+		for ; queueIndex < msg.QueueIndex; queueIndex++ {
+			l1msg := rawdb.ReadL1Message(bc.db, queueIndex)
+			if l1msg == nil {
+				continue
+			}
+			stx := rawdb.ReadSkippedTransaction(bc.db, types.NewTx(l1msg).Hash())
+			if stx == nil || stx.BlockHash == nil || *stx.BlockHash != hash {
+				continue
+			}
+			bc.skippedTxFeed.Send(SkippedTxEvent{Tx: stx.Tx, Reason: stx.Reason, BlockNumber: stx.BlockNumber, BlockHash: stx.BlockHash})
+		}
+		queueIndex = msg.QueueIndex + 1
+	}
+}
+
 // Stop stops the blockchain service. If any imports are currently in progress
 // it will abort them using the procInterrupt.
 func (bc *BlockChain) Stop() {
@@ -1016,6 +1063,7 @@ func (bc *BlockChain) InsertReceiptChain(blockChain types.Blocks, receiptChain [
 			} else if rawdb.ReadTxIndexTail(bc.db) != nil {
 				rawdb.WriteTxLookupEntriesByBlock(batch, block)
 			}
+			rawdb.WriteL1MessageLookupEntriesByBlock(batch, block)
 			stats.processed++
 		}
 
@@ -1099,6 +1147,7 @@ func (bc *BlockChain) InsertReceiptChain(blockChain types.Blocks, receiptChain [
 			rawdb.WriteBody(batch, block.Hash(), block.NumberU64(), block.Body())
 			rawdb.WriteReceipts(batch, block.Hash(), block.NumberU64(), receiptChain[i])
 			rawdb.WriteTxLookupEntriesByBlock(batch, block) // Always write tx indices for live blocks, we assume they are needed
+			rawdb.WriteL1MessageLookupEntriesByBlock(batch, block)
 
 			// Write everything belongs to the blocks into the database. So that
 			// we can ensure all components of body is completed(body, receipts,
@@ -1280,6 +1329,9 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 	rawdb.WriteTd(blockBatch, block.Hash(), block.NumberU64(), externTd)
 	rawdb.WriteBlock(blockBatch, block)
 	rawdb.WriteReceipts(blockBatch, block.Hash(), block.NumberU64(), receipts)
+	if bc.cacheConfig.BlockFees {
+		rawdb.WriteBlockFees(blockBatch, block.Hash(), fees.CalculateBlockFees(bc.chainConfig, block.Header(), block.Transactions(), receipts))
+	}
 	rawdb.WritePreimages(blockBatch, state.Preimages())
 
 	queueIndex := rawdb.ReadFirstQueueIndexNotInL2Block(bc.db, block.ParentHash())
@@ -1411,6 +1463,7 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 
 	if status == CanonStatTy {
 		bc.chainFeed.Send(ChainEvent{Block: block, Hash: block.Hash(), Logs: logs})
+		bc.postSkippedL1Messages(block)
 		if len(logs) > 0 {
 			bc.logsFeed.Send(logs)
 		}
@@ -2065,6 +2118,9 @@ func (bc *BlockChain) reorg(oldBlock, newBlock *types.Block) error {
 	indexesBatch := bc.db.NewBatch()
 	for _, tx := range types.TxDifference(deletedTxs, addedTxs) {
 		rawdb.DeleteTxLookupEntry(indexesBatch, tx.Hash())
+		if tx.IsL1MessageTx() {
+			rawdb.DeleteL1MessageLookupEntry(indexesBatch, tx.L1MessageQueueIndex())
+		}
 	}
 	// Delete any canonical number assignments above the new head
 	number := bc.CurrentBlock().NumberU64()
@@ -2093,6 +2149,15 @@ func (bc *BlockChain) reorg(oldBlock, newBlock *types.Block) error {
 			bc.chainSideFeed.Send(ChainSideEvent{Block: oldChain[i]})
 		}
 	}
+	// Notify the blocks made canonical, except the new head notified by the caller
+	if len(newChain) > 1 {
+		reborn := make([]*types.Block, 0, len(newChain)-1)
+		for i := len(newChain) - 1; i >= 1; i-- {
+			reborn = append(reborn, newChain[i])
+			bc.postSkippedL1Messages(newChain[i])
+		}
+		bc.reorgFeed.Send(ChainReorgEvent{Blocks: reborn})
+	}
 	return nil
 }
 
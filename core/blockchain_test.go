@@ -3441,6 +3441,159 @@ func TestL1MessageValidationFailure(t *testing.T) {
 	assert.Equal(t, uint64(4), *queueIndex)
 }
 
+// TestSkippedL1MessageEvents tests that the L1 messages skipped by imported
+// blocks are notified once, when the skipping block becomes canonical.
+func TestSkippedL1MessageEvents(t *testing.T) {
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		engine = ethash.NewFaker()
+	)
+
+	// initialize genesis
+	config := params.AllEthashProtocolChanges
+	genspec := &Genesis{
+		Config:  config,
+		BaseFee: big.NewInt(params.InitialBaseFee),
+	}
+	genesis := genspec.MustCommit(db)
+
+	// initialize L1 message DB, message #3 is synced later
+	msgs := []types.L1MessageTx{
+		{QueueIndex: 0, Gas: 21016, To: &common.Address{1}, Data: []byte{0x01}, Sender: common.Address{2}},
+		{QueueIndex: 1, Gas: 21016, To: &common.Address{1}, Data: []byte{0x01}, Sender: common.Address{2}},
+		{QueueIndex: 2, Gas: 21016, To: &common.Address{1}, Data: []byte{0x01}, Sender: common.Address{2}},
+		{QueueIndex: 3, Gas: 21016, To: &common.Address{1}, Data: []byte{0x01}, Sender: common.Address{2}},
+	}
+	rawdb.WriteL1Messages(db, msgs[:3])
+
+	// initialize blockchain
+	blockchain, _ := NewBlockChain(db, nil, config, engine, vm.Config{}, nil, nil)
+	defer blockchain.Stop()
+
+	events := make(chan SkippedTxEvent, 10)
+	sub := blockchain.SubscribeSkippedTxEvent(events)
+	defer sub.Unsubscribe()
+
+	generateBlock := func(txs ...*types.Transaction) []*types.Block {
+		blocks, _ := GenerateChain(config, genesis, engine, db, 1, func(i int, b *BlockGen) {
+			for _, tx := range txs {
+				b.AddTxWithChain(blockchain, tx)
+			}
+		})
+		return blocks
+	}
+
+	// block skipping message #1, rejected for including an unknown message
+	unknown := types.L1MessageTx{QueueIndex: 2, Gas: 21016, To: &common.Address{1}, Data: []byte{0x02}, Sender: common.Address{2}}
+	_, err := blockchain.InsertChain(generateBlock(types.NewTx(&msgs[0]), types.NewTx(&unknown)))
+	assert.Equal(t, consensus.ErrUnknownL1Message, err)
+
+	// block skipping message #1, postponed until message #3 is synced
+	blocks := generateBlock(types.NewTx(&msgs[0]), types.NewTx(&msgs[2]), types.NewTx(&msgs[3]))
+	_, err = blockchain.InsertChain(blocks)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(0), blockchain.CurrentBlock().Number())
+	blockchain.procFutureBlocks()
+	assert.Equal(t, big.NewInt(0), blockchain.CurrentBlock().Number())
+
+	select {
+	case ev := <-events:
+		t.Fatalf("unexpected event before the block is canonical: %+v", ev)
+	default:
+	}
+
+	rawdb.WriteL1Message(db, msgs[3])
+	blockchain.procFutureBlocks()
+	assert.Equal(t, blocks[0].Hash(), blockchain.CurrentBlock().Hash())
+
+	select {
+	case ev := <-events:
+		assert.Equal(t, types.NewTx(&msgs[1]).Hash(), ev.Tx.Hash())
+		assert.Equal(t, uint64(1), ev.BlockNumber)
+		assert.Equal(t, blocks[0].Hash(), *ev.BlockHash)
+	default:
+		t.Fatal("missing event of the skipped message")
+	}
+	select {
+	case ev := <-events:
+		t.Fatalf("unexpected event: %+v", ev)
+	default:
+	}
+}
+
+func TestSkippedL1MessageEventsReorg(t *testing.T) {
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		engine = ethash.NewFaker()
+	)
+
+	// initialize genesis
+	config := params.AllEthashProtocolChanges
+	genspec := &Genesis{
+		Config:  config,
+		BaseFee: big.NewInt(params.InitialBaseFee),
+	}
+	genesis := genspec.MustCommit(db)
+
+	msgs := []types.L1MessageTx{
+		{QueueIndex: 0, Gas: 21016, To: &common.Address{1}, Data: []byte{0x01}, Sender: common.Address{2}},
+		{QueueIndex: 1, Gas: 21016, To: &common.Address{1}, Data: []byte{0x01}, Sender: common.Address{2}},
+		{QueueIndex: 2, Gas: 21016, To: &common.Address{1}, Data: []byte{0x01}, Sender: common.Address{2}},
+	}
+	rawdb.WriteL1Messages(db, msgs)
+
+	// initialize blockchain
+	blockchain, _ := NewBlockChain(db, nil, config, engine, vm.Config{}, nil, nil)
+	defer blockchain.Stop()
+
+	skipped := make(chan SkippedTxEvent, 10)
+	skippedSub := blockchain.SubscribeSkippedTxEvent(skipped)
+	defer skippedSub.Unsubscribe()
+
+	reorgs := make(chan ChainReorgEvent, 10)
+	reorgsSub := blockchain.SubscribeChainReorgEvent(reorgs)
+	defer reorgsSub.Unsubscribe()
+
+	// canonical chain without L1 messages
+	blocks, _ := GenerateChain(config, genesis, engine, db, 2, func(i int, b *BlockGen) {
+		b.SetCoinbase(common.Address{0xaa})
+	})
+	_, err := blockchain.InsertChain(blocks)
+	assert.NoError(t, err)
+
+	// longer side chain skipping message #1 in its first block
+	sideBlocks, _ := GenerateChain(config, genesis, engine, db, 3, func(i int, b *BlockGen) {
+		if i == 0 {
+			b.AddTxWithChain(blockchain, types.NewTx(&msgs[0]))
+			b.AddTxWithChain(blockchain, types.NewTx(&msgs[2]))
+		}
+	})
+	_, err = blockchain.InsertChain(sideBlocks)
+	assert.NoError(t, err)
+	assert.Equal(t, sideBlocks[2].Hash(), blockchain.CurrentBlock().Hash())
+
+	// the first block of the side chain is made canonical by the reorg
+	select {
+	case ev := <-reorgs:
+		assert.Equal(t, sideBlocks[0].Hash(), ev.Blocks[0].Hash())
+	default:
+		t.Fatal("missing reorg event")
+	}
+	select {
+	case ev := <-skipped:
+		assert.Equal(t, types.NewTx(&msgs[1]).Hash(), ev.Tx.Hash())
+		assert.Equal(t, uint64(1), ev.BlockNumber)
+		assert.Equal(t, sideBlocks[0].Hash(), *ev.BlockHash)
+	default:
+		t.Fatal("missing event of the skipped message")
+	}
+	select {
+	case ev := <-skipped:
+		t.Fatalf("unexpected event: %+v", ev)
+	default:
+	}
+}
+
 func TestBlockPayloadSizeLimit(t *testing.T) {
 	// Create config that allows at most 150 bytes per block payload
 	config := params.TestChainConfig
@@ -3789,3 +3942,43 @@ func TestCurieTransition(t *testing.T) {
 		}
 	}
 }
+
+func TestBlockFeesWrite(t *testing.T) {
+	var (
+		gendb   = rawdb.NewMemoryDatabase()
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		gspec   = &Genesis{
+			Config:  params.TestChainConfig,
+			Alloc:   GenesisAlloc{address: {Balance: big.NewInt(100000000000000000)}},
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		genesis = gspec.MustCommit(gendb)
+		signer  = types.LatestSigner(gspec.Config)
+	)
+	blocks, _ := GenerateChain(gspec.Config, genesis, ethash.NewFaker(), gendb, 1, func(i int, block *BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(block.TxNonce(address), common.Address{0x00}, big.NewInt(1000), params.TxGas, block.header.BaseFee, nil), signer, key)
+		if err != nil {
+			panic(err)
+		}
+		block.AddTx(tx)
+	})
+	// The fee accounting is only stored if enabled
+	for _, enabled := range []bool{false, true} {
+		db := rawdb.NewMemoryDatabase()
+		gspec.MustCommit(db)
+		cacheConfig := *defaultCacheConfig
+		cacheConfig.BlockFees = enabled
+		chain, err := NewBlockChain(db, &cacheConfig, gspec.Config, ethash.NewFaker(), vm.Config{}, nil, nil)
+		if err != nil {
+			t.Fatalf("failed to create tester chain: %v", err)
+		}
+		if _, err := chain.InsertChain(blocks); err != nil {
+			t.Fatalf("failed to insert chain: %v", err)
+		}
+		if fees := rawdb.ReadBlockFees(db, blocks[0].Hash()); (fees != nil) != enabled {
+			t.Errorf("enabled %v: block fees stored %v", enabled, fees != nil)
+		}
+		chain.Stop()
+	}
+}
@@ -19,6 +19,7 @@ package core
 import (
 	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -466,6 +467,47 @@ func (c *ChainIndexer) Prune(threshold uint64) error {
 	return c.backend.Prune(threshold)
 }
 
+// Rebuild processes all the sections of the canonical chain with enough
+// confirmations from scratch, overwriting the stored sections. The data of the
+// backend should be dropped beforehand. The indexer must not be started, and
+// the database not be in use by a running node.
+func (c *ChainIndexer) Rebuild(ctx context.Context) error {
+	head := rawdb.ReadHeadHeader(c.chainDb)
+	if head == nil {
+		return errors.New("head header not found")
+	}
+	var sections uint64
+	if number := head.Number.Uint64(); number >= c.confirmsReq {
+		sections = (number + 1 - c.confirmsReq) / c.sectionSize
+	}
+	var (
+		start    = time.Now()
+		logged   = start
+		lastHead common.Hash
+	)
+	for section := uint64(0); section < sections; section++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		newHead, err := c.processSection(section, lastHead)
+		if err != nil {
+			return err
+		}
+		c.lock.Lock()
+		c.setSectionHead(section, newHead)
+		c.setValidSections(section + 1)
+		c.lock.Unlock()
+		lastHead = newHead
+
+		if time.Since(logged) > 8*time.Second {
+			c.log.Info("Rebuilding chain index", "section", section+1, "sections", sections, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	c.log.Info("Rebuilt chain index", "sections", sections, "blocks", sections*c.sectionSize, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
 // loadValidSections reads the number of valid sections from the index database
 // and caches is into the local state.
 func (c *ChainIndexer) loadValidSections() {
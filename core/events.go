@@ -42,5 +42,19 @@ type ChainSideEvent struct {
 
 type ChainHeadEvent struct{ Block *types.Block }
 
+// ChainReorgEvent is posted when a chain reorg makes blocks canonical, ordered
+// by number. The new head block is not included, it is posted as a ChainEvent.
+type ChainReorgEvent struct{ Blocks []*types.Block }
+
 // NewL1MsgsEvent is posted when we receive some new messages from L1.
 type NewL1MsgsEvent struct{ Count int }
+
+// SkippedTxEvent is posted when a transaction is skipped, either by the local
+// sequencer or by an imported block skipping an L1 message, once the block is
+// written to the canonical chain.
+type SkippedTxEvent struct {
+	Tx          *types.Transaction
+	Reason      string
+	BlockNumber uint64
+	BlockHash   *common.Hash // Hash of the skipping block, unknown for the sequencer
+}
@@ -0,0 +1,193 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/bitutil"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/ethdb"
+)
+
+const (
+	// logIndexThrottling is the time to wait between processing two consecutive
+	// log index sections, to prevent disk overload while catching up.
+	logIndexThrottling = 100 * time.Millisecond
+
+	// maxLogIndexTopics is the maximum number of topics of a log.
+	maxLogIndexTopics = 4
+)
+
+// logIndexKey returns the key indexing the logs with the given value at the
+// given position: 0 for the address of a log, 1+i for its i-th topic.
+func logIndexKey(position int, value []byte) []byte {
+	return append([]byte{byte(position)}, value...)
+}
+
+// LogIndexer implements a core.ChainIndexer, building up an inverted index of
+// the blocks of every section containing logs emitted by a given address or with
+// a given topic at a given position. Unlike the bloom bits, the index is exact
+// for every address and topic, permitting fast filtering over long ranges of
+// blocks without retrieving the logs of unrelated blocks.
+type LogIndexer struct {
+	size    uint64              // section size to generate the index for
+	db      ethdb.Database      // database instance to write index data into
+	section uint64              // section number being processed currently
+	head    common.Hash         // hash of the last header processed
+	blocks  map[string][]uint32 // offsets of the blocks with matching logs by log key
+}
+
+// NewLogIndexer returns a chain indexer that generates the log index of the
+// canonical chain for fast logs filtering.
+func NewLogIndexer(db ethdb.Database, size, confirms uint64) *ChainIndexer {
+	backend := &LogIndexer{
+		db:   db,
+		size: size,
+	}
+	table := rawdb.NewTable(db, string(rawdb.LogIndexIndexPrefix))
+
+	return NewChainIndexer(db, table, backend, size, confirms, logIndexThrottling, "logindex")
+}
+
+// Reset implements core.ChainIndexerBackend, starting a new log index section.
+func (b *LogIndexer) Reset(ctx context.Context, section uint64, lastSectionHead common.Hash) error {
+	b.section, b.head, b.blocks = section, common.Hash{}, make(map[string][]uint32)
+	return nil
+}
+
+// Process implements core.ChainIndexerBackend, adding the logs of a new header
+// into the index.
+func (b *LogIndexer) Process(ctx context.Context, header *types.Header) error {
+	b.head = header.Hash()
+
+	// Blocks without logs have an empty bloom, skip reading their receipts
+	if header.Bloom == (types.Bloom{}) {
+		return nil
+	}
+	receipts := rawdb.ReadRawReceipts(b.db, b.head, header.Number.Uint64())
+	if receipts == nil {
+		return fmt.Errorf("receipts of block #%d [%x..] not found", header.Number, b.head[:4])
+	}
+	offset := uint32(header.Number.Uint64() - b.section*b.size)
+	for _, receipt := range receipts {
+		for _, l := range receipt.Logs {
+			b.add(logIndexKey(0, l.Address.Bytes()), offset)
+			for i, topic := range l.Topics {
+				b.add(logIndexKey(i+1, topic.Bytes()), offset)
+			}
+		}
+	}
+	return nil
+}
+
+// add records that the block at the given offset of the section contains a log
+// matching the given log key.
+func (b *LogIndexer) add(logKey []byte, offset uint32) {
+	offsets := b.blocks[string(logKey)]
+	if n := len(offsets); n > 0 && offsets[n-1] == offset {
+		return
+	}
+	b.blocks[string(logKey)] = append(offsets, offset)
+}
+
+// Commit implements core.ChainIndexerBackend, finalizing the log index section
+// and writing it out into the database.
+func (b *LogIndexer) Commit() error {
+	batch := b.db.NewBatch()
+	for logKey, offsets := range b.blocks {
+		vector := make([]byte, b.size/8)
+		for _, offset := range offsets {
+			vector[offset/8] |= 0x80 >> (offset % 8)
+		}
+		rawdb.WriteLogIndex(batch, []byte(logKey), b.section, b.head, bitutil.CompressBytes(vector))
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	return batch.Write()
+}
+
+// Prune returns an empty error since we don't support pruning here.
+func (b *LogIndexer) Prune(threshold uint64) error {
+	return nil
+}
+
+// ReadLogIndexMatches returns the vector of the blocks of an indexed section
+// containing logs matching the given addresses and topics, which follow the
+// rules of log filters. The head is the hash of the last block of the section.
+// Every block of the section matches if no criteria are given.
+func ReadLogIndexMatches(db ethdb.KeyValueReader, size, section uint64, head common.Hash, addresses []common.Address, topics [][]common.Hash) ([]byte, error) {
+	var matches []byte
+
+	// match restricts the matches to the blocks with a log having any of the
+	// given values at the given position, unless no values are given.
+	match := func(position int, values [][]byte) error {
+		if len(values) == 0 {
+			return nil
+		}
+		blocks := make([]byte, size/8)
+		for _, value := range values {
+			data := rawdb.ReadLogIndex(db, logIndexKey(position, value), section, head)
+			if len(data) == 0 {
+				continue
+			}
+			vector, err := bitutil.DecompressBytes(data, len(blocks))
+			if err != nil {
+				return err
+			}
+			bitutil.ORBytes(blocks, blocks, vector)
+		}
+		if matches == nil {
+			matches = blocks
+		} else {
+			bitutil.ANDBytes(matches, matches, blocks)
+		}
+		return nil
+	}
+	values := make([][]byte, len(addresses))
+	for i, address := range addresses {
+		values[i] = address.Bytes()
+	}
+	if err := match(0, values); err != nil {
+		return nil, err
+	}
+	for i, sub := range topics {
+		values := make([][]byte, len(sub))
+		for j, topic := range sub {
+			values[j] = topic.Bytes()
+		}
+		// Logs have no topics beyond the maximum, so these can never match
+		if i >= maxLogIndexTopics && len(values) > 0 {
+			return make([]byte, size/8), nil
+		}
+		if err := match(i+1, values); err != nil {
+			return nil, err
+		}
+	}
+	if matches == nil {
+		matches = make([]byte, size/8)
+		for i := range matches {
+			matches[i] = 0xff
+		}
+	}
+	return matches, nil
+}
+
+// RebuildLogIndex drops the log index and rebuilds it from scratch for all the
+// sections of the canonical chain with enough confirmations. The database must
+// not be in use by a running node.
+func RebuildLogIndex(ctx context.Context, db ethdb.Database, size, confirms uint64) error {
+	if err := rawdb.DeleteLogIndex(db); err != nil {
+		return err
+	}
+	indexer := NewLogIndexer(db, size, confirms)
+	defer indexer.Close()
+
+	return indexer.Rebuild(ctx)
+}
@@ -0,0 +1,182 @@
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/ethdb"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rlp"
+)
+
+// Roles of an account in a transaction, recorded by the account history index.
+const (
+	AccountRoleFrom     uint8 = 1 << iota // Sender of the transaction
+	AccountRoleTo                         // Recipient of the transaction
+	AccountRoleCreated                    // Contract created by the transaction
+	AccountRoleInternal                   // Caller or callee of an internal call
+)
+
+// AccountHistoryEntry is a transaction touching an account, along with the
+// roles of the account in the transaction.
+type AccountHistoryEntry struct {
+	BlockNumber uint64 `rlp:"-"`
+	TxIndex     uint32 `rlp:"-"`
+	BlockHash   common.Hash
+	TxHash      common.Hash
+	Roles       uint8
+}
+
+// WriteAccountHistoryEntry stores a transaction touching the given account.
+func WriteAccountHistoryEntry(db ethdb.KeyValueWriter, address common.Address, entry *AccountHistoryEntry) {
+	data, err := rlp.EncodeToBytes(entry)
+	if err != nil {
+		log.Crit("Failed to RLP encode account history entry", "address", address, "err", err)
+	}
+	if err := db.Put(accountHistoryKey(address, entry.BlockNumber, entry.TxIndex), data); err != nil {
+		log.Crit("Failed to store account history entry", "address", address, "err", err)
+	}
+}
+
+// ReadAccountHistory retrieves up to limit transactions touching the given
+// account in canonical blocks, from the newest to the oldest. The history starts
+// at the given block number and transaction index, inclusive, and ends at the
+// given oldest block. Entries of the blocks reorged out of the canonical chain
+// are skipped.
+func ReadAccountHistory(db ethdb.Database, address common.Address, number uint64, txIndex uint32, oldest uint64, limit int) []*AccountHistoryEntry {
+	prefix := accountHistoryKey(address, 0, 0)[:len(accountHistoryPrefix)+common.AddressLength]
+	start := accountHistoryKey(address, number, txIndex)[len(prefix):]
+	it := db.NewIterator(prefix, start)
+	defer it.Release()
+
+	var entries []*AccountHistoryEntry
+	for len(entries) < limit && it.Next() {
+		key := it.Key()
+		if len(key) != len(prefix)+12 {
+			continue
+		}
+		entry := new(AccountHistoryEntry)
+		if err := rlp.DecodeBytes(it.Value(), entry); err != nil {
+			log.Crit("Invalid account history entry RLP", "address", address, "err", err)
+		}
+		entry.BlockNumber = ^binary.BigEndian.Uint64(key[len(prefix):])
+		entry.TxIndex = ^binary.BigEndian.Uint32(key[len(prefix)+8:])
+		if entry.BlockNumber < oldest {
+			break
+		}
+		if ReadCanonicalHash(db, entry.BlockNumber) != entry.BlockHash {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := it.Error(); err != nil {
+		log.Crit("Failed to read account history", "address", address, "err", err)
+	}
+	return entries
+}
+
+// UntracedBlockRange is a range of blocks, inclusive, indexed in the account
+// history without their internal calls.
+type UntracedBlockRange struct {
+	First uint64
+	Last  uint64
+}
+
+// WriteUntracedBlockRange stores a range of blocks indexed in the account history
+// without their internal calls, replacing the range starting at the same block.
+func WriteUntracedBlockRange(db ethdb.KeyValueWriter, r UntracedBlockRange) {
+	if err := db.Put(untracedHistoryKey(r.First), encodeBlockNumber(r.Last)); err != nil {
+		log.Crit("Failed to store untraced block range", "first", r.First, "last", r.Last, "err", err)
+	}
+}
+
+// ReadUntracedBlockRanges retrieves the blocks between first and last, inclusive,
+// indexed in the account history without their internal calls. Adjacent and
+// overlapping ranges are merged.
+func ReadUntracedBlockRanges(db ethdb.Iteratee, first, last uint64) []UntracedBlockRange {
+	it := db.NewIterator(untracedHistoryPrefix, nil)
+	defer it.Release()
+
+	var ranges []UntracedBlockRange
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(untracedHistoryPrefix)+8 || len(it.Value()) != 8 {
+			continue
+		}
+		r := UntracedBlockRange{
+			First: binary.BigEndian.Uint64(key[len(untracedHistoryPrefix):]),
+			Last:  binary.BigEndian.Uint64(it.Value()),
+		}
+		if r.First > last {
+			break
+		}
+		if r.Last < first {
+			continue
+		}
+		if r.First < first {
+			r.First = first
+		}
+		if r.Last > last {
+			r.Last = last
+		}
+		if n := len(ranges); n > 0 && r.First <= ranges[n-1].Last+1 {
+			if r.Last > ranges[n-1].Last {
+				ranges[n-1].Last = r.Last
+			}
+			continue
+		}
+		ranges = append(ranges, r)
+	}
+	if err := it.Error(); err != nil {
+		log.Crit("Failed to read untraced block ranges", "err", err)
+	}
+	return ranges
+}
+
+// DeleteUntracedBlock removes a block from the ranges of blocks indexed in the
+// account history without their internal calls, splitting the ranges covering
+// it. The ranges are read from db and updated through w.
+func DeleteUntracedBlock(db ethdb.Iteratee, w ethdb.KeyValueWriter, number uint64) {
+	it := db.NewIterator(untracedHistoryPrefix, nil)
+	defer it.Release()
+
+	stored := make(map[uint64]uint64)
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(untracedHistoryPrefix)+8 || len(it.Value()) != 8 {
+			continue
+		}
+		stored[binary.BigEndian.Uint64(key[len(untracedHistoryPrefix):])] = binary.BigEndian.Uint64(it.Value())
+	}
+	if err := it.Error(); err != nil {
+		log.Crit("Failed to read untraced block ranges", "err", err)
+	}
+	// The blocks after the removed one are kept in a single range, merged with
+	// the range starting right after it
+	var upper *UntracedBlockRange
+	for first, last := range stored {
+		if first > number || last < number {
+			continue
+		}
+		if first < number {
+			WriteUntracedBlockRange(w, UntracedBlockRange{First: first, Last: number - 1})
+		} else if err := w.Delete(untracedHistoryKey(first)); err != nil {
+			log.Crit("Failed to delete untraced block range", "first", first, "last", last, "err", err)
+		}
+		if last > number && (upper == nil || last > upper.Last) {
+			upper = &UntracedBlockRange{First: number + 1, Last: last}
+		}
+	}
+	if upper != nil {
+		if last, ok := stored[upper.First]; ok && last > upper.Last {
+			upper.Last = last
+		}
+		WriteUntracedBlockRange(w, *upper)
+	}
+}
+
+// DeleteAccountHistory removes the whole account history index along with the
+// progress of its indexer, for it to be rebuilt from scratch.
+func DeleteAccountHistory(db ethdb.Database) error {
+	return deleteByPrefixes(db, accountHistoryPrefix, untracedHistoryPrefix, AccountHistoryIndexPrefix)
+}
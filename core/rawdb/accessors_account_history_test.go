@@ -0,0 +1,119 @@
+package rawdb
+
+import (
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+)
+
+func TestReadWriteAccountHistory(t *testing.T) {
+	var (
+		db      = NewMemoryDatabase()
+		address = common.Address{0x01}
+		other   = common.Address{0x02}
+	)
+	blockHash := func(number uint64) common.Hash {
+		return common.BigToHash(new(big.Int).SetUint64(number + 1))
+	}
+	for number := uint64(1); number <= 3; number++ {
+		WriteCanonicalHash(db, blockHash(number), number)
+	}
+	entries := []struct {
+		address common.Address
+		entry   *AccountHistoryEntry
+	}{
+		{address, &AccountHistoryEntry{BlockNumber: 1, TxIndex: 0, BlockHash: blockHash(1), Roles: AccountRoleFrom}},
+		{address, &AccountHistoryEntry{BlockNumber: 1, TxIndex: 2, BlockHash: blockHash(1), Roles: AccountRoleTo | AccountRoleInternal}},
+		{address, &AccountHistoryEntry{BlockNumber: 2, TxIndex: 1, BlockHash: common.Hash{0xff}, Roles: AccountRoleFrom}}, // reorged out
+		{address, &AccountHistoryEntry{BlockNumber: 3, TxIndex: 0, BlockHash: blockHash(3), Roles: AccountRoleCreated}},
+		{other, &AccountHistoryEntry{BlockNumber: 2, TxIndex: 0, BlockHash: blockHash(2), Roles: AccountRoleFrom}},
+	}
+	for _, e := range entries {
+		e.entry.TxHash = common.Hash{byte(e.entry.BlockNumber), byte(e.entry.TxIndex)}
+		WriteAccountHistoryEntry(db, e.address, e.entry)
+	}
+	type position struct {
+		number  uint64
+		txIndex uint32
+	}
+	tests := []struct {
+		number  uint64
+		txIndex uint32
+		oldest  uint64
+		limit   int
+		want    []position
+	}{
+		{math.MaxUint64, math.MaxUint32, 0, 10, []position{{3, 0}, {1, 2}, {1, 0}}},
+		{math.MaxUint64, math.MaxUint32, 0, 2, []position{{3, 0}, {1, 2}}},
+		{1, 2, 0, 10, []position{{1, 2}, {1, 0}}},
+		{1, 1, 0, 10, []position{{1, 0}}},
+		{math.MaxUint64, math.MaxUint32, 2, 10, []position{{3, 0}}},
+		{0, math.MaxUint32, 0, 10, nil},
+	}
+	for i, tt := range tests {
+		have := ReadAccountHistory(db, address, tt.number, tt.txIndex, tt.oldest, tt.limit)
+		if len(have) != len(tt.want) {
+			t.Fatalf("test %d: wrong number of entries: have %d, want %d", i, len(have), len(tt.want))
+		}
+		for j, entry := range have {
+			if (position{entry.BlockNumber, entry.TxIndex}) != tt.want[j] {
+				t.Errorf("test %d: entry %d: have %d/%d, want %v", i, j, entry.BlockNumber, entry.TxIndex, tt.want[j])
+			}
+			if want := (common.Hash{byte(entry.BlockNumber), byte(entry.TxIndex)}); entry.TxHash != want {
+				t.Errorf("test %d: entry %d: wrong transaction hash: have %x, want %x", i, j, entry.TxHash, want)
+			}
+		}
+	}
+	// Deleting the index drops the history of every account
+	if err := DeleteAccountHistory(db); err != nil {
+		t.Fatalf("failed to delete account history: %v", err)
+	}
+	for _, addr := range []common.Address{address, other} {
+		if entries := ReadAccountHistory(db, addr, math.MaxUint64, math.MaxUint32, 0, 10); len(entries) != 0 {
+			t.Errorf("%x: history not deleted: %d entries", addr, len(entries))
+		}
+	}
+}
+
+func TestReadWriteUntracedBlockRanges(t *testing.T) {
+	db := NewMemoryDatabase()
+	for _, r := range []UntracedBlockRange{{1, 3}, {4, 5}, {3, 4}, {8, 8}, {10, 20}} {
+		WriteUntracedBlockRange(db, r)
+	}
+	// Extending a range replaces it
+	WriteUntracedBlockRange(db, UntracedBlockRange{10, 30})
+
+	tests := []struct {
+		first, last uint64
+		want        []UntracedBlockRange
+	}{
+		{0, math.MaxUint64, []UntracedBlockRange{{1, 5}, {8, 8}, {10, 30}}},
+		{2, 12, []UntracedBlockRange{{2, 5}, {8, 8}, {10, 12}}},
+		{5, 9, []UntracedBlockRange{{5, 5}, {8, 8}}},
+		{6, 7, nil},
+		{31, math.MaxUint64, nil},
+	}
+	for i, tt := range tests {
+		if have := ReadUntracedBlockRanges(db, tt.first, tt.last); !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("test %d: wrong ranges: have %v, want %v", i, have, tt.want)
+		}
+	}
+	// Removing a block splits the ranges covering it
+	DeleteUntracedBlock(db, db, 4)
+	DeleteUntracedBlock(db, db, 10)
+	DeleteUntracedBlock(db, db, 20)
+	want := []UntracedBlockRange{{1, 3}, {5, 5}, {8, 8}, {11, 19}, {21, 30}}
+	if have := ReadUntracedBlockRanges(db, 0, math.MaxUint64); !reflect.DeepEqual(have, want) {
+		t.Errorf("wrong ranges after removing blocks: have %v, want %v", have, want)
+	}
+	// Deleting the index drops the untraced blocks too
+	if err := DeleteAccountHistory(db); err != nil {
+		t.Fatalf("failed to delete account history: %v", err)
+	}
+	if have := ReadUntracedBlockRanges(db, 0, math.MaxUint64); len(have) != 0 {
+		t.Errorf("untraced blocks not deleted: %v", have)
+	}
+}
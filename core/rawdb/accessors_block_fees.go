@@ -0,0 +1,42 @@
+package rawdb
+
+import (
+	"bytes"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/ethdb"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rlp"
+)
+
+// WriteBlockFees writes the fees paid in the block to the database.
+func WriteBlockFees(db ethdb.KeyValueWriter, l2BlockHash common.Hash, fees *types.BlockFees) {
+	if fees == nil {
+		return
+	}
+
+	bytes, err := rlp.EncodeToBytes(fees)
+	if err != nil {
+		log.Crit("Failed to RLP encode block fees", "err", err)
+	}
+	if err := db.Put(blockFeesKey(l2BlockHash), bytes); err != nil {
+		log.Crit("Failed to store block fees", "err", err)
+	}
+}
+
+// ReadBlockFees retrieves the fees paid in the block corresponding to the block hash.
+func ReadBlockFees(db ethdb.Reader, l2BlockHash common.Hash) *types.BlockFees {
+	data, err := db.Get(blockFeesKey(l2BlockHash))
+	if err != nil && IsNotFoundErr(err) {
+		return nil
+	}
+	if err != nil {
+		log.Crit("Failed to load block fees", "l2BlockHash", l2BlockHash.String(), "err", err)
+	}
+	fees := new(types.BlockFees)
+	if err := rlp.Decode(bytes.NewReader(data), fees); err != nil {
+		log.Crit("Invalid block fees RLP", "l2BlockHash", l2BlockHash.String(), "data", data, "err", err)
+	}
+	return fees
+}
@@ -0,0 +1,28 @@
+package rawdb
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+)
+
+func TestReadWriteBlockFees(t *testing.T) {
+	l2BlockHash := common.BigToHash(big.NewInt(10))
+	fees := &types.BlockFees{
+		L2Fee:     big.NewInt(21000),
+		L1DataFee: big.NewInt(1234),
+		BurnedFee: big.NewInt(0),
+	}
+	db := NewMemoryDatabase()
+	if got := ReadBlockFees(db, l2BlockHash); got != nil {
+		t.Fatal("unexpected block fees", "got", got)
+	}
+	WriteBlockFees(db, l2BlockHash, fees)
+	got := ReadBlockFees(db, l2BlockHash)
+	if got == nil || !reflect.DeepEqual(fees, got) {
+		t.Fatal("block fees mismatch", "expected", fees, "got", got)
+	}
+}
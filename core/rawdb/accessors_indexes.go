@@ -175,3 +175,51 @@ func DeleteBloombits(db ethdb.Database, bit uint, from uint64, to uint64) {
 		log.Crit("Failed to delete bloom bits", "err", it.Error())
 	}
 }
+
+// ReadLogIndex retrieves the compressed vector of the blocks of the given section
+// containing logs matching the given log key, if any.
+func ReadLogIndex(db ethdb.KeyValueReader, logKey []byte, section uint64, head common.Hash) []byte {
+	data, _ := db.Get(logIndexKey(logKey, section, head))
+	return data
+}
+
+// WriteLogIndex stores the compressed vector of the blocks of the given section
+// containing logs matching the given log key.
+func WriteLogIndex(db ethdb.KeyValueWriter, logKey []byte, section uint64, head common.Hash, blocks []byte) {
+	if err := db.Put(logIndexKey(logKey, section, head), blocks); err != nil {
+		log.Crit("Failed to store log index", "err", err)
+	}
+}
+
+// DeleteLogIndex removes the whole log index along with the progress of its
+// indexer, for it to be rebuilt from scratch.
+func DeleteLogIndex(db ethdb.Database) error {
+	return deleteByPrefixes(db, logIndexPrefix, LogIndexIndexPrefix)
+}
+
+// deleteByPrefixes removes all the entries with any of the given key prefixes.
+func deleteByPrefixes(db ethdb.Database, prefixes ...[]byte) error {
+	batch := db.NewBatch()
+	for _, prefix := range prefixes {
+		it := db.NewIterator(prefix, nil)
+		for it.Next() {
+			if err := batch.Delete(it.Key()); err != nil {
+				it.Release()
+				return err
+			}
+			if batch.ValueSize() >= ethdb.IdealBatchSize {
+				if err := batch.Write(); err != nil {
+					it.Release()
+					return err
+				}
+				batch.Reset()
+			}
+		}
+		err := it.Error()
+		it.Release()
+		if err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
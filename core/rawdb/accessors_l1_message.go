@@ -36,7 +36,7 @@ func WriteSyncedL1BlockNumber(db ethdb.KeyValueWriter, L1BlockNumber uint64) {
 // ReadSyncedL1BlockNumber retrieves the highest synced L1 block number.
 func ReadSyncedL1BlockNumber(db ethdb.Reader) *uint64 {
 	data, err := db.Get(syncedL1BlockNumberKey)
-	if err != nil && isNotFoundErr(err) {
+	if err != nil && IsNotFoundErr(err) {
 		return nil
 	}
 	if err != nil {
@@ -67,7 +67,7 @@ func WriteHighestSyncedQueueIndex(db ethdb.KeyValueWriter, queueIndex uint64) {
 // ReadHighestSyncedQueueIndex retrieves the highest synced L1 message queue index.
 func ReadHighestSyncedQueueIndex(db ethdb.Reader) uint64 {
 	data, err := db.Get(highestSyncedQueueIndexKey)
-	if err != nil && isNotFoundErr(err) {
+	if err != nil && IsNotFoundErr(err) {
 		return 0
 	}
 	if err != nil {
@@ -108,10 +108,11 @@ func WriteL1Messages(db ethdb.KeyValueWriter, l1Msgs []types.L1MessageTx) {
 }
 
 // ReadL1MessageRLP retrieves an L1 message in its raw RLP database encoding.
+// L1 messages included in finalized blocks are retrieved from the ancient store.
 func ReadL1MessageRLP(db ethdb.Reader, queueIndex uint64) rlp.RawValue {
 	data, err := db.Get(L1MessageKey(queueIndex))
-	if err != nil && isNotFoundErr(err) {
-		return nil
+	if err != nil && IsNotFoundErr(err) {
+		return readRollupAncient(db, rollupL1MessageTable, queueIndex)
 	}
 	if err != nil {
 		log.Crit("Failed to load L1 message", "queueIndex", queueIndex, "err", err)
@@ -134,11 +135,18 @@ func ReadL1Message(db ethdb.Reader, queueIndex uint64) *types.L1MessageTx {
 
 // L1MessageIterator is a wrapper around ethdb.Iterator that
 // allows us to iterate over L1 messages in the database. It
-// implements an interface similar to ethdb.Iterator.
+// implements an interface similar to ethdb.Iterator. Frozen
+// L1 messages are served from the ancient store first.
 type L1MessageIterator struct {
 	inner         ethdb.Iterator
+	db            ethdb.Reader
 	keyLength     int
 	maxQueueIndex uint64
+
+	frozen     bool   // Whether frozen L1 messages might remain
+	queueIndex uint64 // Enqueue index of the current L1 message
+	value      []byte // RLP encoding of the current L1 message
+	next       uint64 // Lowest enqueue index not yet returned
 }
 
 // IterateL1MessagesFrom creates an L1MessageIterator that iterates over
@@ -151,8 +159,11 @@ func IterateL1MessagesFrom(db ethdb.Database, fromQueueIndex uint64) L1MessageIt
 
 	return L1MessageIterator{
 		inner:         it,
+		db:            db,
 		keyLength:     keyLength,
 		maxQueueIndex: maxQueueIndex,
+		frozen:        true,
+		next:          fromQueueIndex,
 	}
 }
 
@@ -166,30 +177,42 @@ func (it *L1MessageIterator) Next() bool {
 		iteratorNextDurationTimer.Update(time.Since(t0))
 	}(time.Now())
 
+	if it.frozen {
+		if data := readRollupAncient(it.db, rollupL1MessageTable, it.next); data != nil {
+			it.queueIndex, it.value = it.next, data
+			it.next++
+			return true
+		}
+		it.frozen = false
+	}
 	for it.inner.Next() {
 		iteratorInnerNextCalledCounter.Inc(1)
 
 		key := it.inner.Key()
-		if len(key) == it.keyLength {
-			return true
-		} else {
+		if len(key) != it.keyLength {
 			iteratorLengthMismatchCounter.Inc(1)
+			continue
+		}
+		// Skip messages that were served from the ancient store
+		queueIndex := binary.BigEndian.Uint64(key[len(l1MessagePrefix):])
+		if queueIndex < it.next {
+			continue
 		}
+		it.queueIndex, it.value = queueIndex, it.inner.Value()
+		it.next = queueIndex + 1
+		return true
 	}
 	return false
 }
 
 // QueueIndex returns the enqueue index of the current L1 message.
 func (it *L1MessageIterator) QueueIndex() uint64 {
-	key := it.inner.Key()
-	raw := key[len(l1MessagePrefix) : len(l1MessagePrefix)+8]
-	queueIndex := binary.BigEndian.Uint64(raw)
-	return queueIndex
+	return it.queueIndex
 }
 
 // L1Message returns the current L1 message.
 func (it *L1MessageIterator) L1Message() types.L1MessageTx {
-	data := it.inner.Value()
+	data := it.value
 	l1Msg := types.L1MessageTx{}
 	if err := rlp.DecodeBytes(data, &l1Msg); err != nil {
 		log.Crit("Invalid L1 message RLP", "data", data, "err", err)
@@ -263,7 +286,7 @@ func WriteFirstQueueIndexNotInL2Block(db ethdb.KeyValueWriter, l2BlockHash commo
 // that is NOT included in the ledger up to and including the provided L2 block.
 func ReadFirstQueueIndexNotInL2Block(db ethdb.Reader, l2BlockHash common.Hash) *uint64 {
 	data, err := db.Get(FirstQueueIndexNotInL2BlockKey(l2BlockHash))
-	if err != nil && isNotFoundErr(err) {
+	if err != nil && IsNotFoundErr(err) {
 		return nil
 	}
 	if err != nil {
@@ -275,3 +298,103 @@ func ReadFirstQueueIndexNotInL2Block(db ethdb.Reader, l2BlockHash common.Hash) *
 	queueIndex := binary.BigEndian.Uint64(data)
 	return &queueIndex
 }
+
+// L1MessageOrigin is the L1 transaction which enqueued an L1 message.
+type L1MessageOrigin struct {
+	TxHash      common.Hash // Hash of the L1 transaction
+	BlockNumber uint64      // Number of the L1 block including the transaction
+	BlockHash   common.Hash // Hash of the L1 block including the transaction
+}
+
+// WriteL1MessageOrigin stores the L1 transaction which enqueued an L1 message,
+// along with the index of the L1 messages enqueued by the transaction.
+func WriteL1MessageOrigin(db ethdb.KeyValueWriter, queueIndex uint64, origin L1MessageOrigin) {
+	data, err := rlp.EncodeToBytes(origin)
+	if err != nil {
+		log.Crit("Failed to RLP encode L1 message origin", "queueIndex", queueIndex, "err", err)
+	}
+	if err := db.Put(l1MessageOriginKey(queueIndex), data); err != nil {
+		log.Crit("Failed to store L1 message origin", "queueIndex", queueIndex, "err", err)
+	}
+	if err := db.Put(l1TxMessageKey(origin.TxHash, queueIndex), nil); err != nil {
+		log.Crit("Failed to store L1 transaction message index", "queueIndex", queueIndex, "err", err)
+	}
+}
+
+// ReadL1MessageOrigin retrieves the L1 transaction which enqueued an L1 message.
+// Messages synced before the origins were recorded have none.
+func ReadL1MessageOrigin(db ethdb.Reader, queueIndex uint64) *L1MessageOrigin {
+	data, err := db.Get(l1MessageOriginKey(queueIndex))
+	if err != nil && IsNotFoundErr(err) {
+		return nil
+	}
+	if err != nil {
+		log.Crit("Failed to read L1 message origin from database", "queueIndex", queueIndex, "err", err)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	var origin L1MessageOrigin
+	if err := rlp.DecodeBytes(data, &origin); err != nil {
+		log.Crit("Invalid L1 message origin RLP", "queueIndex", queueIndex, "data", data, "err", err)
+	}
+	return &origin
+}
+
+// ReadL1MessageQueueIndicesByTxHash retrieves the queue indices of the L1
+// messages enqueued by the given L1 transaction, in ascending order.
+func ReadL1MessageQueueIndicesByTxHash(db ethdb.Iteratee, l1TxHash common.Hash) []uint64 {
+	prefix := append(append([]byte{}, l1TxMessagePrefix...), l1TxHash.Bytes()...)
+	it := db.NewIterator(prefix, nil)
+	defer it.Release()
+
+	var queueIndices []uint64
+	for it.Next() {
+		if key := it.Key(); len(key) == len(prefix)+8 {
+			queueIndices = append(queueIndices, binary.BigEndian.Uint64(key[len(prefix):]))
+		}
+	}
+	if err := it.Error(); err != nil {
+		log.Crit("Failed to read L1 transaction message indices", "l1TxHash", l1TxHash, "err", err)
+	}
+	return queueIndices
+}
+
+// WriteL1MessageLookupEntriesByBlock stores the number of the given L2 block as
+// the location of the L1 messages it includes.
+func WriteL1MessageLookupEntriesByBlock(db ethdb.KeyValueWriter, block *types.Block) {
+	number := encodeBigEndian(block.NumberU64())
+	for _, tx := range block.Transactions() {
+		if !tx.IsL1MessageTx() {
+			continue
+		}
+		if err := db.Put(l1MessageLookupKey(tx.L1MessageQueueIndex()), number); err != nil {
+			log.Crit("Failed to store L1 message lookup entry", "err", err)
+		}
+	}
+}
+
+// DeleteL1MessageLookupEntry removes the location of an L1 message no longer
+// included in the canonical chain.
+func DeleteL1MessageLookupEntry(db ethdb.KeyValueWriter, queueIndex uint64) {
+	if err := db.Delete(l1MessageLookupKey(queueIndex)); err != nil {
+		log.Crit("Failed to delete L1 message lookup entry", "err", err)
+	}
+}
+
+// ReadL1MessageLookupEntry retrieves the number of the L2 block including an L1
+// message, if any.
+func ReadL1MessageLookupEntry(db ethdb.Reader, queueIndex uint64) *uint64 {
+	data, err := db.Get(l1MessageLookupKey(queueIndex))
+	if err != nil && IsNotFoundErr(err) {
+		return nil
+	}
+	if err != nil {
+		log.Crit("Failed to read L1 message lookup entry from database", "queueIndex", queueIndex, "err", err)
+	}
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
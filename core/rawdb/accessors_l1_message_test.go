@@ -157,3 +157,52 @@ func TestIterationStopsAtMaxQueueIndex(t *testing.T) {
 		t.Fatal("Invalid length", "expected", 3, "got", len(got))
 	}
 }
+
+func TestReadWriteL1MessageOrigin(t *testing.T) {
+	var (
+		db      = NewMemoryDatabase()
+		l1TxA   = common.Hash{0xa}
+		l1TxB   = common.Hash{0xb}
+		origins = map[uint64]L1MessageOrigin{
+			5: {TxHash: l1TxA, BlockNumber: 100, BlockHash: common.Hash{1}},
+			6: {TxHash: l1TxA, BlockNumber: 100, BlockHash: common.Hash{1}},
+			7: {TxHash: l1TxB, BlockNumber: 101, BlockHash: common.Hash{2}},
+		}
+	)
+	for queueIndex, origin := range origins {
+		WriteL1MessageOrigin(db, queueIndex, origin)
+	}
+	for queueIndex, origin := range origins {
+		got := ReadL1MessageOrigin(db, queueIndex)
+		if got == nil || *got != origin {
+			t.Fatal("L1 message origin mismatch", "queueIndex", queueIndex, "expected", origin, "got", got)
+		}
+	}
+	if got := ReadL1MessageOrigin(db, 8); got != nil {
+		t.Fatal("Unexpected L1 message origin", "got", got)
+	}
+	if got := ReadL1MessageQueueIndicesByTxHash(db, l1TxA); len(got) != 2 || got[0] != 5 || got[1] != 6 {
+		t.Fatal("Queue indices mismatch", "expected", []uint64{5, 6}, "got", got)
+	}
+	if got := ReadL1MessageQueueIndicesByTxHash(db, l1TxB); len(got) != 1 || got[0] != 7 {
+		t.Fatal("Queue indices mismatch", "expected", []uint64{7}, "got", got)
+	}
+	if got := ReadL1MessageQueueIndicesByTxHash(db, common.Hash{0xc}); len(got) != 0 {
+		t.Fatal("Unexpected queue indices", "got", got)
+	}
+}
+
+func TestReadWriteL1MessageLookupEntries(t *testing.T) {
+	msg := newL1MessageTx(9)
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(42)}).WithBody(types.Transactions{types.NewTx(&msg)}, nil)
+
+	db := NewMemoryDatabase()
+	WriteL1MessageLookupEntriesByBlock(db, block)
+	if got := ReadL1MessageLookupEntry(db, 9); got == nil || *got != 42 {
+		t.Fatal("L1 message lookup mismatch", "expected", 42, "got", got)
+	}
+	DeleteL1MessageLookupEntry(db, 9)
+	if got := ReadL1MessageLookupEntry(db, 9); got != nil {
+		t.Fatal("Unexpected L1 message lookup entry", "got", got)
+	}
+}
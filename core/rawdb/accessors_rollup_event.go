@@ -2,6 +2,8 @@ package rawdb
 
 import (
 	"bytes"
+	"encoding/binary"
+	"math"
 	"math/big"
 
 	"github.com/scroll-tech/go-ethereum/common"
@@ -35,7 +37,7 @@ func WriteRollupEventSyncedL1BlockNumber(db ethdb.KeyValueWriter, l1BlockNumber
 // ReadRollupEventSyncedL1BlockNumber fetches the highest synced L1 block number associated with rollup events from the database.
 func ReadRollupEventSyncedL1BlockNumber(db ethdb.Reader) *uint64 {
 	data, err := db.Get(rollupEventSyncedL1BlockNumberKey)
-	if err != nil && isNotFoundErr(err) {
+	if err != nil && IsNotFoundErr(err) {
 		return nil
 	}
 	if err != nil {
@@ -61,6 +63,10 @@ func WriteBatchChunkRanges(db ethdb.KeyValueWriter, batchIndex uint64, chunkBloc
 	if err := db.Put(batchChunkRangesKey(batchIndex), value); err != nil {
 		log.Crit("failed to store batch chunk ranges", "batch index", batchIndex, "value", value, "err", err)
 	}
+	// Batches are committed in order, the last one written is the highest
+	if err := db.Put(lastBatchChunkRangesIndexKey, encodeBigEndian(batchIndex)); err != nil {
+		log.Crit("failed to store last batch chunk ranges index", "batch index", batchIndex, "err", err)
+	}
 }
 
 // DeleteBatchChunkRanges removes the block ranges of all chunks associated with a specific batch from the database.
@@ -75,8 +81,11 @@ func DeleteBatchChunkRanges(db ethdb.KeyValueWriter, batchIndex uint64) {
 // It returns a list of ChunkBlockRange pointers, or nil if no chunk ranges are found for the given batch index.
 func ReadBatchChunkRanges(db ethdb.Reader, batchIndex uint64) []*ChunkBlockRange {
 	data, err := db.Get(batchChunkRangesKey(batchIndex))
-	if err != nil && isNotFoundErr(err) {
-		return nil
+	if err != nil && IsNotFoundErr(err) {
+		if data = readRollupAncient(db, rollupBatchChunkRangesTable, batchIndex); data == nil {
+			return nil
+		}
+		err = nil
 	}
 	if err != nil {
 		log.Crit("failed to read batch chunk ranges from database", "err", err)
@@ -104,8 +113,11 @@ func WriteFinalizedBatchMeta(db ethdb.KeyValueWriter, batchIndex uint64, finaliz
 // ReadFinalizedBatchMeta fetches the metadata of a finalized batch from the database.
 func ReadFinalizedBatchMeta(db ethdb.Reader, batchIndex uint64) *FinalizedBatchMeta {
 	data, err := db.Get(batchMetaKey(batchIndex))
-	if err != nil && isNotFoundErr(err) {
-		return nil
+	if err != nil && IsNotFoundErr(err) {
+		if data = readRollupAncient(db, rollupBatchMetaTable, batchIndex); data == nil {
+			return nil
+		}
+		err = nil
 	}
 	if err != nil {
 		log.Crit("failed to read finalized batch metadata from database", "batch index", batchIndex, "err", err)
@@ -129,7 +141,7 @@ func WriteFinalizedL2BlockNumber(db ethdb.KeyValueWriter, l2BlockNumber uint64)
 // ReadFinalizedL2BlockNumber fetches the highest finalized L2 block number from the database.
 func ReadFinalizedL2BlockNumber(db ethdb.Reader) *uint64 {
 	data, err := db.Get(finalizedL2BlockNumberKey)
-	if err != nil && isNotFoundErr(err) {
+	if err != nil && IsNotFoundErr(err) {
 		return nil
 	}
 	if err != nil {
@@ -144,3 +156,106 @@ func ReadFinalizedL2BlockNumber(db ethdb.Reader) *uint64 {
 	finalizedL2BlockNumber := number.Uint64()
 	return &finalizedL2BlockNumber
 }
+
+// ReadLastBatchChunkRangesIndex returns the index of the batch whose chunk ranges
+// were written last, or nil if there is none. The chunk ranges of that batch
+// might have been deleted since, if the batch was reverted.
+func ReadLastBatchChunkRangesIndex(db ethdb.Database) *uint64 {
+	data, err := db.Get(lastBatchChunkRangesIndexKey)
+	if err != nil && !IsNotFoundErr(err) {
+		log.Crit("failed to read last batch chunk ranges index", "err", err)
+	}
+	if len(data) == 8 {
+		index := binary.BigEndian.Uint64(data)
+		return &index
+	}
+	// Databases written before the index was tracked
+	return readLastBatchIndex(db, batchChunkRangesPrefix, rollupBatchChunkRangesTable)
+}
+
+// ReadLastFinalizedBatchIndex returns the highest finalized batch index in the
+// database, or nil if there is none.
+func ReadLastFinalizedBatchIndex(db ethdb.Database) *uint64 {
+	return readLastBatchIndex(db, batchMetaPrefix, rollupBatchMetaTable)
+}
+
+// readLastBatchIndex returns the highest batch index stored under the given
+// prefix, falling back to the batches moved into the ancient store. The index
+// is found by seeking backwards from the end of the key range, instead of
+// iterating over all batches.
+func readLastBatchIndex(db ethdb.Database, prefix []byte, table string) *uint64 {
+	// Binary search the highest index from which a batch is still stored
+	var lo, hi uint64 = 0, math.MaxUint64
+	if !hasBatchIndexFrom(db, prefix, 0) {
+		if items := rollupAncientItems(db, table); items > 0 {
+			index := items - 1
+			return &index
+		}
+		return nil
+	}
+	for lo+1 < hi {
+		mid := lo + (hi-lo)/2
+		if hasBatchIndexFrom(db, prefix, mid) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	if !hasBatchIndexFrom(db, prefix, hi) {
+		return &lo
+	}
+	return &hi
+}
+
+// hasBatchIndexFrom returns whether a batch with an index of at least start is
+// stored under the given prefix.
+func hasBatchIndexFrom(db ethdb.Iteratee, prefix []byte, start uint64) bool {
+	it := db.NewIterator(prefix, encodeBigEndian(start))
+	defer it.Release()
+
+	for it.Next() {
+		if len(it.Key()) == len(prefix)+8 {
+			return true
+		}
+	}
+	return false
+}
+
+// FindBatchIndexByL2BlockNumber returns the index of the batch committing the
+// given L2 block, using a binary search over the batch chunk ranges. Batches
+// without chunk ranges, e.g. reverted ones, are skipped. It returns nil if the
+// block is not committed yet.
+func FindBatchIndexByL2BlockNumber(db ethdb.Database, number uint64) *uint64 {
+	last := ReadLastBatchChunkRangesIndex(db)
+	if last == nil {
+		return nil
+	}
+	var (
+		lo, hi = uint64(0), *last + 1
+		found  *uint64
+		ranges []*ChunkBlockRange
+	)
+	for lo < hi {
+		// Probe the first batch with chunk ranges from the middle on
+		mid := lo + (hi-lo)/2
+		index := mid
+		chunkBlockRanges := ReadBatchChunkRanges(db, index)
+		for len(chunkBlockRanges) == 0 && index+1 < hi {
+			index++
+			chunkBlockRanges = ReadBatchChunkRanges(db, index)
+		}
+		switch {
+		case len(chunkBlockRanges) == 0:
+			hi = mid
+		case chunkBlockRanges[len(chunkBlockRanges)-1].EndBlockNumber >= number:
+			found, ranges = &index, chunkBlockRanges
+			hi = mid
+		default:
+			lo = index + 1
+		}
+	}
+	if found == nil || ranges[0].StartBlockNumber > number {
+		return nil
+	}
+	return found
+}
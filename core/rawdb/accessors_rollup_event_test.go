@@ -184,3 +184,73 @@ func TestBatchChunkRanges(t *testing.T) {
 	// delete non-existing value: ensure the delete operation handles non-existing values without errors.
 	DeleteBatchChunkRanges(db, uint64(len(chunks)+1))
 }
+
+func TestFindBatchIndexByL2BlockNumber(t *testing.T) {
+	db := NewMemoryDatabase()
+	if index := FindBatchIndexByL2BlockNumber(db, 0); index != nil {
+		t.Fatalf("expected no batch without chunk ranges, got %d", *index)
+	}
+
+	// Batch 0 is the genesis batch, batch 1 commits blocks 1-5 and batch 2 blocks 6-10
+	WriteBatchChunkRanges(db, 0, []*ChunkBlockRange{{StartBlockNumber: 0, EndBlockNumber: 0}})
+	WriteBatchChunkRanges(db, 1, []*ChunkBlockRange{{StartBlockNumber: 1, EndBlockNumber: 2}, {StartBlockNumber: 3, EndBlockNumber: 5}})
+	WriteBatchChunkRanges(db, 2, []*ChunkBlockRange{{StartBlockNumber: 6, EndBlockNumber: 10}})
+
+	for number, want := range []uint64{0, 1, 1, 1, 1, 1, 2, 2, 2, 2, 2} {
+		index := FindBatchIndexByL2BlockNumber(db, uint64(number))
+		if index == nil || *index != want {
+			t.Fatalf("block %d: have batch %v, want %d", number, index, want)
+		}
+	}
+	if index := FindBatchIndexByL2BlockNumber(db, 11); index != nil {
+		t.Fatalf("expected no batch for uncommitted block, got %d", *index)
+	}
+
+	// Reverted batches are skipped, their blocks are not committed
+	DeleteBatchChunkRanges(db, 1)
+	WriteBatchChunkRanges(db, 3, []*ChunkBlockRange{{StartBlockNumber: 11, EndBlockNumber: 12}})
+	if index := FindBatchIndexByL2BlockNumber(db, 3); index != nil {
+		t.Fatalf("expected no batch with missing chunk ranges, got %d", *index)
+	}
+	for number, want := range map[uint64]uint64{0: 0, 6: 2, 10: 2, 11: 3, 12: 3} {
+		index := FindBatchIndexByL2BlockNumber(db, number)
+		if index == nil || *index != want {
+			t.Fatalf("block %d: have batch %v, want %d", number, index, want)
+		}
+	}
+	// A reverted last batch is skipped as well
+	DeleteBatchChunkRanges(db, 3)
+	if index := FindBatchIndexByL2BlockNumber(db, 11); index != nil {
+		t.Fatalf("expected no batch for reverted block, got %d", *index)
+	}
+	if index := FindBatchIndexByL2BlockNumber(db, 8); index == nil || *index != 2 {
+		t.Fatalf("block 8: have batch %v, want 2", index)
+	}
+}
+
+func TestReadLastBatchIndex(t *testing.T) {
+	db := NewMemoryDatabase()
+	if index := ReadLastBatchChunkRangesIndex(db); index != nil {
+		t.Fatalf("expected no last batch, got %d", *index)
+	}
+	for _, index := range []uint64{0, 5, 1 << 40} {
+		WriteBatchChunkRanges(db, index, []*ChunkBlockRange{{StartBlockNumber: index, EndBlockNumber: index}})
+	}
+	WriteFinalizedBatchMeta(db, 3, &FinalizedBatchMeta{})
+
+	if index := ReadLastBatchChunkRangesIndex(db); index == nil || *index != 1<<40 {
+		t.Fatalf("last chunk ranges index mismatch: have %v, want %d", index, uint64(1<<40))
+	}
+	if index := ReadLastFinalizedBatchIndex(db); index == nil || *index != 3 {
+		t.Fatalf("last finalized batch index mismatch: have %v, want 3", index)
+	}
+	// The last written index is kept when reverting, the stored batches are
+	// found by seeking from the end
+	DeleteBatchChunkRanges(db, 1<<40)
+	if index := ReadLastBatchChunkRangesIndex(db); index == nil || *index != 1<<40 {
+		t.Fatalf("last chunk ranges index mismatch: have %v, want %d", index, uint64(1<<40))
+	}
+	if index := readLastBatchIndex(db, batchChunkRangesPrefix, rollupBatchChunkRangesTable); index == nil || *index != 5 {
+		t.Fatalf("last stored chunk ranges index mismatch: have %v, want 5", index)
+	}
+}
@@ -2,6 +2,7 @@ package rawdb
 
 import (
 	"bytes"
+	"encoding/binary"
 
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/core/types"
@@ -39,13 +40,41 @@ func ReadBlockRowConsumption(db ethdb.Reader, l2BlockHash common.Hash) *types.Ro
 }
 
 // ReadBlockRowConsumption retrieves the RowConsumption in its raw RLP database encoding.
+// The RowConsumption of finalized blocks is retrieved from the ancient store.
 func ReadBlockRowConsumptionRLP(db ethdb.Reader, l2BlockHash common.Hash) rlp.RawValue {
 	data, err := db.Get(rowConsumptionKey(l2BlockHash))
-	if err != nil && isNotFoundErr(err) {
-		return nil
+	if err != nil && IsNotFoundErr(err) {
+		number := ReadHeaderNumber(db, l2BlockHash)
+		if number == nil || ReadCanonicalHash(db, *number) != l2BlockHash {
+			return nil
+		}
+		tail := readRowConsumptionTail(db)
+		if tail == nil || *number < *tail {
+			return nil
+		}
+		return readRollupAncient(db, rollupRowConsumptionTable, *number-*tail)
 	}
 	if err != nil {
 		log.Crit("Failed to load RowConsumption", "l2BlockHash", l2BlockHash.String(), "err", err)
 	}
 	return data
 }
+
+// readRowConsumptionTail retrieves the number of the first block whose row
+// consumption is stored in the rollup freezer.
+func readRowConsumptionTail(db ethdb.KeyValueReader) *uint64 {
+	data, _ := db.Get(rowConsumptionTailKey)
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// writeRowConsumptionTail stores the number of the first block whose row
+// consumption is stored in the rollup freezer.
+func writeRowConsumptionTail(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Put(rowConsumptionTailKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store the row consumption freezer tail", "err", err)
+	}
+}
@@ -29,7 +29,7 @@ func writeNumSkippedTransactions(db ethdb.KeyValueWriter, numSkipped uint64) {
 // ReadNumSkippedTransactions retrieves the number of skipped transactions.
 func ReadNumSkippedTransactions(db ethdb.Reader) uint64 {
 	data, err := db.Get(numSkippedTransactionsKey)
-	if err != nil && isNotFoundErr(err) {
+	if err != nil && IsNotFoundErr(err) {
 		return 0
 	}
 	if err != nil {
@@ -123,10 +123,11 @@ func writeSkippedTransactionV1(db ethdb.KeyValueWriter, tx *types.Transaction, r
 }
 
 // readSkippedTransactionRLP retrieves a skipped transaction in its raw RLP database encoding.
+// Skipped transactions of finalized blocks are retrieved from the ancient store.
 func readSkippedTransactionRLP(db ethdb.Reader, txHash common.Hash) rlp.RawValue {
 	data, err := db.Get(SkippedTransactionKey(txHash))
-	if err != nil && isNotFoundErr(err) {
-		return nil
+	if err != nil && IsNotFoundErr(err) {
+		return readSkippedTransactionAncientRLP(db, txHash)
 	}
 	if err != nil {
 		log.Crit("Failed to load skipped transaction", "hash", txHash.String(), "err", err)
@@ -134,17 +135,29 @@ func readSkippedTransactionRLP(db ethdb.Reader, txHash common.Hash) rlp.RawValue
 	return data
 }
 
-// ReadSkippedTransaction retrieves a skipped transaction by its hash, along with its skipped reason.
-func ReadSkippedTransaction(db ethdb.Reader, txHash common.Hash) *SkippedTransactionV2 {
-	data := readSkippedTransactionRLP(db, txHash)
-	if len(data) == 0 {
+// readSkippedTransactionAncientRLP retrieves a frozen skipped transaction in its raw RLP
+// database encoding, using the skip index recorded when it was frozen.
+func readSkippedTransactionAncientRLP(db ethdb.Reader, txHash common.Hash) rlp.RawValue {
+	data, err := db.Get(skippedTransactionAncientKey(txHash))
+	if err != nil && IsNotFoundErr(err) {
+		return nil
+	}
+	if err != nil {
+		log.Crit("Failed to load frozen skipped transaction index", "hash", txHash.String(), "err", err)
+	}
+	if len(data) != 8 {
 		return nil
 	}
+	return readRollupAncient(db, rollupSkippedTxTable, binary.BigEndian.Uint64(data))
+}
+
+// decodeSkippedTransaction decodes a skipped transaction from either of its RLP encodings.
+func decodeSkippedTransaction(data []byte) (*SkippedTransactionV2, error) {
 	var stxV2 SkippedTransactionV2
 	var stx SkippedTransaction
 	if err := rlp.Decode(bytes.NewReader(data), &stxV2); err != nil {
 		if err := rlp.Decode(bytes.NewReader(data), &stx); err != nil {
-			log.Crit("Invalid skipped transaction RLP", "hash", txHash.String(), "data", data, "err", err)
+			return nil, err
 		}
 		stxV2.Tx = stx.Tx
 		stxV2.Reason = stx.Reason
@@ -155,7 +168,20 @@ func ReadSkippedTransaction(db ethdb.Reader, txHash common.Hash) *SkippedTransac
 	if stxV2.BlockHash != nil && *stxV2.BlockHash == (common.Hash{}) {
 		stxV2.BlockHash = nil
 	}
-	return &stxV2
+	return &stxV2, nil
+}
+
+// ReadSkippedTransaction retrieves a skipped transaction by its hash, along with its skipped reason.
+func ReadSkippedTransaction(db ethdb.Reader, txHash common.Hash) *SkippedTransactionV2 {
+	data := readSkippedTransactionRLP(db, txHash)
+	if len(data) == 0 {
+		return nil
+	}
+	stx, err := decodeSkippedTransaction(data)
+	if err != nil {
+		log.Crit("Invalid skipped transaction RLP", "hash", txHash.String(), "data", data, "err", err)
+	}
+	return stx
 }
 
 // writeSkippedTransactionHash writes the hash of a skipped transaction to the database.
@@ -168,8 +194,8 @@ func writeSkippedTransactionHash(db ethdb.KeyValueWriter, index uint64, txHash c
 // ReadSkippedTransactionHash retrieves the hash of a skipped transaction by its index.
 func ReadSkippedTransactionHash(db ethdb.Reader, index uint64) *common.Hash {
 	data, err := db.Get(SkippedTransactionHashKey(index))
-	if err != nil && isNotFoundErr(err) {
-		return nil
+	if err != nil && IsNotFoundErr(err) {
+		return readSkippedTransactionAncientHash(db, index)
 	}
 	if err != nil {
 		log.Crit("Failed to load skipped transaction hash", "index", index, "err", err)
@@ -178,6 +204,21 @@ func ReadSkippedTransactionHash(db ethdb.Reader, index uint64) *common.Hash {
 	return &hash
 }
 
+// readSkippedTransactionAncientHash retrieves the hash of a frozen skipped
+// transaction by its index.
+func readSkippedTransactionAncientHash(db ethdb.AncientReader, index uint64) *common.Hash {
+	data := readRollupAncient(db, rollupSkippedTxTable, index)
+	if len(data) == 0 {
+		return nil
+	}
+	stx, err := decodeSkippedTransaction(data)
+	if err != nil {
+		log.Crit("Invalid frozen skipped transaction RLP", "index", index, "err", err)
+	}
+	hash := stx.Tx.Hash()
+	return &hash
+}
+
 // WriteSkippedTransaction writes a skipped transaction to the database and also updates the count and lookup index.
 // Note: The lookup index and count will include duplicates if there are chain reorgs.
 func WriteSkippedTransaction(db ethdb.Database, tx *types.Transaction, traces *types.BlockTrace, reason string, blockNumber uint64, blockHash *common.Hash) {
@@ -201,24 +242,36 @@ func WriteSkippedTransaction(db ethdb.Database, tx *types.Transaction, traces *t
 
 // SkippedTransactionIterator is a wrapper around ethdb.Iterator that
 // allows us to iterate over skipped transaction hashes in the database.
-// It implements an interface similar to ethdb.Iterator.
+// It implements an interface similar to ethdb.Iterator. Frozen skipped
+// transactions are iterated first, from the ancient store.
 type SkippedTransactionIterator struct {
 	inner     ethdb.Iterator
 	db        ethdb.Reader
 	keyLength int
+
+	next   uint64      // Next frozen index to iterate
+	frozen uint64      // Number of frozen skipped transactions
+	index  uint64      // Index of the current skipped transaction
+	hash   common.Hash // Hash of the current skipped transaction
 }
 
 // IterateSkippedTransactionsFrom creates a SkippedTransactionIterator that iterates
 // over all skipped transaction hashes in the database starting at the provided index.
 func IterateSkippedTransactionsFrom(db ethdb.Database, index uint64) SkippedTransactionIterator {
-	start := encodeBigEndian(index)
-	it := db.NewIterator(skippedTransactionHashPrefix, start)
+	frozen := rollupAncientItems(db, rollupSkippedTxTable)
+	start := index
+	if start < frozen {
+		start = frozen
+	}
+	it := db.NewIterator(skippedTransactionHashPrefix, encodeBigEndian(start))
 	keyLength := len(skippedTransactionHashPrefix) + 8
 
 	return SkippedTransactionIterator{
 		inner:     it,
 		db:        db,
 		keyLength: keyLength,
+		next:      index,
+		frozen:    frozen,
 	}
 }
 
@@ -226,9 +279,20 @@ func IterateSkippedTransactionsFrom(db ethdb.Database, index uint64) SkippedTran
 // It returns false when the iterator is exhausted.
 // TODO: Consider reading items in batches.
 func (it *SkippedTransactionIterator) Next() bool {
+	if it.next < it.frozen {
+		hash := readSkippedTransactionAncientHash(it.db, it.next)
+		if hash != nil {
+			it.index, it.hash = it.next, *hash
+			it.next++
+			return true
+		}
+		it.next = it.frozen
+	}
 	for it.inner.Next() {
 		key := it.inner.Key()
 		if len(key) == it.keyLength {
+			it.index = binary.BigEndian.Uint64(key[len(skippedTransactionHashPrefix):])
+			it.hash = common.BytesToHash(it.inner.Value())
 			return true
 		}
 	}
@@ -237,16 +301,12 @@ func (it *SkippedTransactionIterator) Next() bool {
 
 // Index returns the index of the current skipped transaction hash.
 func (it *SkippedTransactionIterator) Index() uint64 {
-	key := it.inner.Key()
-	raw := key[len(skippedTransactionHashPrefix) : len(skippedTransactionHashPrefix)+8]
-	index := binary.BigEndian.Uint64(raw)
-	return index
+	return it.index
 }
 
 // TransactionHash returns the current skipped transaction hash.
 func (it *SkippedTransactionIterator) TransactionHash() common.Hash {
-	data := it.inner.Value()
-	return common.BytesToHash(data)
+	return it.hash
 }
 
 // Release releases the associated resources.
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sync/atomic"
 	"time"
 
@@ -37,12 +38,53 @@ import (
 type freezerdb struct {
 	ethdb.KeyValueStore
 	ethdb.AncientStore
+
+	rollup *rollupFreezer // Freezer of finalized rollup metadata, nil if unavailable
+}
+
+// HasAncient returns an indicator whether the specified data exists in the
+// chain or rollup freezer.
+func (frdb *freezerdb) HasAncient(kind string, number uint64) (bool, error) {
+	if frdb.rollup != nil && frdb.rollup.owns(kind) {
+		return frdb.rollup.HasAncient(kind, number)
+	}
+	return frdb.AncientStore.HasAncient(kind, number)
+}
+
+// Ancient retrieves an ancient binary blob from the chain or rollup freezer.
+func (frdb *freezerdb) Ancient(kind string, number uint64) ([]byte, error) {
+	if frdb.rollup != nil && frdb.rollup.owns(kind) {
+		return frdb.rollup.Ancient(kind, number)
+	}
+	return frdb.AncientStore.Ancient(kind, number)
+}
+
+// AncientRange retrieves multiple items in sequence from the chain or rollup
+// freezer, starting from the index 'start'.
+func (frdb *freezerdb) AncientRange(kind string, start, count, maxBytes uint64) ([][]byte, error) {
+	if frdb.rollup != nil && frdb.rollup.owns(kind) {
+		return frdb.rollup.AncientRange(kind, start, count, maxBytes)
+	}
+	return frdb.AncientStore.AncientRange(kind, start, count, maxBytes)
+}
+
+// AncientSize returns the ancient size of the specified chain or rollup table.
+func (frdb *freezerdb) AncientSize(kind string) (uint64, error) {
+	if frdb.rollup != nil && frdb.rollup.owns(kind) {
+		return frdb.rollup.AncientSize(kind)
+	}
+	return frdb.AncientStore.AncientSize(kind)
 }
 
 // Close implements io.Closer, closing both the fast key-value store as well as
 // the slow ancient tables.
 func (frdb *freezerdb) Close() error {
 	var errs []error
+	if frdb.rollup != nil {
+		if err := frdb.rollup.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
 	if err := frdb.AncientStore.Close(); err != nil {
 		errs = append(errs, err)
 	}
@@ -75,6 +117,18 @@ func (frdb *freezerdb) Freeze(threshold uint64) error {
 	return nil
 }
 
+// FreezeRollup is a helper method used for external testing to trigger and block
+// until a rollup metadata freeze cycle completes.
+func (frdb *freezerdb) FreezeRollup() error {
+	if frdb.rollup == nil || frdb.rollup.readonly {
+		return errReadOnly
+	}
+	trigger := make(chan struct{}, 1)
+	frdb.rollup.trigger <- trigger
+	<-trigger
+	return nil
+}
+
 // nofreezedb is a database wrapper that disables freezer data retrievals.
 type nofreezedb struct {
 	ethdb.KeyValueStore
@@ -215,18 +269,31 @@ func NewDatabaseWithFreezer(db ethdb.KeyValueStore, freezer string, namespace st
 			// feezer.
 		}
 	}
+	// Rollup metadata is frozen separately, as it isn't indexed by block number
+	rollup, err := newRollupFreezer(freezer, namespace, readonly)
+	if err != nil {
+		frdb.Close()
+		return nil, err
+	}
 	// Freezer is consistent with the key-value database, permit combining the two
+	fdb := &freezerdb{
+		KeyValueStore: db,
+		AncientStore:  frdb,
+		rollup:        rollup,
+	}
 	if !frdb.readonly {
 		frdb.wg.Add(1)
 		go func() {
 			frdb.freeze(db)
 			frdb.wg.Done()
 		}()
+		rollup.wg.Add(1)
+		go func() {
+			rollup.freeze(fdb)
+			rollup.wg.Done()
+		}()
 	}
-	return &freezerdb{
-		KeyValueStore: db,
-		AncientStore:  frdb,
-	}, nil
+	return fdb, nil
 }
 
 // NewMemoryDatabase creates an ephemeral in-memory key-value database without a
@@ -242,6 +309,54 @@ func NewMemoryDatabaseWithCap(size int) ethdb.Database {
 	return NewDatabase(memorydb.NewWithCap(size))
 }
 
+const (
+	// DBLeveldb is the name of the leveldb key-value store backend.
+	DBLeveldb = "leveldb"
+
+	// DBPebble is the name of the pebble key-value store backend.
+	DBPebble = "pebble"
+)
+
+// PreexistingDatabase checks the given data directory whether a database is
+// already instantiated at that location, and if so, returns the type of the
+// database (or the empty string).
+func PreexistingDatabase(path string) string {
+	if _, err := os.Stat(filepath.Join(path, "CURRENT")); err != nil {
+		return "" // No pre-existing db
+	}
+	// Pebble writes an OPTIONS file next to the manifest, leveldb doesn't
+	if matches, err := filepath.Glob(filepath.Join(path, "OPTIONS*")); len(matches) > 0 || err != nil {
+		if err != nil {
+			panic(err) // only possible if the pattern is malformed
+		}
+		return DBPebble
+	}
+	return DBLeveldb
+}
+
+// OpenKeyValueStore opens a persistent key-value store with the given engine.
+// If no engine is given, the engine of an existing database is used, and new
+// databases are created with leveldb.
+func OpenKeyValueStore(engine string, file string, cache int, handles int, namespace string, readonly bool) (ethdb.KeyValueStore, error) {
+	existing := PreexistingDatabase(file)
+	if engine != "" && existing != "" && engine != existing {
+		return nil, fmt.Errorf("db.engine choice was %v but found pre-existing %v database in specified data directory", engine, existing)
+	}
+	if engine == "" {
+		engine = existing
+	}
+	switch engine {
+	case DBPebble:
+		log.Info("Using pebble as the backing database")
+		return newPebbleKeyValueStore(file, cache, handles, namespace, readonly)
+	case DBLeveldb, "":
+		log.Info("Using leveldb as the backing database")
+		return leveldb.New(file, cache, handles, namespace, readonly)
+	default:
+		return nil, fmt.Errorf("unknown db.engine %v", engine)
+	}
+}
+
 // NewLevelDBDatabase creates a persistent key-value database without a freezer
 // moving immutable chain segments into cold storage.
 func NewLevelDBDatabase(file string, cache int, handles int, namespace string, readonly bool) (ethdb.Database, error) {
@@ -322,10 +437,20 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		storageSnaps    stat
 		preimages       stat
 		bloomBits       stat
+		logIndex        stat
+		accountHistory  stat
 		cliqueSnaps     stat
 		l1Messages      stat
 		l1MessagesOld   stat
 		lastL1Message   stat
+		l1MsgOrigins    stat
+		l1MsgLookups    stat
+		skippedTxs      stat
+		skippedTxHashes stat
+		skippedTxFrozen stat
+		rowConsumptions stat
+		batchChunks     stat
+		batchMetas      stat
 
 		// Ancient store statistics
 		ancientHeadersSize  common.StorageSize
@@ -383,6 +508,14 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 			bloomBits.Add(size)
 		case bytes.HasPrefix(key, BloomBitsIndexPrefix):
 			bloomBits.Add(size)
+		case bytes.HasPrefix(key, logIndexPrefix) || bytes.HasPrefix(key, LogIndexIndexPrefix):
+			logIndex.Add(size)
+		case bytes.HasPrefix(key, accountHistoryPrefix) && len(key) == len(accountHistoryPrefix)+common.AddressLength+12:
+			accountHistory.Add(size)
+		case bytes.HasPrefix(key, untracedHistoryPrefix) && len(key) == len(untracedHistoryPrefix)+8:
+			accountHistory.Add(size)
+		case bytes.HasPrefix(key, AccountHistoryIndexPrefix):
+			accountHistory.Add(size)
 		case bytes.HasPrefix(key, []byte("clique-")) && len(key) == 7+common.HashLength:
 			cliqueSnaps.Add(size)
 		case bytes.HasPrefix(key, l1MessagePrefix) && len(key) == len(l1MessagePrefix)+8:
@@ -391,6 +524,24 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 			l1MessagesOld.Add(size)
 		case bytes.HasPrefix(key, firstQueueIndexNotInL2BlockPrefix) && len(key) == len(firstQueueIndexNotInL2BlockPrefix)+common.HashLength:
 			lastL1Message.Add(size)
+		case bytes.HasPrefix(key, l1MessageOriginPrefix) && len(key) == len(l1MessageOriginPrefix)+8:
+			l1MsgOrigins.Add(size)
+		case bytes.HasPrefix(key, l1TxMessagePrefix) && len(key) == len(l1TxMessagePrefix)+common.HashLength+8:
+			l1MsgOrigins.Add(size)
+		case bytes.HasPrefix(key, l1MessageLookupPrefix) && len(key) == len(l1MessageLookupPrefix)+8:
+			l1MsgLookups.Add(size)
+		case bytes.HasPrefix(key, skippedTransactionPrefix) && len(key) == len(skippedTransactionPrefix)+common.HashLength:
+			skippedTxs.Add(size)
+		case bytes.HasPrefix(key, skippedTransactionHashPrefix) && len(key) == len(skippedTransactionHashPrefix)+8:
+			skippedTxHashes.Add(size)
+		case bytes.HasPrefix(key, skippedTransactionAncientPrefix) && len(key) == len(skippedTransactionAncientPrefix)+common.HashLength:
+			skippedTxFrozen.Add(size)
+		case bytes.HasPrefix(key, rowConsumptionPrefix) && len(key) == len(rowConsumptionPrefix)+common.HashLength:
+			rowConsumptions.Add(size)
+		case bytes.HasPrefix(key, batchChunkRangesPrefix) && len(key) == len(batchChunkRangesPrefix)+8:
+			batchChunks.Add(size)
+		case bytes.HasPrefix(key, batchMetaPrefix) && len(key) == len(batchMetaPrefix)+8:
+			batchMetas.Add(size)
 		case bytes.HasPrefix(key, []byte("cht-")) ||
 			bytes.HasPrefix(key, []byte("chtIndexV2-")) ||
 			bytes.HasPrefix(key, []byte("chtRootV2-")): // Canonical hash trie
@@ -405,7 +556,8 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 				databaseVersionKey, headHeaderKey, headBlockKey, headFastBlockKey, lastPivotKey,
 				fastTrieProgressKey, snapshotDisabledKey, SnapshotRootKey, snapshotJournalKey,
 				snapshotGeneratorKey, snapshotRecoveryKey, txIndexTailKey, fastTxLookupLimitKey,
-				uncleanShutdownKey, badBlockKey, syncedL1BlockNumberKey,
+				uncleanShutdownKey, badBlockKey, syncedL1BlockNumberKey, highestSyncedQueueIndexKey,
+				rollupEventSyncedL1BlockNumberKey, finalizedL2BlockNumberKey, numSkippedTransactionsKey,
 			} {
 				if bytes.Equal(key, meta) {
 					metadata.Add(size)
@@ -436,6 +588,24 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 	if count, err := db.Ancients(); err == nil {
 		ancients = counter(count)
 	}
+	// Rollup tables are frozen independently of the chain
+	rollupTables := []struct{ name, table string }{
+		{"L1 messages", rollupL1MessageTable},
+		{"Skipped transactions", rollupSkippedTxTable},
+		{"Row consumption", rollupRowConsumptionTable},
+		{"Batch chunk ranges", rollupBatchChunkRangesTable},
+		{"Finalized batch metas", rollupBatchMetaTable},
+	}
+	var rollupStats [][]string
+	for _, t := range rollupTables {
+		size, err := db.AncientSize(t.table)
+		if err != nil {
+			continue
+		}
+		items := rollupAncientItems(db, t.table)
+		total += common.StorageSize(size)
+		rollupStats = append(rollupStats, []string{"Ancient store", t.name, common.StorageSize(size).String(), counter(items).String()})
+	}
 	// Display the database statistic.
 	stats := [][]string{
 		{"Key-Value store", "Headers", headers.Size(), headers.Count()},
@@ -446,6 +616,8 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		{"Key-Value store", "Block hash->number", hashNumPairings.Size(), hashNumPairings.Count()},
 		{"Key-Value store", "Transaction index", txLookups.Size(), txLookups.Count()},
 		{"Key-Value store", "Bloombit index", bloomBits.Size(), bloomBits.Count()},
+		{"Key-Value store", "Log index", logIndex.Size(), logIndex.Count()},
+		{"Key-Value store", "Account history", accountHistory.Size(), accountHistory.Count()},
 		{"Key-Value store", "Contract codes", codes.Size(), codes.Count()},
 		{"Key-Value store", "Trie nodes", tries.Size(), tries.Count()},
 		{"Key-Value store", "Trie preimages", preimages.Size(), preimages.Count()},
@@ -456,14 +628,25 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		{"Key-Value store", "L1 messages", l1Messages.Size(), l1Messages.Count()},
 		{"Key-Value store", "L1 messages (legacy prefix)", l1MessagesOld.Size(), l1MessagesOld.Count()},
 		{"Key-Value store", "Last L1 message", lastL1Message.Size(), lastL1Message.Count()},
+		{"Key-Value store", "L1 message origins", l1MsgOrigins.Size(), l1MsgOrigins.Count()},
+		{"Key-Value store", "L1 message lookups", l1MsgLookups.Size(), l1MsgLookups.Count()},
+		{"Key-Value store", "Skipped transactions", skippedTxs.Size(), skippedTxs.Count()},
+		{"Key-Value store", "Skipped transaction index", skippedTxHashes.Size(), skippedTxHashes.Count()},
+		{"Key-Value store", "Skipped tx ancient index", skippedTxFrozen.Size(), skippedTxFrozen.Count()},
+		{"Key-Value store", "Row consumption", rowConsumptions.Size(), rowConsumptions.Count()},
+		{"Key-Value store", "Batch chunk ranges", batchChunks.Size(), batchChunks.Count()},
+		{"Key-Value store", "Finalized batch metas", batchMetas.Size(), batchMetas.Count()},
 		{"Ancient store", "Headers", ancientHeadersSize.String(), ancients.String()},
 		{"Ancient store", "Bodies", ancientBodiesSize.String(), ancients.String()},
 		{"Ancient store", "Receipt lists", ancientReceiptsSize.String(), ancients.String()},
 		{"Ancient store", "Difficulties", ancientTdsSize.String(), ancients.String()},
 		{"Ancient store", "Block number->hash", ancientHashesSize.String(), ancients.String()},
+	}
+	stats = append(stats, rollupStats...)
+	stats = append(stats, [][]string{
 		{"Light client", "CHT trie nodes", chtTrieNodes.Size(), chtTrieNodes.Count()},
 		{"Light client", "Bloom trie nodes", bloomTrieNodes.Size(), bloomTrieNodes.Count()},
-	}
+	}...)
 	table := tablewriter.NewWriter(os.Stdout)
 	table.SetHeader([]string{"Database", "Category", "Size", "Items"})
 	table.SetFooter([]string{"", "Total", total.String(), " "})
@@ -0,0 +1,67 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build (arm64 || amd64) && !openbsd
+// +build arm64 amd64
+// +build !openbsd
+
+package rawdb
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// Tests that the engine of an existing database is detected and that opening it
+// with a different engine is refused.
+func TestOpenKeyValueStore(t *testing.T) {
+	for _, engine := range []string{DBLeveldb, DBPebble} {
+		dir := filepath.Join(t.TempDir(), engine)
+		if have := PreexistingDatabase(dir); have != "" {
+			t.Fatalf("%s: pre-existing database mismatch: have %q, want none", engine, have)
+		}
+		db, err := OpenKeyValueStore(engine, dir, 16, 16, "", false)
+		if err != nil {
+			t.Fatalf("%s: failed to create database: %v", engine, err)
+		}
+		if err := db.Put([]byte("key"), []byte("value")); err != nil {
+			t.Fatalf("%s: failed to write: %v", engine, err)
+		}
+		if _, err := db.Get([]byte("missing")); !IsNotFoundErr(err) {
+			t.Fatalf("%s: missing key error mismatch: have %v", engine, err)
+		}
+		db.Close()
+
+		if have := PreexistingDatabase(dir); have != engine {
+			t.Fatalf("%s: pre-existing database mismatch: have %q, want %q", engine, have, engine)
+		}
+		other := DBPebble
+		if engine == DBPebble {
+			other = DBLeveldb
+		}
+		if _, err := OpenKeyValueStore(other, dir, 16, 16, "", false); err == nil {
+			t.Fatalf("%s: opened database with %s", engine, other)
+		}
+		db, err = OpenKeyValueStore("", dir, 16, 16, "", false)
+		if err != nil {
+			t.Fatalf("%s: failed to reopen database: %v", engine, err)
+		}
+		if val, err := db.Get([]byte("key")); err != nil || string(val) != "value" {
+			t.Fatalf("%s: value mismatch after reopen: have %q, %v", engine, val, err)
+		}
+		db.Close()
+	}
+}
@@ -0,0 +1,451 @@
+package rawdb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/ethdb"
+	"github.com/scroll-tech/go-ethereum/log"
+)
+
+// rollupFreezer moves rollup metadata of finalized L2 blocks and batches from
+// the key-value store into append-only freezer tables. Every table group has
+// its own freezer, since the tables of a freezer must have the same length.
+type rollupFreezer struct {
+	readonly bool
+	groups   map[string]*freezer // Freezer of every table group
+	tables   map[string]*freezer // Freezer holding every table
+
+	trigger chan chan struct{} // Manual blocking freeze trigger, test determinism
+
+	quit      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// newRollupFreezer opens the rollup freezer tables in the rollup directory of
+// the ancient store. A read only rollup freezer is only opened if it exists.
+func newRollupFreezer(datadir string, namespace string, readonly bool) (*rollupFreezer, error) {
+	datadir = filepath.Join(datadir, "rollup")
+	if _, err := os.Stat(datadir); readonly && os.IsNotExist(err) {
+		return nil, nil
+	}
+	f := &rollupFreezer{
+		readonly: readonly,
+		groups:   make(map[string]*freezer),
+		tables:   make(map[string]*freezer),
+		trigger:  make(chan chan struct{}),
+		quit:     make(chan struct{}),
+	}
+	for group, tables := range RollupFreezerTables {
+		fr, err := newFreezer(filepath.Join(datadir, group), namespace+"rollup/"+group+"/", readonly, freezerTableSize, tables)
+		if err != nil {
+			f.close()
+			return nil, err
+		}
+		f.groups[group] = fr
+		for table := range tables {
+			f.tables[table] = fr
+		}
+	}
+	return f, nil
+}
+
+// Close stops the background freezing and closes all rollup freezer tables.
+func (f *rollupFreezer) Close() error {
+	f.closeOnce.Do(func() {
+		close(f.quit)
+		f.wg.Wait()
+	})
+	return f.close()
+}
+
+func (f *rollupFreezer) close() error {
+	var errs []error
+	for _, fr := range f.groups {
+		if err := fr.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if errs != nil {
+		return fmt.Errorf("%v", errs)
+	}
+	return nil
+}
+
+// owns returns whether the given table is a rollup freezer table.
+func (f *rollupFreezer) owns(kind string) bool {
+	_, ok := f.tables[kind]
+	return ok
+}
+
+// HasAncient returns an indicator whether the specified rollup data exists in
+// the freezer.
+func (f *rollupFreezer) HasAncient(kind string, number uint64) (bool, error) {
+	return f.tables[kind].HasAncient(kind, number)
+}
+
+// Ancient retrieves rollup data from the append-only immutable files.
+func (f *rollupFreezer) Ancient(kind string, number uint64) ([]byte, error) {
+	return f.tables[kind].Ancient(kind, number)
+}
+
+// AncientRange retrieves multiple items in sequence, starting from the index 'start'.
+func (f *rollupFreezer) AncientRange(kind string, start, count, maxBytes uint64) ([][]byte, error) {
+	return f.tables[kind].AncientRange(kind, start, count, maxBytes)
+}
+
+// AncientSize returns the ancient size of the specified table.
+func (f *rollupFreezer) AncientSize(kind string) (uint64, error) {
+	return f.tables[kind].AncientSize(kind)
+}
+
+// freeze is a background thread that periodically moves the rollup metadata of
+// finalized blocks and batches from the key-value store into the freezer. The
+// database is needed to look up canonical hashes that might be frozen already.
+func (f *rollupFreezer) freeze(db ethdb.Database) {
+	var triggered chan struct{} // Used in tests
+	for {
+		if err := f.freezeFinalized(db); err != nil {
+			log.Error("Error in rollup freeze operation", "err", err)
+		}
+		// If we were doing a manual trigger, notify it
+		if triggered != nil {
+			triggered <- struct{}{}
+			triggered = nil
+		}
+		select {
+		case <-time.NewTimer(freezerRecheckInterval).C:
+		case triggered = <-f.trigger:
+		case <-f.quit:
+			log.Info("Rollup freezer shutting down")
+			return
+		}
+	}
+}
+
+// freezeFinalized moves all finalized rollup metadata into the freezer, one
+// batch of items at a time.
+func (f *rollupFreezer) freezeFinalized(db ethdb.Database) error {
+	steps := []func(ethdb.Database) (int, error){
+		f.freezeRowConsumption,
+		f.freezeL1Messages,
+		f.freezeSkippedTransactions,
+		f.freezeBatches,
+	}
+	for _, step := range steps {
+		for {
+			select {
+			case <-f.quit:
+				return nil
+			default:
+			}
+			start := time.Now()
+			frozen, err := step(db)
+			if err != nil {
+				return err
+			}
+			if frozen > 0 {
+				log.Info("Deep froze rollup metadata", "items", frozen, "elapsed", common.PrettyDuration(time.Since(start)))
+			}
+			if frozen < freezerBatchLimit {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// append writes a batch of items into the given group, flushes it and then
+// deletes the frozen data from the key-value store.
+func (f *rollupFreezer) append(db ethdb.KeyValueStore, group string, fn func(op ethdb.AncientWriteOp) error, wipe func(batch ethdb.Batch)) error {
+	fr := f.groups[group]
+	if _, err := fr.ModifyAncients(fn); err != nil {
+		return err
+	}
+	if err := fr.Sync(); err != nil {
+		log.Crit("Failed to flush frozen rollup tables", "group", group, "err", err)
+	}
+	batch := db.NewBatch()
+	wipe(batch)
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to delete frozen rollup metadata", "group", group, "err", err)
+	}
+	return nil
+}
+
+// readFrozenCandidate reads a value that is about to be frozen from the
+// key-value store.
+func readFrozenCandidate(db ethdb.KeyValueReader, key []byte) ([]byte, error) {
+	data, err := db.Get(key)
+	if err != nil && !IsNotFoundErr(err) {
+		return nil, err
+	}
+	return data, nil
+}
+
+// freezeRowConsumption freezes the row consumption of finalized L2 blocks, by
+// block number. The table starts at the first block whose row consumption was
+// found in the key-value store, later blocks without row consumption are stored
+// as empty items.
+func (f *rollupFreezer) freezeRowConsumption(db ethdb.Database) (int, error) {
+	finalized := ReadFinalizedL2BlockNumber(db)
+	if finalized == nil {
+		return 0, nil
+	}
+	tail := readRowConsumptionTail(db)
+	if tail == nil {
+		number, ok := lowestRowConsumptionBlock(db)
+		if !ok || number > *finalized {
+			return 0, nil
+		}
+		writeRowConsumptionTail(db, number)
+		tail = &number
+	}
+	frozen, _ := f.groups["rowconsumption"].Ancients()
+	first := *tail + frozen
+	if first > *finalized {
+		return 0, nil
+	}
+	limit := *finalized
+	if limit-first >= freezerBatchLimit {
+		limit = first + freezerBatchLimit - 1
+	}
+	var hashes []common.Hash
+	err := f.append(db, "rowconsumption", func(op ethdb.AncientWriteOp) error {
+		for number := first; number <= limit; number++ {
+			hash := ReadCanonicalHash(db, number)
+			if hash == (common.Hash{}) {
+				return fmt.Errorf("canonical hash missing, can't freeze row consumption of block %d", number)
+			}
+			data, err := readFrozenCandidate(db, rowConsumptionKey(hash))
+			if err != nil {
+				return err
+			}
+			if err := op.AppendRaw(rollupRowConsumptionTable, number-*tail, data); err != nil {
+				return fmt.Errorf("can't write row consumption to freezer: %v", err)
+			}
+			hashes = append(hashes, hash)
+		}
+		return nil
+	}, func(batch ethdb.Batch) {
+		for _, hash := range hashes {
+			batch.Delete(rowConsumptionKey(hash))
+		}
+	})
+	return len(hashes), err
+}
+
+// freezeL1Messages freezes the L1 messages included in finalized L2 blocks, by
+// queue index.
+func (f *rollupFreezer) freezeL1Messages(db ethdb.Database) (int, error) {
+	finalized := ReadFinalizedL2BlockNumber(db)
+	if finalized == nil {
+		return 0, nil
+	}
+	hash := ReadCanonicalHash(db, *finalized)
+	if hash == (common.Hash{}) {
+		return 0, nil
+	}
+	end := ReadFirstQueueIndexNotInL2Block(db, hash)
+	first, _ := f.groups["l1messages"].Ancients()
+	if end == nil || first >= *end {
+		return 0, nil
+	}
+	limit := *end
+	if limit-first > freezerBatchLimit {
+		limit = first + freezerBatchLimit
+	}
+	err := f.append(db, "l1messages", func(op ethdb.AncientWriteOp) error {
+		for index := first; index < limit; index++ {
+			data, err := readFrozenCandidate(db, L1MessageKey(index))
+			if err != nil {
+				return err
+			}
+			if len(data) == 0 {
+				return fmt.Errorf("L1 message missing, can't freeze queue index %d", index)
+			}
+			if err := op.AppendRaw(rollupL1MessageTable, index, data); err != nil {
+				return fmt.Errorf("can't write L1 message to freezer: %v", err)
+			}
+		}
+		return nil
+	}, func(batch ethdb.Batch) {
+		for index := first; index < limit; index++ {
+			batch.Delete(L1MessageKey(index))
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(limit - first), nil
+}
+
+// freezeSkippedTransactions freezes the transactions skipped in finalized L2
+// blocks, by skip index. The transaction hash is mapped to its skip index so
+// that lookups by hash keep working, while the hash of a frozen skip index is
+// read from the frozen transaction itself. A transaction skipped again at an
+// unfrozen index keeps its record until that index is frozen too.
+func (f *rollupFreezer) freezeSkippedTransactions(db ethdb.Database) (int, error) {
+	finalized := ReadFinalizedL2BlockNumber(db)
+	if finalized == nil {
+		return 0, nil
+	}
+	var (
+		first, _  = f.groups["skippedtxs"].Ancients()
+		count     = ReadNumSkippedTransactions(db)
+		hashes    []common.Hash
+		reskipped = make(map[common.Hash]struct{})
+	)
+	err := f.append(db, "skippedtxs", func(op ethdb.AncientWriteOp) error {
+		for index := first; index < count && index-first < freezerBatchLimit; index++ {
+			hash := ReadSkippedTransactionHash(db, index)
+			if hash == nil {
+				return fmt.Errorf("skipped transaction hash missing, can't freeze skip index %d", index)
+			}
+			// A transaction skipped multiple times might be frozen already
+			data := readSkippedTransactionRLP(db, *hash)
+			if len(data) == 0 {
+				return fmt.Errorf("skipped transaction missing, can't freeze skip index %d", index)
+			}
+			stx, err := decodeSkippedTransaction(data)
+			if err != nil {
+				return err
+			}
+			if stx.BlockNumber > *finalized {
+				break
+			}
+			if err := op.AppendRaw(rollupSkippedTxTable, index, data); err != nil {
+				return fmt.Errorf("can't write skipped transaction to freezer: %v", err)
+			}
+			hashes = append(hashes, *hash)
+		}
+		// The record of a transaction skipped again at an unfrozen index
+		// belongs to that skip and must stay in the key-value store
+		for index := first + uint64(len(hashes)); index < count; index++ {
+			if hash := ReadSkippedTransactionHash(db, index); hash != nil {
+				reskipped[*hash] = struct{}{}
+			}
+		}
+		return nil
+	}, func(batch ethdb.Batch) {
+		for i, hash := range hashes {
+			batch.Delete(SkippedTransactionHashKey(first + uint64(i)))
+			if _, ok := reskipped[hash]; ok {
+				continue
+			}
+			batch.Delete(SkippedTransactionKey(hash))
+			batch.Put(skippedTransactionAncientKey(hash), encodeBigEndian(first+uint64(i)))
+		}
+	})
+	return len(hashes), err
+}
+
+// freezeBatches freezes the chunk ranges and metadata of finalized batches, by
+// batch index. Batches finalized before the rollup sync started are stored as
+// empty items.
+func (f *rollupFreezer) freezeBatches(db ethdb.Database) (int, error) {
+	// Batches before the first one synced are not available
+	first, _ := f.groups["batches"].Ancients()
+	start := lowestFinalizedBatchIndex(db)
+	if start < first {
+		start = first
+	}
+	var indices []uint64
+	err := f.append(db, "batches", func(op ethdb.AncientWriteOp) error {
+		for index := first; index-first < freezerBatchLimit; index++ {
+			var ranges, meta []byte
+			if index >= start {
+				var err error
+				if meta, err = readFrozenCandidate(db, batchMetaKey(index)); err != nil {
+					return err
+				}
+				if len(meta) == 0 {
+					break // Not finalized yet
+				}
+				if ranges, err = readFrozenCandidate(db, batchChunkRangesKey(index)); err != nil {
+					return err
+				}
+			}
+			if err := op.AppendRaw(rollupBatchChunkRangesTable, index, ranges); err != nil {
+				return fmt.Errorf("can't write batch chunk ranges to freezer: %v", err)
+			}
+			if err := op.AppendRaw(rollupBatchMetaTable, index, meta); err != nil {
+				return fmt.Errorf("can't write batch metadata to freezer: %v", err)
+			}
+			indices = append(indices, index)
+		}
+		return nil
+	}, func(batch ethdb.Batch) {
+		for _, index := range indices {
+			batch.Delete(batchChunkRangesKey(index))
+			batch.Delete(batchMetaKey(index))
+		}
+	})
+	return len(indices), err
+}
+
+// lowestRowConsumptionBlock returns the number of the first canonical block
+// whose row consumption is in the key-value store.
+func lowestRowConsumptionBlock(db ethdb.Database) (uint64, bool) {
+	it := db.NewIterator(rowConsumptionPrefix, nil)
+	defer it.Release()
+
+	var (
+		lowest uint64
+		found  bool
+	)
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(rowConsumptionPrefix)+common.HashLength {
+			continue
+		}
+		hash := common.BytesToHash(key[len(rowConsumptionPrefix):])
+		number := ReadHeaderNumber(db, hash)
+		if number == nil || (found && *number >= lowest) || ReadCanonicalHash(db, *number) != hash {
+			continue
+		}
+		lowest, found = *number, true
+	}
+	return lowest, found
+}
+
+// lowestFinalizedBatchIndex returns the index of the first finalized batch in
+// the key-value store, or zero if there is none.
+func lowestFinalizedBatchIndex(db ethdb.Iteratee) uint64 {
+	it := db.NewIterator(batchMetaPrefix, nil)
+	defer it.Release()
+
+	for it.Next() {
+		if key := it.Key(); len(key) == len(batchMetaPrefix)+8 {
+			return binary.BigEndian.Uint64(key[len(batchMetaPrefix):])
+		}
+	}
+	return 0
+}
+
+// rollupAncientItems returns the number of items frozen in a rollup table. The
+// items are counted by probing, since the rollup tables are not chain aligned.
+func rollupAncientItems(db ethdb.AncientReader, kind string) uint64 {
+	return uint64(sort.Search(math.MaxInt32, func(n int) bool {
+		ok, _ := db.HasAncient(kind, uint64(n))
+		return !ok
+	}))
+}
+
+// readRollupAncient retrieves rollup data from the ancient store, returning nil
+// if it is not frozen or stored as an empty item.
+func readRollupAncient(db ethdb.AncientReader, kind string, number uint64) []byte {
+	data, err := db.Ancient(kind, number)
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+	return data
+}
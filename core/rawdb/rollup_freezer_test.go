@@ -0,0 +1,227 @@
+package rawdb
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/ethdb"
+)
+
+func TestRollupFreezer(t *testing.T) {
+	kvdb := NewMemoryDatabase()
+	db, err := NewDatabaseWithFreezer(kvdb, t.TempDir(), "", false)
+	if err != nil {
+		t.Fatalf("failed to create database with ancient backend: %v", err)
+	}
+	defer db.Close()
+
+	// Odd blocks include two L1 messages each, blocks 2 and 5 skip a transaction.
+	// The genesis block has no row consumption.
+	var hashes []common.Hash
+	for i := uint64(0); i < 6; i++ {
+		header := &types.Header{Number: new(big.Int).SetUint64(i), Extra: []byte("test block")}
+		WriteHeader(db, header)
+		WriteCanonicalHash(db, header.Hash(), i)
+		if i > 0 {
+			WriteBlockRowConsumption(db, header.Hash(), &types.RowConsumption{{Name: "a", RowNumber: i + 1}})
+		}
+		WriteFirstQueueIndexNotInL2Block(db, header.Hash(), (i+1)/2*2)
+		hashes = append(hashes, header.Hash())
+	}
+	for i := uint64(0); i < 8; i++ {
+		WriteL1Message(db, types.L1MessageTx{QueueIndex: i, To: &common.Address{}, Value: big.NewInt(0)})
+	}
+	WriteHighestSyncedQueueIndex(db, 7)
+	skipped := []*types.Transaction{newTestTransaction(100), newTestTransaction(101)}
+	WriteSkippedTransaction(db, skipped[0], nil, "random reason", 2, &hashes[2])
+	WriteSkippedTransaction(db, skipped[1], nil, "random reason", 5, &hashes[5])
+
+	// Batches before the first synced one are not available
+	for _, index := range []uint64{2, 3} {
+		WriteBatchChunkRanges(db, index, []*ChunkBlockRange{{StartBlockNumber: index, EndBlockNumber: index}})
+		WriteFinalizedBatchMeta(db, index, &FinalizedBatchMeta{TotalL1MessagePopped: index})
+	}
+	WriteFinalizedL2BlockNumber(db, 3)
+
+	if err := db.(*freezerdb).FreezeRollup(); err != nil {
+		t.Fatalf("failed to freeze rollup metadata: %v", err)
+	}
+
+	// Finalized metadata must be moved out of the key-value store
+	frozen := [][]byte{
+		rowConsumptionKey(hashes[1]), rowConsumptionKey(hashes[3]),
+		L1MessageKey(0), L1MessageKey(3),
+		SkippedTransactionKey(skipped[0].Hash()), SkippedTransactionHashKey(0),
+		batchChunkRangesKey(3), batchMetaKey(3),
+	}
+	for _, key := range frozen {
+		if ok, _ := kvdb.Has(key); ok {
+			t.Errorf("key %x not frozen", key)
+		}
+	}
+	live := [][]byte{
+		rowConsumptionKey(hashes[4]),
+		L1MessageKey(4),
+		SkippedTransactionKey(skipped[1].Hash()), SkippedTransactionHashKey(1),
+	}
+	for _, key := range live {
+		if ok, _ := kvdb.Has(key); !ok {
+			t.Errorf("key %x frozen before finalization", key)
+		}
+	}
+
+	// Row consumption must be frozen from the first block that has it
+	if tail := readRowConsumptionTail(db); tail == nil || *tail != 1 {
+		t.Errorf("row consumption tail mismatch: have %v, want 1", tail)
+	}
+	if items, _ := db.(*freezerdb).rollup.groups["rowconsumption"].Ancients(); items != 3 {
+		t.Errorf("frozen row consumption count mismatch: have %d, want 3", items)
+	}
+
+	// All metadata must still be readable
+	if rc := ReadBlockRowConsumption(db, hashes[0]); rc != nil {
+		t.Errorf("unexpected row consumption of block 0: %v", rc)
+	}
+	for i, hash := range hashes[1:] {
+		rc := ReadBlockRowConsumption(db, hash)
+		if rc == nil || (*rc)[0].RowNumber != uint64(i)+2 {
+			t.Errorf("row consumption of block %d mismatch: %v", i+1, rc)
+		}
+	}
+	for _, tx := range skipped {
+		if stx := ReadSkippedTransaction(db, tx.Hash()); stx == nil || stx.Tx.Hash() != tx.Hash() {
+			t.Errorf("skipped transaction %x not found", tx.Hash())
+		}
+	}
+	checkSkippedTransactionHashes(t, db, skipped)
+	for _, index := range []uint64{2, 3} {
+		if meta := ReadFinalizedBatchMeta(db, index); meta == nil || meta.TotalL1MessagePopped != index {
+			t.Errorf("batch %d metadata mismatch: %v", index, meta)
+		}
+		if ranges := ReadBatchChunkRanges(db, index); len(ranges) != 1 || ranges[0].StartBlockNumber != index {
+			t.Errorf("batch %d chunk ranges mismatch: %v", index, ranges)
+		}
+	}
+	if meta := ReadFinalizedBatchMeta(db, 1); meta != nil {
+		t.Errorf("unexpected metadata for unsynced batch: %v", meta)
+	}
+	checkL1Messages(t, db, 2, 6)
+	checkL1Messages(t, db, 5, 3)
+
+	// Freezing again after finalization progressed must continue from the frozen items
+	WriteBatchChunkRanges(db, 4, []*ChunkBlockRange{{StartBlockNumber: 4, EndBlockNumber: 5}})
+	WriteFinalizedBatchMeta(db, 4, &FinalizedBatchMeta{TotalL1MessagePopped: 4})
+	WriteFinalizedL2BlockNumber(db, 5)
+
+	if err := db.(*freezerdb).FreezeRollup(); err != nil {
+		t.Fatalf("failed to freeze rollup metadata: %v", err)
+	}
+	for _, key := range [][]byte{rowConsumptionKey(hashes[5]), L1MessageKey(5), SkippedTransactionKey(skipped[1].Hash()), batchMetaKey(4)} {
+		if ok, _ := kvdb.Has(key); ok {
+			t.Errorf("key %x not frozen", key)
+		}
+	}
+	if ok, _ := kvdb.Has(L1MessageKey(6)); !ok {
+		t.Errorf("L1 message 6 frozen before inclusion")
+	}
+	if stx := ReadSkippedTransaction(db, skipped[1].Hash()); stx == nil || stx.BlockNumber != 5 {
+		t.Errorf("skipped transaction mismatch: %v", stx)
+	}
+	checkSkippedTransactionHashes(t, db, skipped)
+	if meta := ReadFinalizedBatchMeta(db, 4); meta == nil || meta.TotalL1MessagePopped != 4 {
+		t.Errorf("batch 4 metadata mismatch: %v", meta)
+	}
+	checkL1Messages(t, db, 0, 8)
+}
+
+func TestRollupFreezerReskippedTransaction(t *testing.T) {
+	kvdb := NewMemoryDatabase()
+	db, err := NewDatabaseWithFreezer(kvdb, t.TempDir(), "", false)
+	if err != nil {
+		t.Fatalf("failed to create database with ancient backend: %v", err)
+	}
+	defer db.Close()
+
+	// The first transaction is skipped in block 2 and, after a reorg of block
+	// 5, skipped again in block 3.
+	tx, other := newTestTransaction(100), newTestTransaction(101)
+	WriteSkippedTransaction(db, tx, nil, "random reason", 2, &common.Hash{2})
+	WriteSkippedTransaction(db, other, nil, "random reason", 5, &common.Hash{5})
+	WriteSkippedTransaction(db, tx, nil, "random reason", 3, &common.Hash{3})
+	skipped := []*types.Transaction{tx, other, tx}
+
+	WriteFinalizedL2BlockNumber(db, 3)
+	if err := db.(*freezerdb).FreezeRollup(); err != nil {
+		t.Fatalf("failed to freeze rollup metadata: %v", err)
+	}
+	if items := rollupAncientItems(db, rollupSkippedTxTable); items != 1 {
+		t.Fatalf("frozen skipped transaction count mismatch: have %d, want 1", items)
+	}
+	// The record of the unfrozen skip must not be removed with the frozen one
+	if ok, _ := kvdb.Has(SkippedTransactionKey(tx.Hash())); !ok {
+		t.Errorf("skipped transaction record frozen before its last skip")
+	}
+	if stx := ReadSkippedTransaction(db, tx.Hash()); stx == nil || stx.BlockNumber != 3 {
+		t.Errorf("skipped transaction mismatch: %v", stx)
+	}
+	checkSkippedTransactionHashes(t, db, skipped)
+
+	WriteFinalizedL2BlockNumber(db, 5)
+	if err := db.(*freezerdb).FreezeRollup(); err != nil {
+		t.Fatalf("failed to freeze rollup metadata: %v", err)
+	}
+	for _, hash := range []common.Hash{tx.Hash(), other.Hash()} {
+		if ok, _ := kvdb.Has(SkippedTransactionKey(hash)); ok {
+			t.Errorf("skipped transaction %x not frozen", hash)
+		}
+	}
+	if stx := ReadSkippedTransaction(db, tx.Hash()); stx == nil || stx.BlockNumber != 3 {
+		t.Errorf("skipped transaction mismatch: %v", stx)
+	}
+	checkSkippedTransactionHashes(t, db, skipped)
+}
+
+func checkSkippedTransactionHashes(t *testing.T, db ethdb.Database, skipped []*types.Transaction) {
+	t.Helper()
+
+	for i, tx := range skipped {
+		if hash := ReadSkippedTransactionHash(db, uint64(i)); hash == nil || *hash != tx.Hash() {
+			t.Errorf("skipped transaction hash %d mismatch: have %v, want %x", i, hash, tx.Hash())
+		}
+	}
+	for start := range skipped {
+		it := IterateSkippedTransactionsFrom(db, uint64(start))
+		for i := start; i < len(skipped); i++ {
+			if !it.Next() {
+				t.Fatalf("skipped transaction iterator from %d exhausted at %d", start, i)
+			}
+			if it.Index() != uint64(i) || it.TransactionHash() != skipped[i].Hash() {
+				t.Errorf("skipped transaction iterator mismatch: have %d %x, want %d %x", it.Index(), it.TransactionHash(), i, skipped[i].Hash())
+			}
+		}
+		if it.Next() {
+			t.Errorf("skipped transaction iterator from %d not exhausted", start)
+		}
+		it.Release()
+	}
+}
+
+func checkL1Messages(t *testing.T, db ethdb.Database, start uint64, count int) {
+	t.Helper()
+
+	msgs := ReadL1MessagesFrom(db, start, 100)
+	if len(msgs) != count {
+		t.Fatalf("L1 message count mismatch from %d: have %d, want %d", start, len(msgs), count)
+	}
+	for i, msg := range msgs {
+		if msg.QueueIndex != start+uint64(i) {
+			t.Errorf("L1 message queue index mismatch: have %d, want %d", msg.QueueIndex, start+uint64(i))
+		}
+		if want := ReadL1Message(db, msg.QueueIndex); !reflect.DeepEqual(want, &msg) {
+			t.Errorf("L1 message %d mismatch", msg.QueueIndex)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package rawdb
+
+import (
+	"fmt"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/ethdb"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rlp"
+)
+
+// VerifyL1MessageQueue checks that the L1 messages in the database form a
+// contiguous queue from the given enqueue index up to the highest synced one,
+// and that every message is stored under its own enqueue index. It returns the
+// number of verified messages and an error describing the first violation.
+func VerifyL1MessageQueue(db ethdb.Database, start uint64) (uint64, error) {
+	highest := ReadHighestSyncedQueueIndex(db)
+	if start > highest {
+		return 0, nil
+	}
+	it := IterateL1MessagesFrom(db, start)
+	defer it.Release()
+
+	next := start
+	for it.Next() && it.QueueIndex() <= highest {
+		if it.QueueIndex() != next {
+			return next - start, fmt.Errorf("L1 message queue gap: missing indices %d-%d", next, it.QueueIndex()-1)
+		}
+		var msg types.L1MessageTx
+		if err := rlp.DecodeBytes(it.value, &msg); err != nil {
+			return next - start, fmt.Errorf("invalid L1 message %d: %v", next, err)
+		}
+		if msg.QueueIndex != next {
+			return next - start, fmt.Errorf("L1 message %d stored with queue index %d", next, msg.QueueIndex)
+		}
+		next++
+	}
+	if err := it.Error(); err != nil {
+		return next - start, err
+	}
+	// An empty queue has no highest synced index to reach
+	if next == 0 && highest == 0 {
+		return 0, nil
+	}
+	if next <= highest {
+		return next - start, fmt.Errorf("L1 message queue truncated: missing indices %d-%d", next, highest)
+	}
+	return next - start, nil
+}
+
+// RederiveFirstQueueIndexes recomputes the first L1 message enqueue index not
+// included in each canonical L2 block of the given range from the block bodies,
+// in the same way as block import does. Missing indices and indices below the
+// derived value are rewritten. Indices above it are kept, since the sequencer
+// records trailing skipped messages that the block itself does not contain.
+// It returns the number of rewritten blocks.
+func RederiveFirstQueueIndexes(db ethdb.Database, start, end uint64) (int, error) {
+	var (
+		batch   = db.NewBatch()
+		fixed   int
+		current uint64 // Index after the previous block
+	)
+	for number := start; number <= end; number++ {
+		hash := ReadCanonicalHash(db, number)
+		if hash == (common.Hash{}) {
+			return fixed, fmt.Errorf("canonical hash missing for block %d", number)
+		}
+		block := ReadBlock(db, hash, number)
+		if block == nil {
+			return fixed, fmt.Errorf("block %d (%x) missing", number, hash)
+		}
+		switch {
+		case number == 0:
+			current = 0
+		case number == start:
+			parent := ReadFirstQueueIndexNotInL2Block(db, block.ParentHash())
+			if parent == nil {
+				return fixed, fmt.Errorf("first queue index missing for parent of block %d, rederive from an earlier block", number)
+			}
+			current = *parent
+		}
+		derived := current + uint64(block.NumL1MessagesProcessed(current))
+
+		stored := ReadFirstQueueIndexNotInL2Block(db, hash)
+		switch {
+		case stored == nil || *stored < derived:
+			log.Info("Rewriting first queue index not in L2 block", "number", number, "hash", hash, "stored", stored, "derived", derived)
+			WriteFirstQueueIndexNotInL2Block(batch, hash, derived)
+			current = derived
+			fixed++
+		case *stored > derived:
+			log.Warn("Keeping first queue index beyond block messages", "number", number, "hash", hash, "stored", *stored, "derived", derived)
+			current = *stored
+		default:
+			current = derived
+		}
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return fixed, err
+			}
+			batch.Reset()
+		}
+	}
+	return fixed, batch.Write()
+}
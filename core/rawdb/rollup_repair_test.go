@@ -0,0 +1,92 @@
+package rawdb
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/rlp"
+)
+
+func TestVerifyL1MessageQueue(t *testing.T) {
+	db := NewMemoryDatabase()
+	if n, err := VerifyL1MessageQueue(db, 0); n != 0 || err != nil {
+		t.Fatalf("empty queue: have %d, %v", n, err)
+	}
+	for i := uint64(0); i < 5; i++ {
+		WriteL1Message(db, newL1MessageTx(i))
+	}
+	if n, err := VerifyL1MessageQueue(db, 0); n != 5 || err != nil {
+		t.Fatalf("contiguous queue: have %d, %v", n, err)
+	}
+	if n, err := VerifyL1MessageQueue(db, 3); n != 2 || err != nil {
+		t.Fatalf("contiguous queue from 3: have %d, %v", n, err)
+	}
+
+	// Messages beyond the highest synced index are not verified
+	WriteL1Message(db, newL1MessageTx(9))
+	WriteHighestSyncedQueueIndex(db, 4)
+	if n, err := VerifyL1MessageQueue(db, 0); n != 5 || err != nil {
+		t.Fatalf("unsynced message: have %d, %v", n, err)
+	}
+	WriteHighestSyncedQueueIndex(db, 10)
+	if n, err := VerifyL1MessageQueue(db, 0); n != 5 || err == nil {
+		t.Fatalf("queue gap: have %d, %v", n, err)
+	}
+	WriteHighestSyncedQueueIndex(db, 4)
+
+	// A message stored under the wrong index must be reported
+	msg := newL1MessageTx(1)
+	data, _ := rlp.EncodeToBytes(msg)
+	db.Put(L1MessageKey(2), data)
+	if n, err := VerifyL1MessageQueue(db, 0); n != 2 || err == nil {
+		t.Fatalf("misplaced message: have %d, %v", n, err)
+	}
+}
+
+func TestRederiveFirstQueueIndexes(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	// Block 1 includes messages 0-1, block 2 none, block 3 messages 2 and 4
+	var (
+		parent common.Hash
+		hashes []common.Hash
+	)
+	for i, indices := range [][]uint64{nil, {0, 1}, nil, {2, 4}} {
+		var txs []*types.Transaction
+		for _, index := range indices {
+			msg := newL1MessageTx(index)
+			txs = append(txs, types.NewTx(&msg))
+		}
+		header := &types.Header{Number: big.NewInt(int64(i)), ParentHash: parent}
+		block := types.NewBlockWithHeader(header).WithBody(txs, nil)
+		WriteBlock(db, block)
+		WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		parent = block.Hash()
+		hashes = append(hashes, block.Hash())
+	}
+	if _, err := RederiveFirstQueueIndexes(db, 1, 3); err == nil {
+		t.Fatal("expected error for missing parent index")
+	}
+	WriteFirstQueueIndexNotInL2Block(db, hashes[0], 0)
+	WriteFirstQueueIndexNotInL2Block(db, hashes[2], 1) // lagging, rewritten
+	WriteFirstQueueIndexNotInL2Block(db, hashes[3], 6) // trailing skipped message, kept
+
+	fixed, err := RederiveFirstQueueIndexes(db, 1, 3)
+	if err != nil {
+		t.Fatalf("failed to rederive: %v", err)
+	}
+	if fixed != 2 {
+		t.Errorf("rewritten blocks mismatch: have %d, want 2", fixed)
+	}
+	for i, want := range []uint64{0, 2, 2, 6} {
+		if have := ReadFirstQueueIndexNotInL2Block(db, hashes[i]); have == nil || *have != want {
+			t.Errorf("block %d: have %v, want %d", i, have, want)
+		}
+	}
+	// A rederivation from genesis must be stable
+	if fixed, err := RederiveFirstQueueIndexes(db, 0, 3); fixed != 0 || err != nil {
+		t.Errorf("second rederivation: have %d, %v", fixed, err)
+	}
+}
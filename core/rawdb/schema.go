@@ -88,17 +88,22 @@ var (
 	blockBodyPrefix     = []byte("b") // blockBodyPrefix + num (uint64 big endian) + hash -> block body
 	blockReceiptsPrefix = []byte("r") // blockReceiptsPrefix + num (uint64 big endian) + hash -> block receipts
 
-	txLookupPrefix        = []byte("l") // txLookupPrefix + hash -> transaction/receipt lookup metadata
-	bloomBitsPrefix       = []byte("B") // bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash -> bloom bits
-	SnapshotAccountPrefix = []byte("a") // SnapshotAccountPrefix + account hash -> account trie value
-	SnapshotStoragePrefix = []byte("o") // SnapshotStoragePrefix + account hash + storage hash -> storage trie value
-	CodePrefix            = []byte("c") // CodePrefix + code hash -> account code
+	txLookupPrefix        = []byte("l")  // txLookupPrefix + hash -> transaction/receipt lookup metadata
+	bloomBitsPrefix       = []byte("B")  // bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash -> bloom bits
+	logIndexPrefix        = []byte("LI") // logIndexPrefix + log key + section (uint64 big endian) + hash -> blocks of the section with matching logs
+	accountHistoryPrefix  = []byte("AH") // accountHistoryPrefix + address + ^num (uint64 big endian) + ^tx index (uint32 big endian) -> account history entry
+	untracedHistoryPrefix = []byte("AU") // untracedHistoryPrefix + first num (uint64 big endian) -> last num (uint64 big endian) of blocks indexed without internal calls
+	SnapshotAccountPrefix = []byte("a")  // SnapshotAccountPrefix + account hash -> account trie value
+	SnapshotStoragePrefix = []byte("o")  // SnapshotStoragePrefix + account hash + storage hash -> storage trie value
+	CodePrefix            = []byte("c")  // CodePrefix + code hash -> account code
 
 	PreimagePrefix = []byte("secure-key-")      // PreimagePrefix + hash -> preimage
 	configPrefix   = []byte("ethereum-config-") // config prefix for the db
 
 	// Chain index prefixes (use `i` + single byte to avoid mixing data types).
-	BloomBitsIndexPrefix = []byte("iB") // BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
+	BloomBitsIndexPrefix      = []byte("iB") // BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
+	LogIndexIndexPrefix       = []byte("iL") // LogIndexIndexPrefix is the data table of the log indexer to track its progress
+	AccountHistoryIndexPrefix = []byte("iA") // AccountHistoryIndexPrefix is the data table of the account history indexer to track its progress
 
 	preimageCounter    = metrics.NewRegisteredCounter("db/preimage/total", nil)
 	preimageHitCounter = metrics.NewRegisteredCounter("db/preimage/hits", nil)
@@ -109,20 +114,30 @@ var (
 	l1MessagePrefix                   = []byte("L1") // l1MessagePrefix + queueIndex (uint64 big endian) -> L1MessageTx
 	firstQueueIndexNotInL2BlockPrefix = []byte("q")  // firstQueueIndexNotInL2BlockPrefix + L2 block hash -> enqueue index
 	highestSyncedQueueIndexKey        = []byte("HighestSyncedQueueIndex")
+	l1MessageOriginPrefix             = []byte("mo") // l1MessageOriginPrefix + queueIndex (uint64 big endian) -> L1 transaction enqueueing the message
+	l1TxMessagePrefix                 = []byte("mt") // l1TxMessagePrefix + L1 tx hash + queueIndex (uint64 big endian) -> empty
+	l1MessageLookupPrefix             = []byte("ml") // l1MessageLookupPrefix + queueIndex (uint64 big endian) -> L2 block number including the message
 
 	// Scroll rollup event store
 	rollupEventSyncedL1BlockNumberKey = []byte("R-LastRollupEventSyncedL1BlockNumber")
 	batchChunkRangesPrefix            = []byte("R-bcr")
+	lastBatchChunkRangesIndexKey      = []byte("R-LastBatchChunkRangesIndex")
 	batchMetaPrefix                   = []byte("R-bm")
 	finalizedL2BlockNumberKey         = []byte("R-finalized")
 
 	// Row consumption
-	rowConsumptionPrefix = []byte("rc") // rowConsumptionPrefix + hash -> row consumption by block
+	rowConsumptionPrefix  = []byte("rc")                       // rowConsumptionPrefix + hash -> row consumption by block
+	rowConsumptionTailKey = []byte("RowConsumptionFrozenTail") // number of the first block in the rollup freezer row consumption table
+
+	// Fee accounting
+	blockFeesPrefix = []byte("fee") // blockFeesPrefix + hash -> fees paid in block
 
 	// Skipped transactions
 	numSkippedTransactionsKey    = []byte("NumberOfSkippedTransactions")
 	skippedTransactionPrefix     = []byte("skip") // skippedTransactionPrefix + tx hash -> skipped transaction
 	skippedTransactionHashPrefix = []byte("sh")   // skippedTransactionHashPrefix + index -> tx hash
+
+	skippedTransactionAncientPrefix = []byte("sa") // skippedTransactionAncientPrefix + tx hash -> index of the skipped transaction in the ancient store
 )
 
 // Use the updated "L1" prefix on all new networks
@@ -159,6 +174,34 @@ var FreezerNoSnappy = map[string]bool{
 	freezerDifficultyTable: true,
 }
 
+const (
+	// rollupL1MessageTable indicates the name of the rollup freezer L1 message table.
+	rollupL1MessageTable = "l1messages"
+
+	// rollupSkippedTxTable indicates the name of the rollup freezer skipped transaction table.
+	rollupSkippedTxTable = "skippedtxs"
+
+	// rollupRowConsumptionTable indicates the name of the rollup freezer row consumption table.
+	rollupRowConsumptionTable = "rowconsumption"
+
+	// rollupBatchChunkRangesTable indicates the name of the rollup freezer batch chunk ranges table.
+	rollupBatchChunkRangesTable = "batchchunkranges"
+
+	// rollupBatchMetaTable indicates the name of the rollup freezer finalized batch metadata table.
+	rollupBatchMetaTable = "batchmeta"
+)
+
+// RollupFreezerTables configures the table groups of the rollup freezer and
+// whether compression is disabled for each table. The tables of a group share
+// their item numbers: L1 messages by queue index, skipped transactions by skip
+// index, row consumption by L2 block number and batch metadata by batch index.
+var RollupFreezerTables = map[string]map[string]bool{
+	"l1messages":     {rollupL1MessageTable: false},
+	"skippedtxs":     {rollupSkippedTxTable: false},
+	"rowconsumption": {rollupRowConsumptionTable: false},
+	"batches":        {rollupBatchChunkRangesTable: false, rollupBatchMetaTable: false},
+}
+
 // LegacyTxLookupEntry is the legacy TxLookupEntry definition with some unnecessary
 // fields.
 type LegacyTxLookupEntry struct {
@@ -239,6 +282,32 @@ func bloomBitsKey(bit uint, section uint64, hash common.Hash) []byte {
 	return key
 }
 
+// logIndexKey = logIndexPrefix + log key + section (uint64 big endian) + hash
+func logIndexKey(logKey []byte, section uint64, hash common.Hash) []byte {
+	key := make([]byte, 0, len(logIndexPrefix)+len(logKey)+8+common.HashLength)
+	key = append(append(key, logIndexPrefix...), logKey...)
+	key = append(key, encodeBlockNumber(section)...)
+	return append(key, hash.Bytes()...)
+}
+
+// accountHistoryKey = accountHistoryPrefix + address + ^num (uint64 big endian) + ^tx index (uint32 big endian)
+//
+// The block number and transaction index are inverted for the history of an
+// account to be iterated from the newest transaction to the oldest.
+func accountHistoryKey(address common.Address, number uint64, txIndex uint32) []byte {
+	key := make([]byte, len(accountHistoryPrefix)+common.AddressLength+12)
+	copy(key, accountHistoryPrefix)
+	copy(key[len(accountHistoryPrefix):], address.Bytes())
+	binary.BigEndian.PutUint64(key[len(accountHistoryPrefix)+common.AddressLength:], ^number)
+	binary.BigEndian.PutUint32(key[len(accountHistoryPrefix)+common.AddressLength+8:], ^txIndex)
+	return key
+}
+
+// untracedHistoryKey = untracedHistoryPrefix + first num (uint64 big endian)
+func untracedHistoryKey(first uint64) []byte {
+	return append(append([]byte{}, untracedHistoryPrefix...), encodeBlockNumber(first)...)
+}
+
 // preimageKey = PreimagePrefix + hash
 func preimageKey(hash common.Hash) []byte {
 	return append(PreimagePrefix, hash.Bytes()...)
@@ -275,6 +344,21 @@ func L1MessageKey(queueIndex uint64) []byte {
 	return append(l1MessagePrefix, encodeBigEndian(queueIndex)...)
 }
 
+// l1MessageOriginKey = l1MessageOriginPrefix + queueIndex (uint64 big endian)
+func l1MessageOriginKey(queueIndex uint64) []byte {
+	return append(l1MessageOriginPrefix, encodeBigEndian(queueIndex)...)
+}
+
+// l1TxMessageKey = l1TxMessagePrefix + L1 tx hash + queueIndex (uint64 big endian)
+func l1TxMessageKey(l1TxHash common.Hash, queueIndex uint64) []byte {
+	return append(append(l1TxMessagePrefix, l1TxHash.Bytes()...), encodeBigEndian(queueIndex)...)
+}
+
+// l1MessageLookupKey = l1MessageLookupPrefix + queueIndex (uint64 big endian)
+func l1MessageLookupKey(queueIndex uint64) []byte {
+	return append(l1MessageLookupPrefix, encodeBigEndian(queueIndex)...)
+}
+
 // FirstQueueIndexNotInL2BlockKey = firstQueueIndexNotInL2BlockPrefix + L2 block hash
 func FirstQueueIndexNotInL2BlockKey(l2BlockHash common.Hash) []byte {
 	return append(firstQueueIndexNotInL2BlockPrefix, l2BlockHash.Bytes()...)
@@ -285,8 +369,20 @@ func rowConsumptionKey(hash common.Hash) []byte {
 	return append(rowConsumptionPrefix, hash.Bytes()...)
 }
 
-func isNotFoundErr(err error) bool {
-	return errors.Is(err, leveldb.ErrNotFound) || errors.Is(err, memorydb.ErrMemorydbNotFound)
+// skippedTransactionAncientKey = skippedTransactionAncientPrefix + tx hash
+func skippedTransactionAncientKey(txHash common.Hash) []byte {
+	return append(skippedTransactionAncientPrefix, txHash.Bytes()...)
+}
+
+// blockFeesKey = blockFeesPrefix + hash
+func blockFeesKey(hash common.Hash) []byte {
+	return append(blockFeesPrefix, hash.Bytes()...)
+}
+
+// IsNotFoundErr returns whether err is the missing key error of any of the
+// supported key-value store backends.
+func IsNotFoundErr(err error) bool {
+	return errors.Is(err, leveldb.ErrNotFound) || errors.Is(err, memorydb.ErrMemorydbNotFound) || isPebbleNotFoundErr(err)
 }
 
 // SkippedTransactionKey = skippedTransactionPrefix + tx hash
@@ -26,6 +26,8 @@ import (
 	"sync/atomic"
 	"time"
 
+	lru "github.com/hashicorp/golang-lru"
+
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/common/prque"
 	"github.com/scroll-tech/go-ethereum/consensus/misc"
@@ -48,6 +50,10 @@ const (
 	// O(maxslots), where max slots are 4 currently).
 	txSlotSize = 32 * 1024
 
+	// maxCircuitOverflowTxs is the number of recently evicted circuit-overflowing
+	// transactions the pool remembers, to reject them on re-broadcast.
+	maxCircuitOverflowTxs = 4096
+
 	// txMaxSize is the maximum size a single transaction can have. This field has
 	// non-trivial consequences: larger transactions are significantly harder and
 	// more expensive to propagate; larger transactions also take more resources
@@ -87,6 +93,10 @@ var (
 	// than some meaningful limit a user might use. This is not a consensus error
 	// making the transaction invalid, rather a DOS protection.
 	ErrOversizedData = errors.New("oversized data")
+
+	// ErrCircuitCapacityExceeded is returned if a transaction does not fit into
+	// the circuits even when it is the only transaction in a block.
+	ErrCircuitCapacityExceeded = errors.New("transaction exceeds circuit capacity")
 )
 
 var (
@@ -114,6 +124,9 @@ var (
 	invalidTxMeter     = metrics.NewRegisteredMeter("txpool/invalid", nil)
 	underpricedTxMeter = metrics.NewRegisteredMeter("txpool/underpriced", nil)
 	overflowedTxMeter  = metrics.NewRegisteredMeter("txpool/overflowed", nil)
+	// circuitOverflowTxMeter counts how many transactions are evicted or rejected because
+	// they exceed the circuit capacity on their own.
+	circuitOverflowTxMeter = metrics.NewRegisteredMeter("txpool/circuitoverflow", nil)
 	// throttleTxMeter counts how many transactions are rejected due to too-many-changes between
 	// txpool reorgs.
 	throttleTxMeter = metrics.NewRegisteredMeter("txpool/throttle", nil)
@@ -151,6 +164,23 @@ type blockChain interface {
 	SubscribeChainHeadEvent(ch chan<- ChainHeadEvent) event.Subscription
 }
 
+// TxCapacityChecker estimates the circuit row consumption of a single transaction
+// executed on top of the current chain head.
+type TxCapacityChecker interface {
+	// CheckTransaction returns an error wrapping ErrCircuitCapacityExceeded if the
+	// transaction cannot fit into a block on its own. Any other error is treated
+	// as transient and the transaction is checked again later.
+	CheckTransaction(tx *types.Transaction) error
+}
+
+// CircuitOverflowTx is a transaction that was evicted from the pool because it
+// exceeds the circuit capacity.
+type CircuitOverflowTx struct {
+	Tx     *types.Transaction
+	From   common.Address
+	Reason string
+}
+
 // TxPoolConfig are the configuration parameters of the transaction pool.
 type TxPoolConfig struct {
 	Locals    []common.Address // Addresses that should be treated by default as local
@@ -167,6 +197,8 @@ type TxPoolConfig struct {
 	GlobalQueue  uint64 // Maximum number of non-executable transaction slots for all accounts
 
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
+
+	CircuitCheckInterval time.Duration // Interval of the circuit capacity check of pending transactions (0 = disabled)
 }
 
 // DefaultTxPoolConfig contains the default configurations for the transaction
@@ -272,6 +304,9 @@ type TxPool struct {
 	initDoneCh      chan struct{}  // is closed once the pool is initialized (for tests)
 
 	changesSinceReorg int // A counter for how many drops we've performed in-between reorg.
+
+	capacityChecker TxCapacityChecker // Circuit capacity estimator for pending transactions (nil = disabled)
+	circuitOverflow *lru.Cache        // Recently evicted circuit-overflowing transactions
 }
 
 type txpoolResetRequest struct {
@@ -309,6 +344,7 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 		pool.locals.add(addr)
 	}
 	pool.priced = newTxPricedList(pool.all)
+	pool.circuitOverflow, _ = lru.New(maxCircuitOverflowTxs)
 	pool.reset(nil, chain.CurrentBlock().Header())
 
 	// Start the reorg loop early so it can handle requests generated during journal loading.
@@ -332,6 +368,11 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 	pool.wg.Add(1)
 	go pool.loop()
 
+	// Start the circuit capacity check of pending transactions if requested.
+	if config.CircuitCheckInterval > 0 {
+		pool.wg.Add(1)
+		go pool.circuitCheckLoop()
+	}
 	return pool
 }
 
@@ -414,6 +455,61 @@ func (pool *TxPool) loop() {
 	}
 }
 
+// circuitCheckLoop periodically runs the pending transactions through the
+// configured capacity checker, evicting the ones that can never be included.
+func (pool *TxPool) circuitCheckLoop() {
+	defer pool.wg.Done()
+
+	check := time.NewTicker(pool.config.CircuitCheckInterval)
+	defer check.Stop()
+
+	// Transactions that already passed the check, to avoid re-tracing them
+	checked := make(map[common.Hash]struct{})
+	for {
+		select {
+		case <-check.C:
+			pool.mu.RLock()
+			checker := pool.capacityChecker
+			pool.mu.RUnlock()
+
+			if checker != nil {
+				checked = pool.checkCircuitCapacity(checker, checked)
+			}
+
+		case <-pool.reorgShutdownCh:
+			return
+		}
+	}
+}
+
+// checkCircuitCapacity checks the first pending transaction of every account,
+// the only ones executable on top of the current head. It returns the set of
+// transactions which passed the check and are still pending.
+func (pool *TxPool) checkCircuitCapacity(checker TxCapacityChecker, checked map[common.Hash]struct{}) map[common.Hash]struct{} {
+	passed := make(map[common.Hash]struct{})
+	for _, txs := range pool.Pending(false) {
+		if len(txs) == 0 {
+			continue
+		}
+		tx := txs[0]
+		if _, ok := checked[tx.Hash()]; ok {
+			passed[tx.Hash()] = struct{}{}
+			continue
+		}
+		err := checker.CheckTransaction(tx)
+		switch {
+		case err == nil:
+			passed[tx.Hash()] = struct{}{}
+		case errors.Is(err, ErrCircuitCapacityExceeded):
+			log.Debug("Evicting circuit-overflowing transaction", "hash", tx.Hash(), "err", err)
+			pool.RemoveCircuitOverflowTx(tx.Hash(), err)
+		default:
+			log.Trace("Failed to check transaction circuit capacity", "hash", tx.Hash(), "err", err)
+		}
+	}
+	return passed
+}
+
 // Stop terminates the transaction pool.
 func (pool *TxPool) Stop() {
 	// Unsubscribe all subscriptions registered from txpool
@@ -530,6 +626,27 @@ func (pool *TxPool) ContentFrom(addr common.Address) (types.Transactions, types.
 	return pending, queued
 }
 
+// SetCapacityChecker sets the estimator used by the periodic circuit capacity
+// check of pending transactions.
+func (pool *TxPool) SetCapacityChecker(checker TxCapacityChecker) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	pool.capacityChecker = checker
+}
+
+// CircuitOverflowed retrieves the recently evicted transactions which exceed the
+// circuit capacity, oldest first.
+func (pool *TxPool) CircuitOverflowed() []*CircuitOverflowTx {
+	var txs []*CircuitOverflowTx
+	for _, key := range pool.circuitOverflow.Keys() {
+		if entry, ok := pool.circuitOverflow.Peek(key); ok {
+			txs = append(txs, entry.(*CircuitOverflowTx))
+		}
+	}
+	return txs
+}
+
 // Pending retrieves all currently processable transactions, grouped by origin
 // account and sorted by nonce. The returned transaction set is a copy and can be
 // freely modified by calling code.
@@ -701,6 +818,12 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		knownTxMeter.Mark(1)
 		return false, ErrAlreadyKnown
 	}
+	// If the transaction is known to overflow the circuits, discard it
+	if pool.circuitOverflow.Contains(hash) {
+		log.Trace("Discarding circuit-overflowing transaction", "hash", hash)
+		circuitOverflowTxMeter.Mark(1)
+		return false, ErrCircuitCapacityExceeded
+	}
 	// Make the local flag. If it's from local source or it's from the network but
 	// the sender is marked as local previously, treat it as the local transaction.
 	isLocal := local || pool.locals.containsTx(tx)
@@ -1037,6 +1160,24 @@ func (pool *TxPool) RemoveTx(hash common.Hash, outofbound bool) {
 	pool.removeTx(hash, outofbound)
 }
 
+// RemoveCircuitOverflowTx removes a transaction that exceeds the circuit capacity
+// on its own, moving all subsequent transactions back to the future queue. The
+// transaction is remembered with the given reason and rejected if re-added.
+func (pool *TxPool) RemoveCircuitOverflowTx(hash common.Hash, reason error) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	tx := pool.all.Get(hash)
+	if tx == nil {
+		return
+	}
+	from, _ := types.Sender(pool.signer, tx) // already validated during insertion
+	pool.circuitOverflow.Add(hash, &CircuitOverflowTx{Tx: tx, From: from, Reason: reason.Error()})
+	circuitOverflowTxMeter.Mark(1)
+
+	pool.removeTx(hash, true)
+}
+
 // removeTx removes a single transaction from the queue, moving all subsequent
 // transactions back to the future queue.
 func (pool *TxPool) removeTx(hash common.Hash, outofbound bool) {
@@ -2601,3 +2601,76 @@ func TestPoolPending(t *testing.T) {
 	maxAccounts := 10
 	assert.Len(t, pool.PendingWithMax(false, maxAccounts), maxAccounts)
 }
+
+// testCapacityChecker reports a fixed set of transactions as overflowing.
+type testCapacityChecker struct {
+	overflowing map[common.Hash]bool
+	checked     int
+}
+
+func (c *testCapacityChecker) CheckTransaction(tx *types.Transaction) error {
+	c.checked++
+	if c.overflowing[tx.Hash()] {
+		return fmt.Errorf("%w: row consumption overflow", ErrCircuitCapacityExceeded)
+	}
+	return nil
+}
+
+// Tests that pending transactions exceeding the circuit capacity are evicted,
+// reported with their reason and rejected when re-added.
+func TestTransactionCircuitCapacityEviction(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, from, big.NewInt(1000000))
+
+	otherKey, _ := crypto.GenerateKey()
+	other := crypto.PubkeyToAddress(otherKey.PublicKey)
+	testAddBalance(pool, other, big.NewInt(1000000))
+
+	overflowing := transaction(0, 100000, key)
+	txs := []*types.Transaction{overflowing, transaction(1, 100000, key), transaction(0, 100000, otherKey)}
+	for _, err := range pool.AddRemotesSync(txs) {
+		if err != nil {
+			t.Fatalf("failed to add transaction: %v", err)
+		}
+	}
+	checker := &testCapacityChecker{overflowing: map[common.Hash]bool{overflowing.Hash(): true}}
+
+	checked := pool.checkCircuitCapacity(checker, nil)
+	if checker.checked != 2 {
+		t.Fatalf("checked transaction count mismatch: have %d, want %d", checker.checked, 2)
+	}
+	if len(checked) != 1 {
+		t.Fatalf("passed transaction count mismatch: have %d, want %d", len(checked), 1)
+	}
+	// The overflowing transaction is dropped and its successor demoted
+	if pending, queued := pool.Stats(); pending != 1 || queued != 1 {
+		t.Fatalf("pool stats mismatch: have %d/%d, want 1/1", pending, queued)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+	overflowed := pool.CircuitOverflowed()
+	if len(overflowed) != 1 {
+		t.Fatalf("overflowed transaction count mismatch: have %d, want %d", len(overflowed), 1)
+	}
+	if overflowed[0].Tx.Hash() != overflowing.Hash() || overflowed[0].From != from {
+		t.Fatalf("overflowed transaction mismatch: have %x from %x", overflowed[0].Tx.Hash(), overflowed[0].From)
+	}
+	if overflowed[0].Reason != "transaction exceeds circuit capacity: row consumption overflow" {
+		t.Fatalf("overflow reason mismatch: have %q", overflowed[0].Reason)
+	}
+	// Transactions that passed are not checked again
+	pool.checkCircuitCapacity(checker, checked)
+	if checker.checked != 2 {
+		t.Fatalf("checked transaction count mismatch: have %d, want %d", checker.checked, 2)
+	}
+	// Re-broadcasting the overflowing transaction is rejected
+	if err := pool.AddRemote(overflowing); !errors.Is(err, ErrCircuitCapacityExceeded) {
+		t.Fatalf("re-added transaction error mismatch: have %v, want %v", err, ErrCircuitCapacityExceeded)
+	}
+}
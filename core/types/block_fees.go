@@ -0,0 +1,28 @@
+package types
+
+import (
+	"math/big"
+)
+
+// BlockFees summarizes the fees paid by the transactions of an L2 block.
+type BlockFees struct {
+	L2Fee     *big.Int // execution fees credited to the fee recipient
+	L1DataFee *big.Int // L1 data fees charged to the senders, only non-zero if the fee vault is enabled
+	BurnedFee *big.Int // base fees burned, only non-zero if the fee vault is disabled
+}
+
+// NewBlockFees returns an empty BlockFees.
+func NewBlockFees() *BlockFees {
+	return &BlockFees{
+		L2Fee:     new(big.Int),
+		L1DataFee: new(big.Int),
+		BurnedFee: new(big.Int),
+	}
+}
+
+// Add adds the fees of other to f.
+func (f *BlockFees) Add(other *BlockFees) {
+	f.L2Fee.Add(f.L2Fee, other.L2Fee)
+	f.L1DataFee.Add(f.L1DataFee, other.L1DataFee)
+	f.BurnedFee.Add(f.BurnedFee, other.BurnedFee)
+}
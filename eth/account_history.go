@@ -0,0 +1,265 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/state"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/core/vm"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/eth/tracers"
+	_ "github.com/scroll-tech/go-ethereum/eth/tracers/native" // Register the native call tracer
+	"github.com/scroll-tech/go-ethereum/ethdb"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rollup/fees"
+)
+
+const (
+	// accountHistorySectionSize is the number of blocks of the sections of the
+	// account history indexer. The history is indexed block by block to follow
+	// the chain head closely.
+	accountHistorySectionSize = 1
+
+	// accountHistoryConfirms is the number of confirmations before a block is
+	// indexed. Reorged blocks are indexed again, their stale entries are skipped
+	// when reading the history.
+	accountHistoryConfirms = 0
+
+	// accountHistoryThrottling is the time to wait between processing two
+	// consecutive blocks while catching up, none as their re-execution dominates.
+	accountHistoryThrottling = 0
+
+	// accountHistoryReexec is the number of blocks the indexer may re-execute to
+	// regenerate the state a block is traced on.
+	accountHistoryReexec = 128
+)
+
+// StateAtBlockFn returns the state after the execution of the given block.
+type StateAtBlockFn func(block *types.Block) (*state.StateDB, error)
+
+// AccountHistoryIndexer implements a core.ChainIndexer, recording the
+// transactions touching every account along with the roles of the account: the
+// sender and the recipient of a transaction, the contract it creates and the
+// participants of its internal calls. The internal calls are found by executing
+// the transactions again with the native call tracer, which requires the state
+// of the parent block. Blocks whose state is unavailable are indexed without
+// their internal calls, and recorded as untraced for readers to know the history
+// of these blocks is incomplete.
+type AccountHistoryIndexer struct {
+	chain   *core.BlockChain
+	db      ethdb.Database
+	stateAt StateAtBlockFn
+	batch   ethdb.Batch
+	warned  bool // Whether the indexing of a block without its internal calls was reported
+}
+
+// NewAccountHistoryIndexer returns a chain indexer that generates the account
+// history of the canonical chain, retrieving the state blocks are traced on with
+// the given function.
+func NewAccountHistoryIndexer(chain *core.BlockChain, stateAt StateAtBlockFn) *core.ChainIndexer {
+	db := chain.Database()
+	backend := &AccountHistoryIndexer{
+		chain:   chain,
+		db:      db,
+		stateAt: stateAt,
+	}
+	table := rawdb.NewTable(db, string(rawdb.AccountHistoryIndexPrefix))
+
+	return core.NewChainIndexer(db, table, backend, accountHistorySectionSize, accountHistoryConfirms, accountHistoryThrottling, "accounthistory")
+}
+
+// Reset implements core.ChainIndexerBackend, starting a new section.
+func (b *AccountHistoryIndexer) Reset(ctx context.Context, section uint64, lastSectionHead common.Hash) error {
+	b.batch = b.db.NewBatch()
+	return nil
+}
+
+// Process implements core.ChainIndexerBackend, adding the transactions of a new
+// header into the account history.
+func (b *AccountHistoryIndexer) Process(ctx context.Context, header *types.Header) error {
+	hash, number := header.Hash(), header.Number.Uint64()
+	block := b.chain.GetBlock(hash, number)
+	if block == nil {
+		return fmt.Errorf("block #%d [%x..] not found", number, hash[:4])
+	}
+	if len(block.Transactions()) == 0 {
+		b.clearUntraced(number)
+		return nil
+	}
+	roles, err := b.directParticipants(block)
+	if err != nil {
+		return err
+	}
+	if err := b.traceInternalCalls(block, roles); err == nil {
+		b.clearUntraced(number)
+	} else {
+		if !b.warned {
+			log.Warn("Indexing account history without internal calls, state unavailable", "number", number, "hash", hash, "err", err)
+			b.warned = true
+		} else {
+			log.Debug("Indexing account history without internal calls", "number", number, "hash", hash, "err", err)
+		}
+		b.recordUntraced(number)
+	}
+	for i, tx := range block.Transactions() {
+		for address, role := range roles[i] {
+			rawdb.WriteAccountHistoryEntry(b.batch, address, &rawdb.AccountHistoryEntry{
+				BlockNumber: number,
+				TxIndex:     uint32(i),
+				BlockHash:   hash,
+				TxHash:      tx.Hash(),
+				Roles:       role,
+			})
+		}
+	}
+	return nil
+}
+
+// recordUntraced records the given block as indexed without internal calls,
+// extending the untraced range of the previous block if any.
+func (b *AccountHistoryIndexer) recordUntraced(number uint64) {
+	if len(rawdb.ReadUntracedBlockRanges(b.db, number, number)) > 0 {
+		return
+	}
+	r := rawdb.UntracedBlockRange{First: number, Last: number}
+	if number > 0 {
+		if prev := rawdb.ReadUntracedBlockRanges(b.db, 0, number-1); len(prev) > 0 && prev[len(prev)-1].Last == number-1 {
+			r.First = prev[len(prev)-1].First
+		}
+	}
+	rawdb.WriteUntracedBlockRange(b.batch, r)
+}
+
+// clearUntraced removes the given block from the untraced ranges, as it may
+// have been recorded untraced before being reorged and indexed again.
+func (b *AccountHistoryIndexer) clearUntraced(number uint64) {
+	if len(rawdb.ReadUntracedBlockRanges(b.db, number, number)) > 0 {
+		rawdb.DeleteUntracedBlock(b.db, b.batch, number)
+	}
+}
+
+// Commit implements core.ChainIndexerBackend, writing the section out into the
+// database.
+func (b *AccountHistoryIndexer) Commit() error {
+	return b.batch.Write()
+}
+
+// Prune returns an empty error since we don't support pruning here.
+func (b *AccountHistoryIndexer) Prune(threshold uint64) error {
+	return nil
+}
+
+// directParticipants returns the roles of the sender and the recipient or the
+// created contract of every transaction of the given block.
+func (b *AccountHistoryIndexer) directParticipants(block *types.Block) ([]map[common.Address]uint8, error) {
+	signer := types.MakeSigner(b.chain.Config(), block.Number())
+
+	roles := make([]map[common.Address]uint8, len(block.Transactions()))
+	for i, tx := range block.Transactions() {
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %#x: %w", tx.Hash(), err)
+		}
+		roles[i] = map[common.Address]uint8{from: rawdb.AccountRoleFrom}
+		if to := tx.To(); to != nil {
+			roles[i][*to] |= rawdb.AccountRoleTo
+		} else {
+			roles[i][crypto.CreateAddress(from, tx.Nonce())] |= rawdb.AccountRoleCreated
+		}
+	}
+	return roles, nil
+}
+
+// callFrame is the part of a call frame of the native call tracer listing the
+// participants of the call and of its subcalls.
+type callFrame struct {
+	From  common.Address  `json:"from"`
+	To    *common.Address `json:"to"`
+	Calls []callFrame     `json:"calls"`
+}
+
+// addInternalCallParticipants adds the role of internal call participant to the
+// caller and callee of the given call and of its subcalls.
+func addInternalCallParticipants(roles map[common.Address]uint8, call *callFrame) {
+	roles[call.From] |= rawdb.AccountRoleInternal
+	if call.To != nil {
+		roles[*call.To] |= rawdb.AccountRoleInternal
+	}
+	for i := range call.Calls {
+		addInternalCallParticipants(roles, &call.Calls[i])
+	}
+}
+
+// traceInternalCalls executes the transactions of the given block with the
+// native call tracer, adding the participants of their internal calls to the
+// given roles.
+func (b *AccountHistoryIndexer) traceInternalCalls(block *types.Block, roles []map[common.Address]uint8) error {
+	parent := b.chain.GetBlock(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return fmt.Errorf("parent %#x not found", block.ParentHash())
+	}
+	statedb, err := b.stateAt(parent)
+	if err != nil {
+		return err
+	}
+	var (
+		config   = b.chain.Config()
+		signer   = types.MakeSigner(config, block.Number())
+		blockCtx = core.NewEVMBlockContext(block.Header(), b.chain, config, nil)
+	)
+	for i, tx := range block.Transactions() {
+		msg, err := tx.AsMessage(signer, block.BaseFee())
+		if err != nil {
+			return fmt.Errorf("transaction %#x: %w", tx.Hash(), err)
+		}
+		tracer, err := tracers.New("callTracer", &tracers.Context{BlockHash: block.Hash(), TxIndex: i, TxHash: tx.Hash()}, nil)
+		if err != nil {
+			return err
+		}
+		vmenv := vm.NewEVM(blockCtx, core.NewEVMTxContext(msg), statedb, config, vm.Config{Debug: true, Tracer: tracer})
+		statedb.SetTxContext(tx.Hash(), i)
+		l1DataFee, err := fees.CalculateL1DataFee(tx, statedb, config, block.Number())
+		if err != nil {
+			return err
+		}
+		if _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.Gas()), l1DataFee); err != nil {
+			return fmt.Errorf("transaction %#x failed: %w", tx.Hash(), err)
+		}
+		statedb.Finalise(vmenv.ChainConfig().IsEIP158(block.Number()))
+
+		result, err := tracer.GetResult()
+		if err != nil {
+			return fmt.Errorf("transaction %#x: %w", tx.Hash(), err)
+		}
+		var frame callFrame
+		if err := json.Unmarshal(result, &frame); err != nil {
+			return fmt.Errorf("transaction %#x: %w", tx.Hash(), err)
+		}
+		for j := range frame.Calls {
+			addInternalCallParticipants(roles[i], &frame.Calls[j])
+		}
+	}
+	return nil
+}
+
+// RebuildAccountHistory drops the account history and rebuilds it from scratch
+// for the whole canonical chain. The internal calls are only indexed for the
+// blocks whose parent state is available, i.e. for every block on archive nodes,
+// the other blocks are recorded as untraced.
+// The database must not be in use by a running node.
+func RebuildAccountHistory(ctx context.Context, chain *core.BlockChain) error {
+	if err := rawdb.DeleteAccountHistory(chain.Database()); err != nil {
+		return err
+	}
+	indexer := NewAccountHistoryIndexer(chain, func(block *types.Block) (*state.StateDB, error) {
+		return chain.StateAt(block.Root())
+	})
+	defer indexer.Close()
+
+	return indexer.Rebuild(ctx)
+}
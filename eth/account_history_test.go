@@ -0,0 +1,166 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/consensus/ethash"
+	"github.com/scroll-tech/go-ethereum/core"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/state"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/core/vm"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/params"
+)
+
+func TestAccountHistoryIndexer(t *testing.T) {
+	var (
+		key, _  = crypto.GenerateKey()
+		sender  = crypto.PubkeyToAddress(key.PublicKey)
+		caller  = common.HexToAddress("0xa000000000000000000000000000000000000000")
+		callee  = common.HexToAddress("0xb000000000000000000000000000000000000000")
+		payee   = common.HexToAddress("0xc000000000000000000000000000000000000000")
+		created = crypto.CreateAddress(sender, 2)
+		config  = params.TestChainConfig
+		signer  = types.LatestSigner(config)
+		engine  = ethash.NewFaker()
+	)
+	// The caller contract calls the callee contract, which stops
+	code := []byte{
+		byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.PUSH1), 0,
+		byte(vm.PUSH20),
+	}
+	code = append(code, callee.Bytes()...)
+	code = append(code, byte(vm.GAS), byte(vm.CALL), byte(vm.STOP))
+	gspec := &core.Genesis{
+		Config: config,
+		Alloc: core.GenesisAlloc{
+			sender: {Balance: big.NewInt(params.Ether)},
+			caller: {Balance: common.Big0, Code: code},
+			callee: {Balance: common.Big0, Code: []byte{byte(vm.STOP)}},
+		},
+	}
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		genesis = gspec.MustCommit(db)
+		gendb   = rawdb.NewMemoryDatabase()
+	)
+	gspec.MustCommit(gendb)
+	blocks, _ := core.GenerateChain(config, genesis, engine, gendb, 2, func(i int, b *core.BlockGen) {
+		switch i {
+		case 0:
+			tx, _ := types.SignTx(types.NewTransaction(0, caller, common.Big0, 100000, b.BaseFee(), nil), signer, key)
+			b.AddTx(tx)
+			tx, _ = types.SignTx(types.NewTransaction(1, payee, big.NewInt(1000), params.TxGas, b.BaseFee(), nil), signer, key)
+			b.AddTx(tx)
+		case 1:
+			tx, _ := types.SignTx(types.NewContractCreation(2, common.Big0, 100000, b.BaseFee(), nil), signer, key)
+			b.AddTx(tx)
+		}
+	})
+	cacheConfig := &core.CacheConfig{TrieCleanLimit: 256, TrieDirtyDisabled: true} // Archive mode
+	chain, err := core.NewBlockChain(db, cacheConfig, config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+	}
+	if err := RebuildAccountHistory(context.Background(), chain); err != nil {
+		t.Fatalf("failed to rebuild account history: %v", err)
+	}
+	type position struct {
+		number  uint64
+		txIndex uint32
+		roles   uint8
+	}
+	tests := []struct {
+		address common.Address
+		want    []position
+	}{
+		{sender, []position{{2, 0, rawdb.AccountRoleFrom}, {1, 1, rawdb.AccountRoleFrom}, {1, 0, rawdb.AccountRoleFrom}}},
+		{caller, []position{{1, 0, rawdb.AccountRoleTo | rawdb.AccountRoleInternal}}},
+		{callee, []position{{1, 0, rawdb.AccountRoleInternal}}},
+		{payee, []position{{1, 1, rawdb.AccountRoleTo}}},
+		{created, []position{{2, 0, rawdb.AccountRoleCreated}}},
+	}
+	for _, tt := range tests {
+		entries := rawdb.ReadAccountHistory(db, tt.address, ^uint64(0), ^uint32(0), 0, 10)
+		if len(entries) != len(tt.want) {
+			t.Fatalf("%x: wrong number of transactions: have %d, want %d", tt.address, len(entries), len(tt.want))
+		}
+		for i, entry := range entries {
+			block := blocks[entry.BlockNumber-1]
+			have := position{entry.BlockNumber, entry.TxIndex, entry.Roles}
+			if have != tt.want[i] {
+				t.Errorf("%x: transaction %d mismatch: have %+v, want %+v", tt.address, i, have, tt.want[i])
+			}
+			if entry.BlockHash != block.Hash() || entry.TxHash != block.Transactions()[entry.TxIndex].Hash() {
+				t.Errorf("%x: transaction %d: wrong hashes %x/%x", tt.address, i, entry.BlockHash, entry.TxHash)
+			}
+		}
+	}
+	if ranges := rawdb.ReadUntracedBlockRanges(db, 0, ^uint64(0)); len(ranges) != 0 {
+		t.Errorf("untraced blocks on archive node: %v", ranges)
+	}
+
+	// Without the state, the blocks are indexed without their internal calls
+	// and recorded as untraced
+	if err := rawdb.DeleteAccountHistory(db); err != nil {
+		t.Fatalf("failed to delete account history: %v", err)
+	}
+	indexer := NewAccountHistoryIndexer(chain, func(block *types.Block) (*state.StateDB, error) {
+		return nil, errors.New("state unavailable")
+	})
+	defer indexer.Close()
+	if err := indexer.Rebuild(context.Background()); err != nil {
+		t.Fatalf("failed to rebuild account history: %v", err)
+	}
+	if entries := rawdb.ReadAccountHistory(db, callee, ^uint64(0), ^uint32(0), 0, 10); len(entries) != 0 {
+		t.Errorf("internal calls indexed without state: %d entries", len(entries))
+	}
+	if entries := rawdb.ReadAccountHistory(db, sender, ^uint64(0), ^uint32(0), 0, 10); len(entries) != 3 {
+		t.Errorf("wrong number of sender transactions without state: have %d, want 3", len(entries))
+	}
+	want := []rawdb.UntracedBlockRange{{First: 1, Last: 2}}
+	if ranges := rawdb.ReadUntracedBlockRanges(db, 0, ^uint64(0)); !reflect.DeepEqual(ranges, want) {
+		t.Errorf("wrong untraced blocks: have %v, want %v", ranges, want)
+	}
+
+	// A block indexed again with its state, e.g. after a reorg, is traced and
+	// no longer untraced, while indexing it again without state must not lose
+	// the following untraced blocks
+	index := func(number uint64, stateAt StateAtBlockFn) {
+		t.Helper()
+		backend := &AccountHistoryIndexer{chain: chain, db: db, stateAt: stateAt}
+		if err := backend.Reset(context.Background(), number, common.Hash{}); err != nil {
+			t.Fatalf("failed to reset indexer: %v", err)
+		}
+		if err := backend.Process(context.Background(), blocks[number-1].Header()); err != nil {
+			t.Fatalf("failed to index block %d: %v", number, err)
+		}
+		if err := backend.Commit(); err != nil {
+			t.Fatalf("failed to commit block %d: %v", number, err)
+		}
+	}
+	index(1, func(block *types.Block) (*state.StateDB, error) {
+		return chain.StateAt(block.Root())
+	})
+	want = []rawdb.UntracedBlockRange{{First: 2, Last: 2}}
+	if ranges := rawdb.ReadUntracedBlockRanges(db, 0, ^uint64(0)); !reflect.DeepEqual(ranges, want) {
+		t.Errorf("wrong untraced blocks after tracing: have %v, want %v", ranges, want)
+	}
+	index(1, func(block *types.Block) (*state.StateDB, error) {
+		return nil, errors.New("state unavailable")
+	})
+	want = []rawdb.UntracedBlockRange{{First: 1, Last: 2}}
+	if ranges := rawdb.ReadUntracedBlockRanges(db, 0, ^uint64(0)); !reflect.DeepEqual(ranges, want) {
+		t.Errorf("wrong untraced blocks after indexing without state: have %v, want %v", ranges, want)
+	}
+}
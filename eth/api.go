@@ -35,9 +35,11 @@ import (
 	"github.com/scroll-tech/go-ethereum/core/rawdb"
 	"github.com/scroll-tech/go-ethereum/core/state"
 	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/ethdb"
 	"github.com/scroll-tech/go-ethereum/internal/ethapi"
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/scroll-tech/go-ethereum/rlp"
+	"github.com/scroll-tech/go-ethereum/rollup/fees"
 	"github.com/scroll-tech/go-ethereum/rpc"
 	"github.com/scroll-tech/go-ethereum/trie"
 )
@@ -668,6 +670,144 @@ func (api *ScrollAPI) GetLatestRelayedQueueIndex(ctx context.Context) (queueInde
 	return &lastIncluded, nil
 }
 
+// Statuses of L1 messages on L2.
+const (
+	L1MessagePending  = "pending"  // Synced from L1, not yet included in an L2 block
+	L1MessageIncluded = "included" // Included in a canonical L2 block
+	L1MessageSkipped  = "skipped"  // Skipped by the sequencer, e.g. for exceeding the circuit capacity
+)
+
+// QueueIndexOrL1TxHash identifies L1 messages either by their queue index, or
+// by the hash of the L1 transaction which enqueued them.
+type QueueIndexOrL1TxHash struct {
+	QueueIndex *uint64
+	L1TxHash   *common.Hash
+}
+
+func (q *QueueIndexOrL1TxHash) UnmarshalJSON(data []byte) error {
+	var number uint64
+	if err := json.Unmarshal(data, &number); err == nil {
+		q.QueueIndex = &number
+		return nil
+	}
+	var input string
+	if err := json.Unmarshal(data, &input); err != nil {
+		return err
+	}
+	if len(input) == 2+2*common.HashLength {
+		var hash common.Hash
+		if err := hash.UnmarshalText([]byte(input)); err != nil {
+			return err
+		}
+		q.L1TxHash = &hash
+		return nil
+	}
+	number, err := hexutil.DecodeUint64(input)
+	if err != nil {
+		return fmt.Errorf("invalid queue index or L1 transaction hash %q: %w", input, err)
+	}
+	q.QueueIndex = &number
+	return nil
+}
+
+// L1MessageStatus is the status of an L1 message on L2, along with the L1
+// transaction which enqueued it and the L2 block which included or skipped it.
+type L1MessageStatus struct {
+	QueueIndex       hexutil.Uint64  `json:"queueIndex"`
+	Status           string          `json:"status"`
+	L1TxHash         *common.Hash    `json:"l1TxHash"`
+	L1BlockNumber    *hexutil.Uint64 `json:"l1BlockNumber"`
+	L1BlockHash      *common.Hash    `json:"l1BlockHash"`
+	TxHash           common.Hash     `json:"transactionHash"`
+	BlockHash        *common.Hash    `json:"blockHash"`
+	BlockNumber      *hexutil.Uint64 `json:"blockNumber"`
+	TransactionIndex *hexutil.Uint64 `json:"transactionIndex"`
+	SkipReason       string          `json:"skipReason,omitempty"`
+}
+
+// GetL1MessageStatus returns the status of the L1 message with the given queue
+// index, or of the L1 messages enqueued by the given L1 transaction: pending,
+// included in a canonical L2 block or skipped. The L1 transaction is unknown
+// for the messages synced before it was recorded.
+func (api *ScrollAPI) GetL1MessageStatus(ctx context.Context, query QueueIndexOrL1TxHash) ([]*L1MessageStatus, error) {
+	var queueIndices []uint64
+	switch {
+	case query.QueueIndex != nil:
+		queueIndices = []uint64{*query.QueueIndex}
+	case query.L1TxHash != nil:
+		queueIndices = rawdb.ReadL1MessageQueueIndicesByTxHash(api.eth.ChainDb(), *query.L1TxHash)
+	}
+	var statuses []*L1MessageStatus
+	for _, queueIndex := range queueIndices {
+		if status := api.l1MessageStatus(queueIndex); status != nil {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses, nil
+}
+
+// l1MessageStatus returns the status of the L1 message with the given queue
+// index, or nil if the message is not synced yet.
+func (api *ScrollAPI) l1MessageStatus(queueIndex uint64) *L1MessageStatus {
+	db := api.eth.ChainDb()
+	msg := rawdb.ReadL1Message(db, queueIndex)
+	if msg == nil {
+		return nil
+	}
+	status := newL1MessageStatus(db, msg)
+	if blockHash, blockNumber, index, ok := api.l1MessageLocation(queueIndex, status.TxHash); ok {
+		status.Status = L1MessageIncluded
+		status.BlockHash = &blockHash
+		status.BlockNumber = (*hexutil.Uint64)(&blockNumber)
+		status.TransactionIndex = (*hexutil.Uint64)(&index)
+		return status
+	}
+	if stx := rawdb.ReadSkippedTransaction(db, status.TxHash); stx != nil {
+		status.Status = L1MessageSkipped
+		status.BlockHash = stx.BlockHash
+		status.BlockNumber = (*hexutil.Uint64)(&stx.BlockNumber)
+		status.SkipReason = stx.Reason
+	}
+	return status
+}
+
+// newL1MessageStatus returns the status of a pending L1 message, along with the
+// L1 transaction which enqueued it if known.
+func newL1MessageStatus(db ethdb.Reader, msg *types.L1MessageTx) *L1MessageStatus {
+	status := &L1MessageStatus{
+		QueueIndex: hexutil.Uint64(msg.QueueIndex),
+		Status:     L1MessagePending,
+		TxHash:     types.NewTx(msg).Hash(),
+	}
+	if origin := rawdb.ReadL1MessageOrigin(db, msg.QueueIndex); origin != nil {
+		status.L1TxHash = &origin.TxHash
+		status.L1BlockNumber = (*hexutil.Uint64)(&origin.BlockNumber)
+		status.L1BlockHash = &origin.BlockHash
+	}
+	return status
+}
+
+// l1MessageLocation returns the location of an L1 message in the canonical
+// chain, if included.
+func (api *ScrollAPI) l1MessageLocation(queueIndex uint64, txHash common.Hash) (common.Hash, uint64, uint64, bool) {
+	if number := rawdb.ReadL1MessageLookupEntry(api.eth.ChainDb(), queueIndex); number != nil {
+		// The entry may be stale after a rewind, so check the block still includes the message
+		if block := api.eth.blockchain.GetBlockByNumber(*number); block != nil {
+			for i, tx := range block.Transactions() {
+				if tx.Hash() == txHash {
+					return block.Hash(), block.NumberU64(), uint64(i), true
+				}
+			}
+		}
+	}
+	// Messages included before the lookup entries were recorded are found
+	// through the transaction index, unless it was pruned.
+	if tx, blockHash, blockNumber, index := rawdb.ReadTransaction(api.eth.ChainDb(), txHash); tx != nil {
+		return blockHash, blockNumber, index, true
+	}
+	return common.Hash{}, 0, 0, false
+}
+
 // rpcMarshalBlock uses the generalized output filler, then adds the total difficulty field, which requires
 // a `ScrollAPI`.
 func (api *ScrollAPI) rpcMarshalBlock(ctx context.Context, b *types.Block, fullTx bool) (map[string]interface{}, error) {
@@ -749,13 +889,14 @@ func (api *ScrollAPI) SyncStatus(_ context.Context) *SyncStatus {
 
 // EstimateL1DataFee returns an estimate of the L1 data fee required to
 // process the given transaction against the current pending block.
-func (api *ScrollAPI) EstimateL1DataFee(ctx context.Context, args ethapi.TransactionArgs, blockNrOrHash *rpc.BlockNumberOrHash) (*hexutil.Uint64, error) {
+// State and L1 fee overrides are applied before estimating.
+func (api *ScrollAPI) EstimateL1DataFee(ctx context.Context, args ethapi.TransactionArgs, blockNrOrHash *rpc.BlockNumberOrHash, overrides *ethapi.StateOverride, l1FeeOverrides *ethapi.L1FeeOverride) (*hexutil.Uint64, error) {
 	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber)
 	if blockNrOrHash != nil {
 		bNrOrHash = *blockNrOrHash
 	}
 
-	l1DataFee, err := ethapi.EstimateL1MsgFee(ctx, api.eth.APIBackend, args, bNrOrHash, nil, 0, api.eth.APIBackend.RPCGasCap(), api.eth.APIBackend.ChainConfig())
+	l1DataFee, err := ethapi.EstimateL1MsgFee(ctx, api.eth.APIBackend, args, bNrOrHash, overrides, l1FeeOverrides, 0, api.eth.APIBackend.RPCGasCap(), api.eth.APIBackend.ChainConfig())
 	if err != nil {
 		return nil, fmt.Errorf("failed to estimate L1 data fee: %w", err)
 	}
@@ -764,6 +905,303 @@ func (api *ScrollAPI) EstimateL1DataFee(ctx context.Context, args ethapi.Transac
 	return &result, nil
 }
 
+// L1DataFeeBreakdown is the RPC representation of the L1 data fee components
+// of a transaction. Before Curie the whole fee is attributed to the commit
+// component; afterwards it is split into a commit and a blob component.
+type L1DataFeeBreakdown struct {
+	Curie     bool           `json:"curie"`
+	TxSize    hexutil.Uint64 `json:"txSize"`
+	L1Gas     *hexutil.Big   `json:"l1Gas"`
+	CommitFee *hexutil.Big   `json:"commitFee"`
+	BlobFee   *hexutil.Big   `json:"blobFee"`
+	L1DataFee *hexutil.Big   `json:"l1DataFee"`
+
+	L1BaseFee     *hexutil.Big `json:"l1BaseFee,omitempty"`
+	Overhead      *hexutil.Big `json:"overhead,omitempty"`
+	Scalar        *hexutil.Big `json:"scalar,omitempty"`
+	L1BlobBaseFee *hexutil.Big `json:"l1BlobBaseFee,omitempty"`
+	CommitScalar  *hexutil.Big `json:"commitScalar,omitempty"`
+	BlobScalar    *hexutil.Big `json:"blobScalar,omitempty"`
+}
+
+// EstimateL1DataFeeBreakdown returns the L1 data fee of the given transaction
+// against the current pending block, split into its commit and blob components
+// together with the oracle values used. State and L1 fee overrides are applied
+// before estimating, e.g. to answer what the fee would be if L1 gas doubled.
+func (api *ScrollAPI) EstimateL1DataFeeBreakdown(ctx context.Context, args ethapi.TransactionArgs, blockNrOrHash *rpc.BlockNumberOrHash, overrides *ethapi.StateOverride, l1FeeOverrides *ethapi.L1FeeOverride) (*L1DataFeeBreakdown, error) {
+	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber)
+	if blockNrOrHash != nil {
+		bNrOrHash = *blockNrOrHash
+	}
+
+	b, err := ethapi.EstimateL1MsgFeeBreakdown(ctx, api.eth.APIBackend, args, bNrOrHash, overrides, l1FeeOverrides, 0, api.eth.APIBackend.RPCGasCap(), api.eth.APIBackend.ChainConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate L1 data fee: %w", err)
+	}
+
+	return &L1DataFeeBreakdown{
+		Curie:         b.Curie,
+		TxSize:        hexutil.Uint64(b.TxSize),
+		L1Gas:         (*hexutil.Big)(b.L1Gas),
+		CommitFee:     (*hexutil.Big)(b.Commit),
+		BlobFee:       (*hexutil.Big)(b.Blob),
+		L1DataFee:     (*hexutil.Big)(b.L1DataFee),
+		L1BaseFee:     (*hexutil.Big)(b.L1BaseFee),
+		Overhead:      (*hexutil.Big)(b.Overhead),
+		Scalar:        (*hexutil.Big)(b.Scalar),
+		L1BlobBaseFee: (*hexutil.Big)(b.L1BlobBaseFee),
+		CommitScalar:  (*hexutil.Big)(b.CommitScalar),
+		BlobScalar:    (*hexutil.Big)(b.BlobScalar),
+	}, nil
+}
+
+// feeHistoryResult is the result of scroll_feeHistory. It extends the result of
+// eth_feeHistory with the L1GasPriceOracle values in the post state of each
+// block, which determine the L1 data fee of transactions in the next block.
+// Entries are null for blocks whose state is not available.
+type feeHistoryResult struct {
+	OldestBlock   *hexutil.Big     `json:"oldestBlock"`
+	Reward        [][]*hexutil.Big `json:"reward,omitempty"`
+	BaseFee       []*hexutil.Big   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio  []float64        `json:"gasUsedRatio"`
+	L1BaseFee     []*hexutil.Big   `json:"l1BaseFee,omitempty"`
+	L1BlobBaseFee []*hexutil.Big   `json:"l1BlobBaseFee,omitempty"`
+	Overhead      []*hexutil.Big   `json:"overhead,omitempty"`
+	Scalar        []*hexutil.Big   `json:"scalar,omitempty"`
+	CommitScalar  []*hexutil.Big   `json:"commitScalar,omitempty"`
+	BlobScalar    []*hexutil.Big   `json:"blobScalar,omitempty"`
+}
+
+// FeeHistory returns the same data as eth_feeHistory for the given range of
+// blocks, together with the L1 base fee, L1 blob base fee and scalars of the
+// L1GasPriceOracle after each block.
+func (api *ScrollAPI) FeeHistory(ctx context.Context, blockCount rpc.DecimalOrHex, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*feeHistoryResult, error) {
+	oldest, reward, baseFee, gasUsed, l1Fees, err := api.eth.APIBackend.gpo.L1FeeHistory(ctx, int(blockCount), lastBlock, rewardPercentiles)
+	if err != nil {
+		return nil, err
+	}
+	results := &feeHistoryResult{
+		OldestBlock:  (*hexutil.Big)(oldest),
+		GasUsedRatio: gasUsed,
+	}
+	if reward != nil {
+		results.Reward = make([][]*hexutil.Big, len(reward))
+		for i, w := range reward {
+			results.Reward[i] = make([]*hexutil.Big, len(w))
+			for j, v := range w {
+				results.Reward[i][j] = (*hexutil.Big)(v)
+			}
+		}
+	}
+	if baseFee != nil {
+		results.BaseFee = make([]*hexutil.Big, len(baseFee))
+		for i, v := range baseFee {
+			results.BaseFee[i] = (*hexutil.Big)(v)
+		}
+	}
+	if l1Fees != nil {
+		results.L1BaseFee = make([]*hexutil.Big, len(l1Fees))
+		results.L1BlobBaseFee = make([]*hexutil.Big, len(l1Fees))
+		results.Overhead = make([]*hexutil.Big, len(l1Fees))
+		results.Scalar = make([]*hexutil.Big, len(l1Fees))
+		results.CommitScalar = make([]*hexutil.Big, len(l1Fees))
+		results.BlobScalar = make([]*hexutil.Big, len(l1Fees))
+		for i, v := range l1Fees {
+			if v == nil {
+				continue
+			}
+			results.L1BaseFee[i] = (*hexutil.Big)(v.L1BaseFee)
+			results.L1BlobBaseFee[i] = (*hexutil.Big)(v.L1BlobBaseFee)
+			results.Overhead[i] = (*hexutil.Big)(v.Overhead)
+			results.Scalar[i] = (*hexutil.Big)(v.Scalar)
+			results.CommitScalar[i] = (*hexutil.Big)(v.CommitScalar)
+			results.BlobScalar[i] = (*hexutil.Big)(v.BlobScalar)
+		}
+	}
+	return results, nil
+}
+
+// TotalCostEstimate is the estimated cost of a transaction, combining the L2
+// execution fee and the L1 data fee.
+type TotalCostEstimate struct {
+	Gas       hexutil.Uint64 `json:"gas"`
+	GasPrice  *hexutil.Big   `json:"gasPrice"`
+	L2Fee     *hexutil.Big   `json:"l2Fee"`
+	L1DataFee *hexutil.Big   `json:"l1DataFee"`
+	Value     *hexutil.Big   `json:"value"`
+	TotalCost *hexutil.Big   `json:"totalCost"`
+}
+
+// EstimateTotalCost estimates the total amount of wei the sender of the given
+// transaction needs against the current pending block: gas * gasPrice +
+// l1DataFee + value. This matches the balance check of the transaction pool,
+// which charges the fee cap of dynamic fee transactions. If no gas limit is
+// given, it is estimated. The price is gasPrice or maxFeePerGas if given. With
+// only maxPriorityFeePerGas, the fee cap defaults to the tip plus twice the
+// base fee, as when sending the transaction. Otherwise the suggested gas price
+// is used.
+func (api *ScrollAPI) EstimateTotalCost(ctx context.Context, args ethapi.TransactionArgs, blockNrOrHash *rpc.BlockNumberOrHash, overrides *ethapi.StateOverride, l1FeeOverrides *ethapi.L1FeeOverride) (*TotalCostEstimate, error) {
+	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber)
+	if blockNrOrHash != nil {
+		bNrOrHash = *blockNrOrHash
+	}
+	backend := api.eth.APIBackend
+
+	if args.GasPrice != nil && (args.MaxFeePerGas != nil || args.MaxPriorityFeePerGas != nil) {
+		return nil, errors.New("both gasPrice and (maxFeePerGas or maxPriorityFeePerGas) specified")
+	}
+	var gasPrice *big.Int
+	switch {
+	case args.GasPrice != nil:
+		gasPrice = args.GasPrice.ToInt()
+	case args.MaxFeePerGas != nil:
+		gasPrice = args.MaxFeePerGas.ToInt()
+	case args.MaxPriorityFeePerGas != nil:
+		gasPrice = new(big.Int).Set(args.MaxPriorityFeePerGas.ToInt())
+		if head := backend.CurrentHeader(); head.BaseFee != nil {
+			gasPrice.Add(gasPrice, new(big.Int).Mul(head.BaseFee, big.NewInt(2)))
+		}
+		args.MaxFeePerGas = (*hexutil.Big)(gasPrice)
+	default:
+		tip, err := backend.SuggestGasTipCap(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if head := backend.CurrentHeader(); head.BaseFee != nil {
+			tip.Add(tip, head.BaseFee)
+		}
+		gasPrice = tip
+		args.GasPrice = (*hexutil.Big)(gasPrice)
+	}
+	if args.MaxFeePerGas != nil && args.MaxPriorityFeePerGas != nil && args.MaxFeePerGas.ToInt().Cmp(args.MaxPriorityFeePerGas.ToInt()) < 0 {
+		return nil, fmt.Errorf("maxFeePerGas (%v) < maxPriorityFeePerGas (%v)", args.MaxFeePerGas, args.MaxPriorityFeePerGas)
+	}
+
+	if args.Gas == nil {
+		gas, err := ethapi.DoEstimateGas(ctx, backend, args, bNrOrHash, overrides, l1FeeOverrides, backend.RPCGasCap())
+		if err != nil {
+			return nil, err
+		}
+		args.Gas = &gas
+	}
+
+	l1DataFee, err := ethapi.EstimateL1MsgFee(ctx, backend, args, bNrOrHash, overrides, l1FeeOverrides, 0, backend.RPCGasCap(), backend.ChainConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate L1 data fee: %w", err)
+	}
+
+	value := new(big.Int)
+	if args.Value != nil {
+		value = args.Value.ToInt()
+	}
+	l2Fee := new(big.Int).Mul(new(big.Int).SetUint64(uint64(*args.Gas)), gasPrice)
+	total := new(big.Int).Add(l2Fee, l1DataFee)
+	total.Add(total, value)
+
+	return &TotalCostEstimate{
+		Gas:       *args.Gas,
+		GasPrice:  (*hexutil.Big)(gasPrice),
+		L2Fee:     (*hexutil.Big)(l2Fee),
+		L1DataFee: (*hexutil.Big)(l1DataFee),
+		Value:     (*hexutil.Big)(value),
+		TotalCost: (*hexutil.Big)(total),
+	}, nil
+}
+
+// maxFeeBreakdownBlocks is the maximum number of blocks a single
+// scroll_getFeeBreakdown request may cover.
+const maxFeeBreakdownBlocks = 10000
+
+// Fees is the RPC representation of the fees paid in one or more blocks.
+type Fees struct {
+	L2Fee     *hexutil.Big `json:"l2Fee"`
+	L1DataFee *hexutil.Big `json:"l1DataFee"`
+	BurnedFee *hexutil.Big `json:"burnedFee"`
+}
+
+func newRPCFees(fees *types.BlockFees) Fees {
+	return Fees{
+		L2Fee:     (*hexutil.Big)(fees.L2Fee),
+		L1DataFee: (*hexutil.Big)(fees.L1DataFee),
+		BurnedFee: (*hexutil.Big)(fees.BurnedFee),
+	}
+}
+
+// BlockFees contains the fees paid in a block.
+type BlockFees struct {
+	Number hexutil.Uint64 `json:"number"`
+	Hash   common.Hash    `json:"hash"`
+	Fees
+}
+
+// FeeBreakdown is the result of scroll_getFeeBreakdown.
+type FeeBreakdown struct {
+	Blocks []*BlockFees `json:"blocks"`
+	Total  Fees         `json:"total"`
+}
+
+// GetFeeBreakdown returns the L2 execution fees, L1 data fees and burned base
+// fees paid in each canonical block of the given range (inclusive), along with
+// their totals. The per-transaction L1 data fee is available in the receipts.
+func (api *ScrollAPI) GetFeeBreakdown(ctx context.Context, fromBlock rpc.BlockNumber, toBlock rpc.BlockNumber) (*FeeBreakdown, error) {
+	from, to := api.resolveBlockNumber(fromBlock), api.resolveBlockNumber(toBlock)
+	if from > to {
+		return nil, fmt.Errorf("invalid block range: from %d > to %d", from, to)
+	}
+	if to-from+1 > maxFeeBreakdownBlocks {
+		return nil, fmt.Errorf("block range too large: %d > %d", to-from+1, maxFeeBreakdownBlocks)
+	}
+
+	var (
+		db     = api.eth.ChainDb()
+		config = api.eth.blockchain.Config()
+		total  = types.NewBlockFees()
+		result = &FeeBreakdown{}
+	)
+	for number := from; number <= to; number++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		block := api.eth.blockchain.GetBlockByNumber(number)
+		if block == nil {
+			break
+		}
+		// blocks imported without fee accounting enabled are computed from receipts
+		blockFees := rawdb.ReadBlockFees(db, block.Hash())
+		if blockFees == nil {
+			receipts := api.eth.blockchain.GetReceiptsByHash(block.Hash())
+			if receipts == nil {
+				return nil, fmt.Errorf("receipts of block %d not found", number)
+			}
+			blockFees = fees.CalculateBlockFees(config, block.Header(), block.Transactions(), receipts)
+		}
+		total.Add(blockFees)
+		result.Blocks = append(result.Blocks, &BlockFees{
+			Number: hexutil.Uint64(number),
+			Hash:   block.Hash(),
+			Fees:   newRPCFees(blockFees),
+		})
+	}
+	result.Total = newRPCFees(total)
+	return result, nil
+}
+
+// resolveBlockNumber converts a block number into an absolute one. The pending
+// and latest tags resolve to the current head.
+func (api *ScrollAPI) resolveBlockNumber(number rpc.BlockNumber) uint64 {
+	switch {
+	case number >= 0:
+		return uint64(number)
+	case number == rpc.FinalizedBlockNumber || number == rpc.SafeBlockNumber:
+		if finalized := rawdb.ReadFinalizedL2BlockNumber(api.eth.ChainDb()); finalized != nil {
+			return *finalized
+		}
+		return 0
+	default:
+		return api.eth.blockchain.CurrentBlock().NumberU64()
+	}
+}
+
 // RPCTransaction is the standard RPC transaction return type with some additional skip-related fields.
 type RPCTransaction struct {
 	ethapi.RPCTransaction
@@ -781,11 +1219,7 @@ func (api *ScrollAPI) GetSkippedTransaction(ctx context.Context, hash common.Has
 	if stx == nil {
 		return nil, nil
 	}
-	var rpcTx RPCTransaction
-	rpcTx.RPCTransaction = *ethapi.NewRPCTransaction(stx.Tx, common.Hash{}, 0, 0, nil, api.eth.blockchain.Config())
-	rpcTx.SkipReason = stx.Reason
-	rpcTx.SkipBlockNumber = (*hexutil.Big)(new(big.Int).SetUint64(stx.BlockNumber))
-	rpcTx.SkipBlockHash = stx.BlockHash
+	rpcTx := api.newSkippedRPCTransaction(stx.Tx, stx.Reason, stx.BlockNumber, stx.BlockHash)
 	if len(stx.TracesBytes) != 0 {
 		traces := &types.BlockTrace{}
 		if err := json.Unmarshal(stx.TracesBytes, traces); err != nil {
@@ -793,7 +1227,18 @@ func (api *ScrollAPI) GetSkippedTransaction(ctx context.Context, hash common.Has
 		}
 		rpcTx.Traces = traces
 	}
-	return &rpcTx, nil
+	return rpcTx, nil
+}
+
+// newSkippedRPCTransaction returns the RPC representation of a skipped transaction,
+// without traces.
+func (api *ScrollAPI) newSkippedRPCTransaction(tx *types.Transaction, reason string, blockNumber uint64, blockHash *common.Hash) *RPCTransaction {
+	return &RPCTransaction{
+		RPCTransaction:  *ethapi.NewRPCTransaction(tx, common.Hash{}, 0, 0, nil, api.eth.blockchain.Config()),
+		SkipReason:      reason,
+		SkipBlockNumber: (*hexutil.Big)(new(big.Int).SetUint64(blockNumber)),
+		SkipBlockHash:   blockHash,
+	}
 }
 
 // GetSkippedTransactionHashes returns a list of skipped transaction hashes between the two indices provided (inclusive).
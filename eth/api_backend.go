@@ -300,6 +300,10 @@ func (b *EthAPIBackend) TxPoolContentFrom(addr common.Address) (types.Transactio
 	return b.eth.TxPool().ContentFrom(addr)
 }
 
+func (b *EthAPIBackend) TxPoolCircuitOverflowed() []*core.CircuitOverflowTx {
+	return b.eth.TxPool().CircuitOverflowed()
+}
+
 func (b *EthAPIBackend) TxPool() *core.TxPool {
 	return b.eth.TxPool()
 }
@@ -363,6 +367,22 @@ func (b *EthAPIBackend) ServiceFilter(ctx context.Context, session *bloombits.Ma
 	}
 }
 
+func (b *EthAPIBackend) LogIndexStatus() (uint64, uint64) {
+	if b.eth.logIndexer == nil {
+		return params.LogIndexBlocks, 0
+	}
+	sections, _, _ := b.eth.logIndexer.Sections()
+	return params.LogIndexBlocks, sections
+}
+
+func (b *EthAPIBackend) AccountHistoryStatus() (bool, uint64) {
+	if b.eth.accountHistory == nil {
+		return false, 0
+	}
+	sections, _, _ := b.eth.accountHistory.Sections()
+	return true, sections * accountHistorySectionSize
+}
+
 func (b *EthAPIBackend) Engine() consensus.Engine {
 	return b.eth.engine
 }
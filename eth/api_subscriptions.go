@@ -0,0 +1,285 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/core"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/rollup/rollup_sync_service"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+const (
+	// rollupEventChanSize is the size of the channels receiving the events of
+	// the rollup subscriptions.
+	rollupEventChanSize = 16
+
+	// maxNotifiedL1Messages is the maximum number of new L1 messages read from
+	// the database at once when notifying them.
+	maxNotifiedL1Messages = 1000
+)
+
+var (
+	errL1MessageSyncDisabled = errors.New("L1 message sync is disabled")
+	errRollupVerifyDisabled  = errors.New("rollup verification is disabled")
+)
+
+// NewL1Messages creates a subscription that is triggered for every L1 message
+// synced from L1, with the L1 transaction which enqueued it.
+func (api *ScrollAPI) NewL1Messages(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	syncService := api.eth.SyncService()
+	if syncService == nil {
+		return &rpc.Subscription{}, errL1MessageSyncDisabled
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		events := make(chan core.NewL1MsgsEvent, rollupEventChanSize)
+		eventsSub := syncService.SubscribeNewL1MsgsEvent(events)
+		defer eventsSub.Unsubscribe()
+
+		// The events only count the new messages, so keep track of the
+		// next message to notify instead.
+		db := api.eth.ChainDb()
+		var next uint64
+		if highest := rawdb.ReadHighestSyncedQueueIndex(db); rawdb.ReadL1Message(db, highest) != nil {
+			next = highest + 1
+		}
+		for {
+			select {
+			case <-events:
+				for {
+					msgs := rawdb.ReadL1MessagesFrom(db, next, maxNotifiedL1Messages)
+					for i := range msgs {
+						notifier.Notify(rpcSub.ID, newL1MessageStatus(db, &msgs[i]))
+					}
+					next += uint64(len(msgs))
+					if len(msgs) < maxNotifiedL1Messages {
+						break
+					}
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// L1MessagesInL2Block lists the L1 messages included or skipped by an L2 block.
+type L1MessagesInL2Block struct {
+	BlockHash   common.Hash        `json:"blockHash"`
+	BlockNumber hexutil.Uint64     `json:"blockNumber"`
+	Messages    []*L1MessageStatus `json:"messages"`
+}
+
+// L1MessagesInL2Block creates a subscription that is triggered for every new
+// canonical L2 block including or skipping L1 messages, including the blocks
+// made canonical by a chain reorg.
+func (api *ScrollAPI) L1MessagesInL2Block(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		events := make(chan core.ChainEvent, rollupEventChanSize)
+		eventsSub := api.eth.blockchain.SubscribeChainEvent(events)
+		defer eventsSub.Unsubscribe()
+
+		reorgs := make(chan core.ChainReorgEvent, rollupEventChanSize)
+		reorgsSub := api.eth.blockchain.SubscribeChainReorgEvent(reorgs)
+		defer reorgsSub.Unsubscribe()
+
+		notify := func(block *types.Block) {
+			if result := api.l1MessagesInL2Block(block); len(result.Messages) > 0 {
+				notifier.Notify(rpcSub.ID, result)
+			}
+		}
+		for {
+			select {
+			case ev := <-events:
+				// The blocks of a reorg are posted before the new head
+			drain:
+				for {
+					select {
+					case reorg := <-reorgs:
+						for _, block := range reorg.Blocks {
+							notify(block)
+						}
+					default:
+						break drain
+					}
+				}
+				notify(ev.Block)
+			case reorg := <-reorgs:
+				for _, block := range reorg.Blocks {
+					notify(block)
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// l1MessagesInL2Block returns the L1 messages included or skipped by the given
+// block, ordered by queue index. The block skips the messages it processes but
+// doesn't include.
+func (api *ScrollAPI) l1MessagesInL2Block(block *types.Block) *L1MessagesInL2Block {
+	var (
+		db     = api.eth.ChainDb()
+		hash   = block.Hash()
+		number = hexutil.Uint64(block.NumberU64())
+		result = &L1MessagesInL2Block{BlockHash: hash, BlockNumber: number}
+	)
+	included := make(map[uint64]bool)
+	for i, tx := range block.Transactions() {
+		msg := tx.AsL1MessageTx()
+		if msg == nil {
+			continue
+		}
+		index := hexutil.Uint64(i)
+		status := newL1MessageStatus(db, msg)
+		status.Status = L1MessageIncluded
+		status.BlockHash = &hash
+		status.BlockNumber = &number
+		status.TransactionIndex = &index
+		result.Messages = append(result.Messages, status)
+		included[msg.QueueIndex] = true
+	}
+	first := rawdb.ReadFirstQueueIndexNotInL2Block(db, block.ParentHash())
+	next := rawdb.ReadFirstQueueIndexNotInL2Block(db, hash)
+	if first == nil || next == nil {
+		return result
+	}
+	for queueIndex := *first; queueIndex < *next; queueIndex++ {
+		if included[queueIndex] {
+			continue
+		}
+		msg := rawdb.ReadL1Message(db, queueIndex)
+		if msg == nil {
+			continue
+		}
+		status := newL1MessageStatus(db, msg)
+		status.Status = L1MessageSkipped
+		status.BlockHash = &hash
+		status.BlockNumber = &number
+		if stx := rawdb.ReadSkippedTransaction(db, status.TxHash); stx != nil {
+			status.SkipReason = stx.Reason
+		}
+		result.Messages = append(result.Messages, status)
+	}
+	sort.Slice(result.Messages, func(i, j int) bool {
+		return result.Messages[i].QueueIndex < result.Messages[j].QueueIndex
+	})
+	return result
+}
+
+// RollupBatch is a batch committed, reverted or finalized on L1.
+type RollupBatch struct {
+	Status        string          `json:"status"`
+	BatchIndex    hexutil.Uint64  `json:"batchIndex"`
+	BatchHash     common.Hash     `json:"batchHash"`
+	StartBlock    *hexutil.Uint64 `json:"startBlockNumber"`
+	EndBlock      *hexutil.Uint64 `json:"endBlockNumber"`
+	L1BlockNumber hexutil.Uint64  `json:"l1BlockNumber"`
+	L1TxHash      common.Hash     `json:"l1TxHash"`
+}
+
+// newRollupBatch returns the RPC representation of a batch event.
+func newRollupBatch(ev rollup_sync_service.BatchEvent) *RollupBatch {
+	batch := &RollupBatch{
+		Status:        ev.Status,
+		BatchIndex:    hexutil.Uint64(ev.BatchIndex),
+		BatchHash:     ev.BatchHash,
+		L1BlockNumber: hexutil.Uint64(ev.L1BlockNumber),
+		L1TxHash:      ev.L1TxHash,
+	}
+	if ev.EndBlock != 0 {
+		batch.StartBlock = (*hexutil.Uint64)(&ev.StartBlock)
+		batch.EndBlock = (*hexutil.Uint64)(&ev.EndBlock)
+	}
+	return batch
+}
+
+// RollupBatches creates a subscription that is triggered for every batch
+// committed, reverted or finalized on L1. The events are only known once the L1
+// blocks emitting them are finalized, and require rollup verification enabled.
+func (api *ScrollAPI) RollupBatches(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	rollupSyncService := api.eth.rollupSyncService
+	if rollupSyncService == nil {
+		return &rpc.Subscription{}, errRollupVerifyDisabled
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		events := make(chan rollup_sync_service.BatchEvent, rollupEventChanSize)
+		eventsSub := rollupSyncService.SubscribeBatchEvent(events)
+		defer eventsSub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-events:
+				notifier.Notify(rpcSub.ID, newRollupBatch(ev))
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// SkippedTransactions creates a subscription that is triggered for every
+// transaction skipped by the local sequencer, and for every L1 message skipped
+// by an imported block once it is written to the canonical chain.
+func (api *ScrollAPI) SkippedTransactions(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		events := make(chan core.SkippedTxEvent, rollupEventChanSize)
+		eventsSub := api.eth.blockchain.SubscribeSkippedTxEvent(events)
+		defer eventsSub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-events:
+				notifier.Notify(rpcSub.ID, api.newSkippedRPCTransaction(ev.Tx, ev.Reason, ev.BlockNumber, ev.BlockHash))
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
@@ -18,6 +18,7 @@ package eth
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"reflect"
@@ -27,8 +28,10 @@ import (
 	"github.com/davecgh/go-spew/spew"
 
 	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
 	"github.com/scroll-tech/go-ethereum/core/rawdb"
 	"github.com/scroll-tech/go-ethereum/core/state"
+	"github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/crypto"
 	"github.com/scroll-tech/go-ethereum/trie"
 )
@@ -220,3 +223,83 @@ func TestStorageRangeAt(t *testing.T) {
 		}
 	}
 }
+
+func TestQueueIndexOrL1TxHashUnmarshal(t *testing.T) {
+	hash := common.HexToHash("0x5e8f7e8a6d7c6b5a4f3e2d1c0b0a09080706050403020100f0e0d0c0b0a09080")
+	tests := []struct {
+		input      string
+		queueIndex *uint64
+		l1TxHash   *common.Hash
+		fail       bool
+	}{
+		{input: `12`, queueIndex: func() *uint64 { n := uint64(12); return &n }()},
+		{input: `"0x1f"`, queueIndex: func() *uint64 { n := uint64(31); return &n }()},
+		{input: `"` + hash.Hex() + `"`, l1TxHash: &hash},
+		{input: `"0xzz"`, fail: true},
+		{input: `true`, fail: true},
+	}
+	for i, tt := range tests {
+		var q QueueIndexOrL1TxHash
+		err := json.Unmarshal([]byte(tt.input), &q)
+		if tt.fail {
+			if err == nil {
+				t.Errorf("test %d: expected error for %s", i, tt.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("test %d: failed to unmarshal %s: %v", i, tt.input, err)
+		}
+		if !reflect.DeepEqual(q.QueueIndex, tt.queueIndex) || !reflect.DeepEqual(q.L1TxHash, tt.l1TxHash) {
+			t.Errorf("test %d: mismatch: have %v/%v, want %v/%v", i, q.QueueIndex, q.L1TxHash, tt.queueIndex, tt.l1TxHash)
+		}
+	}
+}
+
+func TestL1MessagesInL2Block(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	msgs := make([]types.L1MessageTx, 4)
+	for i := range msgs {
+		msgs[i] = types.L1MessageTx{QueueIndex: uint64(i), Gas: 21000, To: &common.Address{}, Value: big.NewInt(0), Sender: common.Address{1}}
+	}
+	rawdb.WriteL1Messages(db, msgs)
+
+	// The block processes the messages 1 to 3, skipping the message 2
+	parentHash := common.Hash{0xff}
+	skipped := types.NewTx(&msgs[2])
+	rawdb.WriteSkippedTransaction(db, skipped, nil, "row consumption overflow", 5, nil)
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(5), ParentHash: parentHash}).
+		WithBody([]*types.Transaction{types.NewTx(&msgs[1]), types.NewTx(&msgs[3])}, nil)
+	rawdb.WriteFirstQueueIndexNotInL2Block(db, parentHash, 1)
+	rawdb.WriteFirstQueueIndexNotInL2Block(db, block.Hash(), 4)
+
+	api := NewScrollAPI(&Ethereum{chainDb: db})
+	result := api.l1MessagesInL2Block(block)
+	if result.BlockHash != block.Hash() || result.BlockNumber != 5 {
+		t.Fatalf("wrong block: have %x/%d, want %x/5", result.BlockHash, result.BlockNumber, block.Hash())
+	}
+	want := []struct {
+		queueIndex hexutil.Uint64
+		status     string
+		txIndex    *hexutil.Uint64
+		reason     string
+	}{
+		{1, L1MessageIncluded, new(hexutil.Uint64), ""},
+		{2, L1MessageSkipped, nil, "row consumption overflow"},
+		{3, L1MessageIncluded, func() *hexutil.Uint64 { n := hexutil.Uint64(1); return &n }(), ""},
+	}
+	if len(result.Messages) != len(want) {
+		t.Fatalf("wrong number of messages: have %d, want %d", len(result.Messages), len(want))
+	}
+	for i, msg := range result.Messages {
+		if msg.QueueIndex != want[i].queueIndex || msg.Status != want[i].status || msg.SkipReason != want[i].reason {
+			t.Errorf("message %d: have %d/%s/%q, want %d/%s/%q", i, msg.QueueIndex, msg.Status, msg.SkipReason, want[i].queueIndex, want[i].status, want[i].reason)
+		}
+		if !reflect.DeepEqual(msg.TransactionIndex, want[i].txIndex) {
+			t.Errorf("message %d: wrong transaction index: have %v, want %v", i, msg.TransactionIndex, want[i].txIndex)
+		}
+		if msg.BlockHash == nil || *msg.BlockHash != block.Hash() {
+			t.Errorf("message %d: wrong block hash %v", i, msg.BlockHash)
+		}
+	}
+}
@@ -22,12 +22,14 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"os"
 	"runtime"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/scroll-tech/go-ethereum/accounts"
+	"github.com/scroll-tech/go-ethereum/accounts/external"
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/common/hexutil"
 	"github.com/scroll-tech/go-ethereum/consensus"
@@ -35,6 +37,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/core"
 	"github.com/scroll-tech/go-ethereum/core/bloombits"
 	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/state"
 	"github.com/scroll-tech/go-ethereum/core/state/pruner"
 	"github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/core/vm"
@@ -56,6 +59,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/params"
 	"github.com/scroll-tech/go-ethereum/rlp"
 	"github.com/scroll-tech/go-ethereum/rollup/rollup_sync_service"
+	"github.com/scroll-tech/go-ethereum/rollup/sequencer"
 	"github.com/scroll-tech/go-ethereum/rollup/sync_service"
 	"github.com/scroll-tech/go-ethereum/rollup/tracing"
 	"github.com/scroll-tech/go-ethereum/rpc"
@@ -88,12 +92,15 @@ type Ethereum struct {
 	bloomRequests     chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
 	bloomIndexer      *core.ChainIndexer             // Bloom indexer operating during block imports
 	closeBloomHandler chan struct{}
+	logIndexer        *core.ChainIndexer // Log indexer operating during block imports, if enabled
+	accountHistory    *core.ChainIndexer // Account history indexer operating during block imports, if enabled
 
 	APIBackend *EthAPIBackend
 
-	miner     *miner.Miner
-	gasPrice  *big.Int
-	etherbase common.Address
+	miner          *miner.Miner
+	sequencerLease *sequencer.Elector // Guards sealing when running as a failover sequencer
+	gasPrice       *big.Int
+	etherbase      common.Address
 
 	networkID     uint64
 	netRPCService *ethapi.PublicNetAPI
@@ -193,6 +200,7 @@ func New(stack *node.Node, config *ethconfig.Config, l1Client sync_service.EthCl
 			SnapshotLimit:       config.SnapshotCache,
 			Preimages:           config.Preimages,
 			MPTWitness:          config.MPTWitness,
+			BlockFees:           config.BlockFees,
 		}
 	)
 	eth.blockchain, err = core.NewBlockChain(chainDb, cacheConfig, chainConfig, eth.engine, vmConfig, eth.shouldPreserve, &config.TxLookupLimit)
@@ -211,6 +219,16 @@ func New(stack *node.Node, config *ethconfig.Config, l1Client sync_service.EthCl
 		rawdb.WriteChainConfig(chainDb, genesisHash, chainConfig)
 	}
 	eth.bloomIndexer.Start(eth.blockchain)
+	if config.LogIndex {
+		eth.logIndexer = core.NewLogIndexer(chainDb, params.LogIndexBlocks, params.BloomConfirms)
+		eth.logIndexer.Start(eth.blockchain)
+	}
+	if config.AccountHistory {
+		eth.accountHistory = NewAccountHistoryIndexer(eth.blockchain, func(block *types.Block) (*state.StateDB, error) {
+			return eth.stateAtBlock(block, accountHistoryReexec, nil, true, false)
+		})
+		eth.accountHistory.Start(eth.blockchain)
+	}
 
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = stack.ResolvePath(config.TxPool.Journal)
@@ -256,6 +274,21 @@ func New(stack *node.Node, config *ethconfig.Config, l1Client sync_service.EthCl
 	eth.miner = miner.New(eth, &config.Miner, chainConfig, eth.EventMux(), eth.engine, eth.isLocalBlock)
 	eth.miner.SetExtra(makeExtraData(config.Miner.ExtraData))
 
+	// Only seal blocks while holding the sequencer leader lease, if configured
+	if config.Miner.LeaseFile != "" {
+		engine, ok := eth.engine.(*clique.Clique)
+		if !ok {
+			return nil, errors.New("sequencer leader lease requires the clique consensus engine")
+		}
+		holder := config.Miner.LeaseID
+		if holder == "" {
+			hostname, _ := os.Hostname()
+			holder = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+		}
+		eth.sequencerLease = sequencer.NewElector(sequencer.NewFileLease(config.Miner.LeaseFile), holder, config.Miner.LeaseTTL)
+		engine.SetSealGuard(eth.sequencerLease)
+	}
+
 	eth.APIBackend = &EthAPIBackend{stack.Config().ExtRPCEnabled(), stack.Config().AllowUnprotectedTxs, eth, nil}
 	if eth.APIBackend.allowUnprotectedTxs {
 		log.Info("Unprotected transactions allowed")
@@ -499,12 +532,27 @@ func (s *Ethereum) StartMining(threads int) error {
 			return fmt.Errorf("etherbase missing: %v", err)
 		}
 		if clique, ok := s.engine.(*clique.Clique); ok {
-			wallet, err := s.accountManager.Find(accounts.Account{Address: eb})
-			if wallet == nil || err != nil {
-				log.Error("Etherbase account unavailable locally", "err", err)
-				return fmt.Errorf("signer missing: %v", err)
+			if endpoint := s.config.Miner.Signer; endpoint != "" {
+				// Keep the sealing key out of the node, clef approves every header
+				signer, err := external.NewExternalSigner(endpoint)
+				if err != nil {
+					log.Error("Cannot connect to external signer", "url", endpoint, "err", err)
+					return fmt.Errorf("external signer unavailable: %v", err)
+				}
+				if !signer.Contains(accounts.Account{Address: eb}) {
+					log.Error("Etherbase account unavailable in external signer", "url", endpoint, "address", eb)
+					return fmt.Errorf("signer missing: %v", accounts.ErrUnknownAccount)
+				}
+				log.Info("Using external signer for clique sealing", "url", endpoint, "address", eb)
+				clique.Authorize(eb, signer.SignData)
+			} else {
+				wallet, err := s.accountManager.Find(accounts.Account{Address: eb})
+				if wallet == nil || err != nil {
+					log.Error("Etherbase account unavailable locally", "err", err)
+					return fmt.Errorf("signer missing: %v", err)
+				}
+				clique.Authorize(eb, wallet.SignData)
 			}
-			clique.Authorize(eb, wallet.SignData)
 		}
 		// If mining is started, we can disable the transaction rejection mechanism
 		// introduced to speed sync times.
@@ -573,6 +621,11 @@ func (s *Ethereum) Start() error {
 	//}
 	// Start the networking layer and the light server if requested
 	s.handler.Start(maxPeers)
+
+	// Start competing for the sequencer leader lease
+	if s.sequencerLease != nil {
+		s.sequencerLease.Start()
+	}
 	return nil
 }
 
@@ -587,12 +640,21 @@ func (s *Ethereum) Stop() error {
 	// Then stop everything else.
 	s.bloomIndexer.Close()
 	close(s.closeBloomHandler)
+	if s.logIndexer != nil {
+		s.logIndexer.Close()
+	}
+	if s.accountHistory != nil {
+		s.accountHistory.Close()
+	}
 	s.txPool.Stop()
 	s.syncService.Stop()
 	if s.config.EnableRollupVerify {
 		s.rollupSyncService.Stop()
 	}
 	s.miner.Close()
+	if s.sequencerLease != nil {
+		s.sequencerLease.Stop()
+	}
 	s.blockchain.Stop()
 	s.engine.Close()
 	rawdb.PopUncleanShutdownMarker(s.chainDb)
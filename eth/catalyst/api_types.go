@@ -19,6 +19,7 @@ package catalyst
 import (
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/core/types"
 )
 
 //go:generate go run github.com/fjl/gencodec -type assembleBlockParams -field-override assembleBlockParamsMarshaling -out gen_blockparams.go
@@ -68,3 +69,57 @@ type newBlockResponse struct {
 type genericResponse struct {
 	Success bool `json:"success"`
 }
+
+// assembleL2BlockParams are the parameters of engine_assembleL2BlockV1. The
+// block includes the L1 messages [L1QueueStart, L1QueueEnd) followed by the
+// given L2 transactions, in order.
+type assembleL2BlockParams struct {
+	ParentHash   common.Hash     `json:"parentHash"`
+	Timestamp    hexutil.Uint64  `json:"timestamp"`
+	L1QueueStart hexutil.Uint64  `json:"l1QueueStart"`
+	L1QueueEnd   hexutil.Uint64  `json:"l1QueueEnd"`
+	Transactions []hexutil.Bytes `json:"transactions"`
+}
+
+// skippedTransaction is a transaction that could not be included in an
+// assembled block.
+type skippedTransaction struct {
+	Hash        common.Hash   `json:"hash"`
+	Reason      string        `json:"reason"`
+	Transaction hexutil.Bytes `json:"transaction"` // Encoded transaction, recorded on import
+}
+
+// newSkippedTransaction returns the skipped transaction report of tx.
+func newSkippedTransaction(tx *types.Transaction, reason string) skippedTransaction {
+	enc, _ := tx.MarshalBinary()
+	return skippedTransaction{Hash: tx.Hash(), Reason: reason, Transaction: enc}
+}
+
+// l2ExecutableData is the block representation of the Scroll engine API.
+type l2ExecutableData struct {
+	BlockHash    common.Hash      `json:"blockHash"`
+	ParentHash   common.Hash      `json:"parentHash"`
+	Miner        common.Address   `json:"miner"`
+	StateRoot    common.Hash      `json:"stateRoot"`
+	Number       hexutil.Uint64   `json:"number"`
+	GasLimit     hexutil.Uint64   `json:"gasLimit"`
+	GasUsed      hexutil.Uint64   `json:"gasUsed"`
+	Timestamp    hexutil.Uint64   `json:"timestamp"`
+	ReceiptRoot  common.Hash      `json:"receiptsRoot"`
+	LogsBloom    hexutil.Bytes    `json:"logsBloom"`
+	ExtraData    hexutil.Bytes    `json:"extraData"`
+	Difficulty   *hexutil.Big     `json:"difficulty"`
+	MixDigest    common.Hash      `json:"mixHash"`
+	Nonce        types.BlockNonce `json:"nonce"`
+	BaseFee      *hexutil.Big     `json:"baseFeePerGas,omitempty"`
+	Transactions []hexutil.Bytes  `json:"transactions"`
+
+	// First L1 message not processed by this block or its ancestors,
+	// including the messages skipped by the block builder.
+	NextL1QueueIndex hexutil.Uint64 `json:"nextL1QueueIndex"`
+
+	// Block builder results, stored on import like for the blocks sealed by
+	// the local sequencer
+	RowConsumption      *types.RowConsumption `json:"rowConsumption,omitempty"`
+	SkippedTransactions []skippedTransaction  `json:"skippedTransactions,omitempty"`
+}
@@ -0,0 +1,422 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package catalyst
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/consensus/misc"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/eth"
+	"github.com/scroll-tech/go-ethereum/ethdb"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/node"
+	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rollup/circuitcapacitychecker"
+	"github.com/scroll-tech/go-ethereum/rollup/fees"
+	"github.com/scroll-tech/go-ethereum/rollup/pipeline"
+	"github.com/scroll-tech/go-ethereum/rpc"
+	"github.com/scroll-tech/go-ethereum/trie"
+)
+
+// assembleTimeout is the maximum time spent executing and circuit checking the
+// transactions of a block assembled through the engine API.
+const assembleTimeout = 10 * time.Second
+
+// RegisterScrollEngine adds the Scroll engine APIs to the node, allowing an
+// external sequencer or consensus client to drive block production.
+func RegisterScrollEngine(stack *node.Node, backend *eth.Ethereum) {
+	log.Warn("Scroll engine API enabled")
+	stack.RegisterAPIs([]rpc.API{
+		{
+			Namespace: "engine",
+			Version:   "1.0",
+			Service:   newScrollEngineAPI(backend),
+			Public:    true,
+		},
+	})
+}
+
+type scrollEngineAPI struct {
+	eth *eth.Ethereum
+
+	cccMutex sync.Mutex // Serializes block assembly, which shares a single checker
+	ccc      *circuitcapacitychecker.CircuitCapacityChecker
+}
+
+func newScrollEngineAPI(eth *eth.Ethereum) *scrollEngineAPI {
+	return &scrollEngineAPI{
+		eth: eth,
+		ccc: circuitcapacitychecker.NewCircuitCapacityChecker(true),
+	}
+}
+
+// AssembleL2BlockV1 builds a block on top of the given parent, containing the
+// L1 messages of the given queue range followed by the given L2 transactions.
+// The block is built through the same pipeline as the sequencer, so it respects
+// the circuit capacity. Transactions that could not be included are reported as
+// skipped. The block keeps the requested timestamp if the consensus engine
+// accepts it. The block is not imported, see NewL2BlockV1.
+func (api *scrollEngineAPI) AssembleL2BlockV1(params assembleL2BlockParams) (*l2ExecutableData, error) {
+	bc := api.eth.BlockChain()
+	config := bc.Config()
+
+	parent := bc.GetBlockByHash(params.ParentHash)
+	if parent == nil {
+		return nil, fmt.Errorf("cannot assemble block with unknown parent %s", params.ParentHash)
+	}
+	if parent.Time() > uint64(params.Timestamp) {
+		return nil, fmt.Errorf("child timestamp lower than parent's: %d > %d", parent.Time(), params.Timestamp)
+	}
+	txs, err := decodeTransactions(fromHexBytes(params.Transactions))
+	if err != nil {
+		return nil, err
+	}
+	for i, tx := range txs {
+		if tx.IsL1MessageTx() {
+			return nil, fmt.Errorf("transaction %d is an L1 message, use the L1 queue range instead", i)
+		}
+	}
+	l1Messages, err := api.collectL1Messages(parent, uint64(params.L1QueueStart), uint64(params.L1QueueEnd))
+	if err != nil {
+		return nil, err
+	}
+
+	coinbase, _ := api.eth.Etherbase()
+	num := parent.Number()
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     num.Add(num, common.Big1),
+		Coinbase:   coinbase,
+		GasLimit:   parent.GasLimit(),
+		Extra:      []byte{},
+		Time:       uint64(params.Timestamp),
+	}
+	parentState, err := bc.StateAt(parent.Root())
+	if err != nil {
+		return nil, err
+	}
+	if config.IsCurie(header.Number) {
+		header.BaseFee = misc.CalcBaseFee(config, parent.Header(), fees.GetL1BaseFee(parentState))
+	}
+	if err := api.eth.Engine().Prepare(bc, header); err != nil {
+		return nil, err
+	}
+	// Clique moves the timestamp to the present, restore the requested one
+	if err := verifyAssembleTimestamp(config, parent.Header(), uint64(params.Timestamp)); err != nil {
+		return nil, err
+	}
+	header.Time = uint64(params.Timestamp)
+
+	// zkEVM requirement: Curie transition block contains 0 transactions, bypass pipeline.
+	if config.CurieBlock != nil && config.CurieBlock.Cmp(header.Number) == 0 {
+		misc.ApplyCurieHardFork(parentState)
+		return api.finalize(&pipeline.Result{
+			Rows: &types.RowConsumption{},
+			FinalBlock: &pipeline.BlockCandidate{
+				NextL1MsgIndex: uint64(params.L1QueueStart),
+				Header:         header,
+				State:          parentState,
+			},
+		}, nil)
+	}
+
+	api.cccMutex.Lock()
+	defer api.cccMutex.Unlock()
+
+	p := pipeline.NewPipeline(bc, bc.GetVMConfig(), parentState, header, uint64(params.L1QueueStart), api.ccc)
+	if err := p.Start(time.Now().Add(assembleTimeout)); err != nil {
+		return nil, err
+	}
+
+	var skipped []skippedTransaction
+	onFailingTxn := func(txIndex int, tx *types.Transaction, err error) bool {
+		skipped = append(skipped, newSkippedTransaction(tx, err.Error()))
+		return false
+	}
+	res := p.TryPushTxns(l1Messages, onFailingTxn)
+	for _, tx := range txs {
+		if res != nil {
+			break
+		}
+		var err error
+		if res, err = p.TryPushTxn(tx); err != nil {
+			if errors.Is(err, pipeline.ErrApplyStageDone) {
+				break
+			}
+			onFailingTxn(0, tx, err)
+		}
+	}
+	if res == nil {
+		res = p.Finish()
+	} else {
+		p.Kill()
+	}
+	if res == nil {
+		return nil, errors.New("block assembly aborted")
+	}
+	if res.OverflowingTx != nil {
+		skipped = append(skipped, newSkippedTransaction(res.OverflowingTx, res.CCCErr.Error()))
+	}
+	if res.FinalBlock == nil {
+		return nil, fmt.Errorf("no transaction could be included in the block, skipped: %v", skipped)
+	}
+	return api.finalize(res, skipped)
+}
+
+// verifyAssembleTimestamp checks that the consensus engine of the chain accepts
+// the given timestamp for a child of the given parent.
+func verifyAssembleTimestamp(config *params.ChainConfig, parent *types.Header, timestamp uint64) error {
+	if config.Clique != nil {
+		if timestamp > uint64(time.Now().Unix()) {
+			return fmt.Errorf("timestamp %d in the future", timestamp)
+		}
+		if !config.Clique.RelaxedPeriod && timestamp < parent.Time+config.Clique.Period {
+			return fmt.Errorf("timestamp %d before the end of the clique period of %ds after the parent's %d", timestamp, config.Clique.Period, parent.Time)
+		}
+	}
+	return nil
+}
+
+// collectL1Messages reads the L1 messages [start, end) from the local database,
+// start must be the first message not yet included on the parent's chain.
+func (api *scrollEngineAPI) collectL1Messages(parent *types.Block, start, end uint64) (*types.L1MessagesByQueueIndex, error) {
+	next := rawdb.ReadFirstQueueIndexNotInL2Block(api.eth.ChainDb(), parent.Hash())
+	if next == nil {
+		return nil, fmt.Errorf("missing L1 queue index of parent %s", parent.Hash())
+	}
+	if start != *next {
+		return nil, fmt.Errorf("invalid L1 queue start: have %d, want %d", start, *next)
+	}
+	if end < start {
+		return nil, fmt.Errorf("invalid L1 queue range: [%d, %d)", start, end)
+	}
+	if end > start {
+		scroll := api.eth.BlockChain().Config().Scroll
+		if !scroll.ShouldIncludeL1Messages() {
+			return nil, errors.New("L1 messages are not enabled on this chain")
+		}
+		if end-start > scroll.L1Config.NumL1MessagesPerBlock {
+			return nil, fmt.Errorf("too many L1 messages: have %d, max %d", end-start, scroll.L1Config.NumL1MessagesPerBlock)
+		}
+	}
+	msgs := rawdb.ReadL1MessagesFrom(api.eth.ChainDb(), start, end-start)
+	if uint64(len(msgs)) != end-start {
+		return nil, fmt.Errorf("L1 messages not available: have %d, want %d", len(msgs), end-start)
+	}
+	return types.NewL1MessagesByQueueIndex(msgs)
+}
+
+func (api *scrollEngineAPI) finalize(res *pipeline.Result, skipped []skippedTransaction) (*l2ExecutableData, error) {
+	candidate := res.FinalBlock
+	block, err := api.eth.Engine().FinalizeAndAssemble(api.eth.BlockChain(), candidate.Header, candidate.State,
+		candidate.Txs, nil /* uncles */, candidate.Receipts)
+	if err != nil {
+		return nil, err
+	}
+	// Messages skipped after the last included one can't be recorded in the
+	// block, so they are not consumed and will be offered again
+	next, err := nextL1QueueIndex(api.eth.ChainDb(), block)
+	if err != nil {
+		return nil, err
+	}
+	data := newL2ExecutableData(block)
+	data.NextL1QueueIndex = hexutil.Uint64(next)
+	data.RowConsumption = res.Rows
+	data.SkippedTransactions = skipped
+	return data, nil
+}
+
+// NewL2BlockV1 validates the given block and imports it into the chain. Any L1
+// messages in the block must match the local view of the L1 message queue, and
+// the next L1 queue index must follow from them. The row consumption and the L2
+// transactions skipped by the block builder are stored along with the block.
+func (api *scrollEngineAPI) NewL2BlockV1(params l2ExecutableData) (*newBlockResponse, error) {
+	bc := api.eth.BlockChain()
+	parent := bc.GetBlockByHash(params.ParentHash)
+	if parent == nil {
+		return &newBlockResponse{false}, fmt.Errorf("could not find parent %x", params.ParentHash)
+	}
+	block, err := params.toBlock()
+	if err != nil {
+		return &newBlockResponse{false}, err
+	}
+	if block.Hash() != params.BlockHash {
+		return &newBlockResponse{false}, fmt.Errorf("block hash mismatch: have %x, want %x", block.Hash(), params.BlockHash)
+	}
+	if bc.HasBlock(block.Hash(), block.NumberU64()) {
+		return &newBlockResponse{true}, nil
+	}
+	if params.RowConsumption == nil {
+		return &newBlockResponse{false}, errors.New("missing row consumption")
+	}
+	skipped, reasons, err := decodeSkippedTransactions(params.SkippedTransactions)
+	if err != nil {
+		return &newBlockResponse{false}, err
+	}
+
+	// The chain derives the next queue index from the L1 messages of the block
+	// on import, a caller can't skip further messages without them being
+	// recorded in a block.
+	next, err := nextL1QueueIndex(api.eth.ChainDb(), block)
+	if err != nil {
+		return &newBlockResponse{false}, err
+	}
+	if uint64(params.NextL1QueueIndex) != next {
+		return &newBlockResponse{false}, fmt.Errorf("invalid next L1 queue index: have %d, want %d", params.NextL1QueueIndex, next)
+	}
+	// L1 messages included in the block are validated during the import
+	if _, err := bc.InsertChainWithoutSealVerification(block); err != nil {
+		return &newBlockResponse{false}, err
+	}
+	rawdb.WriteBlockRowConsumption(api.eth.ChainDb(), block.Hash(), params.RowConsumption)
+	for i, tx := range skipped {
+		bc.WriteSkippedTransaction(tx, nil, reasons[i], block.NumberU64(), nil)
+	}
+	return &newBlockResponse{true}, nil
+}
+
+// decodeSkippedTransactions decodes the L2 transactions skipped by the builder
+// of a block along with the reasons they were skipped for. The L1 messages are
+// left out: the ones skipped by the block are recorded when validating it, the
+// following ones are not consumed.
+func decodeSkippedTransactions(reports []skippedTransaction) ([]*types.Transaction, []string, error) {
+	var (
+		txs     []*types.Transaction
+		reasons []string
+	)
+	for i, report := range reports {
+		var tx types.Transaction
+		if err := tx.UnmarshalBinary(report.Transaction); err != nil {
+			return nil, nil, fmt.Errorf("invalid skipped transaction %d: %v", i, err)
+		}
+		if tx.Hash() != report.Hash {
+			return nil, nil, fmt.Errorf("skipped transaction %d hash mismatch: have %x, want %x", i, tx.Hash(), report.Hash)
+		}
+		if !tx.IsL1MessageTx() {
+			txs = append(txs, &tx)
+			reasons = append(reasons, report.Reason)
+		}
+	}
+	return txs, reasons, nil
+}
+
+// nextL1QueueIndex returns the first L1 queue index not processed by the given
+// block, following from the queue index of its parent and the L1 messages the
+// block includes.
+func nextL1QueueIndex(db ethdb.Reader, block *types.Block) (uint64, error) {
+	next := rawdb.ReadFirstQueueIndexNotInL2Block(db, block.ParentHash())
+	if next == nil {
+		return 0, fmt.Errorf("missing L1 queue index of parent %s", block.ParentHash())
+	}
+	return *next + uint64(block.NumL1MessagesProcessed(*next)), nil
+}
+
+// SetHeadV1 rewinds the canonical chain to the given block.
+func (api *scrollEngineAPI) SetHeadV1(hash common.Hash) (*genericResponse, error) {
+	bc := api.eth.BlockChain()
+	block := bc.GetBlockByHash(hash)
+	if block == nil {
+		return &genericResponse{false}, fmt.Errorf("unknown block %x", hash)
+	}
+	if bc.CurrentBlock().Hash() == hash {
+		return &genericResponse{true}, nil
+	}
+	if bc.GetCanonicalHash(block.NumberU64()) != hash {
+		return &genericResponse{false}, fmt.Errorf("block %x is not canonical", hash)
+	}
+	if err := bc.SetHead(block.NumberU64()); err != nil {
+		return &genericResponse{false}, err
+	}
+	return &genericResponse{true}, nil
+}
+
+func newL2ExecutableData(block *types.Block) *l2ExecutableData {
+	data := &l2ExecutableData{
+		BlockHash:    block.Hash(),
+		ParentHash:   block.ParentHash(),
+		Miner:        block.Coinbase(),
+		StateRoot:    block.Root(),
+		Number:       hexutil.Uint64(block.NumberU64()),
+		GasLimit:     hexutil.Uint64(block.GasLimit()),
+		GasUsed:      hexutil.Uint64(block.GasUsed()),
+		Timestamp:    hexutil.Uint64(block.Time()),
+		ReceiptRoot:  block.ReceiptHash(),
+		LogsBloom:    block.Bloom().Bytes(),
+		ExtraData:    block.Extra(),
+		Difficulty:   (*hexutil.Big)(block.Difficulty()),
+		MixDigest:    block.MixDigest(),
+		Nonce:        block.Header().Nonce,
+		Transactions: toHexBytes(encodeTransactions(block.Transactions())),
+	}
+	if block.BaseFee() != nil {
+		data.BaseFee = (*hexutil.Big)(block.BaseFee())
+	}
+	return data
+}
+
+func (data *l2ExecutableData) toBlock() (*types.Block, error) {
+	txs, err := decodeTransactions(fromHexBytes(data.Transactions))
+	if err != nil {
+		return nil, err
+	}
+	header := &types.Header{
+		ParentHash:  data.ParentHash,
+		UncleHash:   types.EmptyUncleHash,
+		Coinbase:    data.Miner,
+		Root:        data.StateRoot,
+		TxHash:      types.DeriveSha(types.Transactions(txs), trie.NewStackTrie(nil)),
+		ReceiptHash: data.ReceiptRoot,
+		Bloom:       types.BytesToBloom(data.LogsBloom),
+		Difficulty:  (*big.Int)(data.Difficulty),
+		Number:      new(big.Int).SetUint64(uint64(data.Number)),
+		GasLimit:    uint64(data.GasLimit),
+		GasUsed:     uint64(data.GasUsed),
+		Time:        uint64(data.Timestamp),
+		Extra:       data.ExtraData,
+		MixDigest:   data.MixDigest,
+		Nonce:       data.Nonce,
+		BaseFee:     (*big.Int)(data.BaseFee),
+	}
+	if header.Difficulty == nil {
+		header.Difficulty = new(big.Int)
+	}
+	return types.NewBlockWithHeader(header).WithBody(txs, nil /* uncles */), nil
+}
+
+func toHexBytes(enc [][]byte) []hexutil.Bytes {
+	var res = make([]hexutil.Bytes, len(enc))
+	for i, b := range enc {
+		res[i] = b
+	}
+	return res
+}
+
+func fromHexBytes(enc []hexutil.Bytes) [][]byte {
+	var res = make([][]byte, len(enc))
+	for i, b := range enc {
+		res[i] = b
+	}
+	return res
+}
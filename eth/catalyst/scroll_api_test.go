@@ -0,0 +1,242 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package catalyst
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/accounts"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/consensus/clique"
+	"github.com/scroll-tech/go-ethereum/core"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/params"
+)
+
+func TestScrollEngineAssembleAndImport(t *testing.T) {
+	genesis, blocks := generateTestChain()
+	config := *genesis.Config
+	config.Scroll.L1Config = &params.L1Config{NumL1MessagesPerBlock: 5}
+	genesis.Config = &config
+	n, ethservice := startEthService(t, genesis, blocks[1:9])
+	defer n.Close()
+
+	rawdb.WriteL1Messages(ethservice.ChainDb(), []types.L1MessageTx{
+		{QueueIndex: 0, Gas: 25000, To: &common.Address{1}, Value: big.NewInt(0), Sender: common.Address{2}},
+		{QueueIndex: 1, Gas: 25000, To: &common.Address{1}, Value: big.NewInt(0), Sender: common.Address{2}},
+	})
+
+	api := newScrollEngineAPI(ethservice)
+	signer := types.NewEIP155Signer(ethservice.BlockChain().Config().ChainID)
+	tx, err := types.SignTx(types.NewTransaction(0, blocks[8].Coinbase(), big.NewInt(1000), params.TxGas, big.NewInt(params.InitialBaseFee), nil), signer, testKey)
+	if err != nil {
+		t.Fatalf("error signing transaction, err=%v", err)
+	}
+	enc, _ := tx.MarshalBinary()
+	// A transaction with a nonce gap is skipped
+	gapped, err := types.SignTx(types.NewTransaction(5, blocks[8].Coinbase(), big.NewInt(1000), params.TxGas, big.NewInt(params.InitialBaseFee), nil), signer, testKey)
+	if err != nil {
+		t.Fatalf("error signing transaction, err=%v", err)
+	}
+	gappedEnc, _ := gapped.MarshalBinary()
+
+	parent := ethservice.BlockChain().CurrentBlock()
+	args := assembleL2BlockParams{
+		ParentHash:   parent.Hash(),
+		Timestamp:    hexutil.Uint64(parent.Time() + 5),
+		L1QueueStart: 0,
+		L1QueueEnd:   2,
+		Transactions: []hexutil.Bytes{enc, gappedEnc},
+	}
+	data, err := api.AssembleL2BlockV1(args)
+	if err != nil {
+		t.Fatalf("error assembling block, err=%v", err)
+	}
+	if len(data.Transactions) != 3 {
+		t.Fatalf("invalid number of transactions %d != 3", len(data.Transactions))
+	}
+	if data.NextL1QueueIndex != 2 {
+		t.Fatalf("invalid next L1 queue index %d != 2", data.NextL1QueueIndex)
+	}
+	if data.RowConsumption == nil {
+		t.Fatal("missing row consumption")
+	}
+	if len(data.SkippedTransactions) != 1 || data.SkippedTransactions[0].Hash != gapped.Hash() {
+		t.Fatalf("unexpected skipped transactions: %v", data.SkippedTransactions)
+	}
+
+	// Assembling does not change the chain
+	if head := ethservice.BlockChain().CurrentBlock().Hash(); head != parent.Hash() {
+		t.Fatalf("head changed during assembly: %x != %x", head, parent.Hash())
+	}
+	// L1 messages can't be skipped without being recorded in the block
+	skipping := *data
+	skipping.NextL1QueueIndex++
+	if resp, err := api.NewL2BlockV1(skipping); err == nil || resp.Valid {
+		t.Fatal("expected error for skipping L1 messages")
+	}
+	// The results of the block builder are stored on import
+	missingRows := *data
+	missingRows.RowConsumption = nil
+	if resp, err := api.NewL2BlockV1(missingRows); err == nil || resp.Valid {
+		t.Fatal("expected error for missing row consumption")
+	}
+	resp, err := api.NewL2BlockV1(*data)
+	if err != nil || !resp.Valid {
+		t.Fatalf("failed to import block: %v", err)
+	}
+	if rows := rawdb.ReadBlockRowConsumption(ethservice.ChainDb(), data.BlockHash); !reflect.DeepEqual(rows, data.RowConsumption) {
+		t.Fatalf("row consumption mismatch: have %v, want %v", rows, data.RowConsumption)
+	}
+	stx := rawdb.ReadSkippedTransaction(ethservice.ChainDb(), gapped.Hash())
+	if stx == nil || stx.Tx.Hash() != gapped.Hash() || stx.Reason != data.SkippedTransactions[0].Reason || stx.BlockNumber != uint64(data.Number) {
+		t.Fatalf("invalid skipped transaction record: %+v", stx)
+	}
+	if head := ethservice.BlockChain().CurrentBlock().Hash(); head != data.BlockHash {
+		t.Fatalf("head mismatch after import: %x != %x", head, data.BlockHash)
+	}
+	if index := rawdb.ReadFirstQueueIndexNotInL2Block(ethservice.ChainDb(), data.BlockHash); index == nil || *index != 2 {
+		t.Fatalf("invalid stored L1 queue index %v", index)
+	}
+
+	// Rewind to the parent again
+	if resp, err := api.SetHeadV1(parent.Hash()); err != nil || !resp.Success {
+		t.Fatalf("failed to set head: %v", err)
+	}
+	if head := ethservice.BlockChain().CurrentBlock().Hash(); head != parent.Hash() {
+		t.Fatalf("head mismatch after set head: %x != %x", head, parent.Hash())
+	}
+}
+
+func TestScrollEngineInvalidParams(t *testing.T) {
+	genesis, blocks := generateTestChain()
+	config := *genesis.Config
+	config.Scroll.L1Config = &params.L1Config{NumL1MessagesPerBlock: 5}
+	genesis.Config = &config
+	n, ethservice := startEthService(t, genesis, blocks[1:9])
+	defer n.Close()
+
+	api := newScrollEngineAPI(ethservice)
+	parent := ethservice.BlockChain().CurrentBlock()
+
+	// L1 messages must continue the parent's queue
+	_, err := api.AssembleL2BlockV1(assembleL2BlockParams{
+		ParentHash:   parent.Hash(),
+		Timestamp:    hexutil.Uint64(parent.Time() + 5),
+		L1QueueStart: 1,
+		L1QueueEnd:   2,
+	})
+	if err == nil {
+		t.Fatal("expected error for invalid L1 queue start")
+	}
+	// L1 messages must be known locally
+	_, err = api.AssembleL2BlockV1(assembleL2BlockParams{
+		ParentHash:   parent.Hash(),
+		Timestamp:    hexutil.Uint64(parent.Time() + 5),
+		L1QueueStart: 0,
+		L1QueueEnd:   1,
+	})
+	if err == nil {
+		t.Fatal("expected error for unknown L1 message")
+	}
+	// Blocks must match their hash
+	data := newL2ExecutableData(blocks[9])
+	data.BlockHash = common.Hash{1}
+	if resp, err := api.NewL2BlockV1(*data); err == nil || resp.Valid {
+		t.Fatal("expected error for block hash mismatch")
+	}
+}
+
+func TestScrollEngineAssembleClique(t *testing.T) {
+	config := *params.AllCliqueProtocolChanges
+	config.Clique = &params.CliqueConfig{Period: 3, Epoch: 30000}
+	config.Scroll.FeeVaultAddress = &common.Address{0xfe} // The signer is unknown while assembling
+	genesis := &core.Genesis{
+		Config:    &config,
+		Alloc:     core.GenesisAlloc{testAddr: {Balance: testBalance}},
+		ExtraData: make([]byte, 32+common.AddressLength+crypto.SignatureLength),
+		Timestamp: uint64(time.Now().Unix()) - 60,
+		BaseFee:   big.NewInt(params.InitialBaseFee),
+	}
+	copy(genesis.ExtraData[32:], testAddr[:])
+	n, ethservice := startEthService(t, genesis, nil)
+	defer n.Close()
+
+	engine := ethservice.Engine().(*clique.Clique)
+	engine.Authorize(testAddr, func(account accounts.Account, mimeType string, data []byte) ([]byte, error) {
+		return crypto.Sign(crypto.Keccak256(data), testKey)
+	})
+	api := newScrollEngineAPI(ethservice)
+	parent := ethservice.BlockChain().CurrentBlock()
+
+	signer := types.LatestSigner(&config)
+	tx, err := types.SignTx(types.NewTransaction(0, common.Address{1}, big.NewInt(1000), params.TxGas, big.NewInt(params.InitialBaseFee), nil), signer, testKey)
+	if err != nil {
+		t.Fatalf("error signing transaction, err=%v", err)
+	}
+	enc, _ := tx.MarshalBinary()
+
+	// Clique would move the timestamp to the present, the requested one is kept
+	timestamp := parent.Time() + config.Clique.Period
+	data, err := api.AssembleL2BlockV1(assembleL2BlockParams{
+		ParentHash:   parent.Hash(),
+		Timestamp:    hexutil.Uint64(timestamp),
+		Transactions: []hexutil.Bytes{enc},
+	})
+	if err != nil {
+		t.Fatalf("error assembling block, err=%v", err)
+	}
+	if uint64(data.Timestamp) != timestamp {
+		t.Fatalf("timestamp mismatch: have %d, want %d", data.Timestamp, timestamp)
+	}
+	// Timestamps clique would reject are refused
+	for _, invalid := range []uint64{parent.Time() + 1, uint64(time.Now().Unix()) + 60} {
+		if _, err := api.AssembleL2BlockV1(assembleL2BlockParams{ParentHash: parent.Hash(), Timestamp: hexutil.Uint64(invalid)}); err == nil {
+			t.Fatalf("expected error for timestamp %d", invalid)
+		}
+	}
+
+	// The block is sealed by the external sequencer before being imported
+	block, err := data.toBlock()
+	if err != nil {
+		t.Fatalf("failed to decode block: %v", err)
+	}
+	header := block.Header()
+	sig, err := crypto.Sign(clique.SealHash(header).Bytes(), testKey)
+	if err != nil {
+		t.Fatalf("failed to seal block: %v", err)
+	}
+	copy(header.Extra[len(header.Extra)-crypto.SignatureLength:], sig)
+	sealed := newL2ExecutableData(block.WithSeal(header))
+	sealed.NextL1QueueIndex = data.NextL1QueueIndex
+	sealed.RowConsumption = data.RowConsumption
+	sealed.SkippedTransactions = data.SkippedTransactions
+
+	if resp, err := api.NewL2BlockV1(*sealed); err != nil || !resp.Valid {
+		t.Fatalf("failed to import block: %v", err)
+	}
+	head := ethservice.BlockChain().CurrentBlock()
+	if head.Hash() != sealed.BlockHash || head.Time() != timestamp {
+		t.Fatalf("head mismatch after import: %x at %d, want %x at %d", head.Hash(), head.Time(), sealed.BlockHash, timestamp)
+	}
+}
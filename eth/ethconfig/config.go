@@ -136,7 +136,10 @@ type Config struct {
 	NoPruning  bool // Whether to disable pruning and flush everything to disk
 	NoPrefetch bool // Whether to disable prefetching and only load state on demand
 
-	TxLookupLimit uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
+	TxLookupLimit  uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
+	LogIndex       bool   `toml:",omitempty"` // Whether to maintain the address/topic index of the logs
+	AccountHistory bool   `toml:",omitempty"` // Whether to maintain the index of the transactions by account
+	BlockFees      bool   `toml:",omitempty"` // Whether to persist the fee accounting of every block
 
 	// Whitelist of required block number -> hash values to accept
 	Whitelist map[uint64]common.Hash `toml:"-"`
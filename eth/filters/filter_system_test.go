@@ -46,14 +46,16 @@ var (
 )
 
 type testBackend struct {
-	mux             *event.TypeMux
-	db              ethdb.Database
-	sections        uint64
-	txFeed          event.Feed
-	logsFeed        event.Feed
-	rmLogsFeed      event.Feed
-	pendingLogsFeed event.Feed
-	chainFeed       event.Feed
+	mux              *event.TypeMux
+	db               ethdb.Database
+	sections         uint64
+	logIndexSize     uint64
+	logIndexSections uint64
+	txFeed           event.Feed
+	logsFeed         event.Feed
+	rmLogsFeed       event.Feed
+	pendingLogsFeed  event.Feed
+	chainFeed        event.Feed
 }
 
 func (b *testBackend) ChainDb() ethdb.Database {
@@ -132,6 +134,10 @@ func (b *testBackend) BloomStatus() (uint64, uint64) {
 	return params.BloomBitsBlocks, b.sections
 }
 
+func (b *testBackend) LogIndexStatus() (uint64, uint64) {
+	return b.logIndexSize, b.logIndexSections
+}
+
 func (b *testBackend) ServiceFilter(ctx context.Context, session *bloombits.MatcherSession) {
 	requests := make(chan chan *bloombits.Retrieval)
 
@@ -227,3 +227,86 @@ func TestFilters(t *testing.T) {
 		t.Error("expected 0 log, got", len(logs))
 	}
 }
+
+func TestLogIndexFilters(t *testing.T) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr    = crypto.PubkeyToAddress(key1.PublicKey)
+		addr2   = common.HexToAddress("0x2")
+
+		hash1 = common.BytesToHash([]byte("topic1"))
+		hash2 = common.BytesToHash([]byte("topic2"))
+		hash3 = common.BytesToHash([]byte("topic3"))
+	)
+	genesis := core.GenesisBlockForTesting(db, addr, big.NewInt(1000000))
+	chain, receipts := core.GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), db, 1000, func(i int, gen *core.BlockGen) {
+		var logs []*types.Log
+		switch i {
+		case 1:
+			logs = []*types.Log{{Address: addr, Topics: []common.Hash{hash1}}}
+		case 2:
+			logs = []*types.Log{{Address: addr, Topics: []common.Hash{hash2, hash1}}}
+		case 300:
+			logs = []*types.Log{{Address: addr2, Topics: []common.Hash{hash1}}, {Address: addr, Topics: []common.Hash{hash3}}}
+		case 600, 900:
+			logs = []*types.Log{{Address: addr, Topics: []common.Hash{hash1, hash2}}}
+		default:
+			return
+		}
+		receipt := types.NewReceipt(nil, false, 0)
+		receipt.Logs = logs
+		gen.AddUncheckedReceipt(receipt)
+		gen.AddUncheckedTx(types.NewTransaction(uint64(i), common.HexToAddress("0x1"), big.NewInt(1), 1, gen.BaseFee(), nil))
+	})
+	for i, block := range chain {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+		rawdb.WriteHeadHeaderHash(db, block.Hash())
+		rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), receipts[i])
+	}
+	// Index the first three sections, leaving the rest of the chain unindexed
+	if err := core.RebuildLogIndex(context.Background(), db, 256, 200); err != nil {
+		t.Fatalf("failed to build log index: %v", err)
+	}
+	var (
+		unindexed = &testBackend{db: db}
+		indexed   = &testBackend{db: db, logIndexSize: 256, logIndexSections: 3}
+	)
+	tests := []struct {
+		begin, end int64
+		addresses  []common.Address
+		topics     [][]common.Hash
+		want       int
+	}{
+		{0, -1, []common.Address{addr}, nil, 5},
+		{0, -1, []common.Address{addr2}, nil, 1},
+		{0, -1, nil, [][]common.Hash{{hash1}}, 4},
+		{0, -1, nil, [][]common.Hash{nil, {hash1}}, 1},
+		{0, -1, []common.Address{addr2}, [][]common.Hash{nil, {hash3}}, 0},
+		{0, -1, []common.Address{addr}, [][]common.Hash{{hash1}, {hash2}}, 2},
+		{2, 600, []common.Address{addr, addr2}, [][]common.Hash{{hash1, hash2, hash3}}, 4},
+		{700, -1, nil, [][]common.Hash{{hash1}}, 1},
+		{0, -1, nil, [][]common.Hash{nil, nil, nil, nil, {hash1}}, 0},
+	}
+	for i, tt := range tests {
+		want, err := NewRangeFilter(unindexed, tt.begin, tt.end, tt.addresses, tt.topics).Logs(context.Background())
+		if err != nil {
+			t.Fatalf("test %d: unindexed filter failed: %v", i, err)
+		}
+		have, err := NewRangeFilter(indexed, tt.begin, tt.end, tt.addresses, tt.topics).Logs(context.Background())
+		if err != nil {
+			t.Fatalf("test %d: indexed filter failed: %v", i, err)
+		}
+		if len(have) != tt.want || len(want) != tt.want {
+			t.Errorf("test %d: log count mismatch: indexed %d, unindexed %d, want %d", i, len(have), len(want), tt.want)
+			continue
+		}
+		for j := range have {
+			if have[j].BlockNumber != want[j].BlockNumber || have[j].Address != want[j].Address {
+				t.Errorf("test %d: log %d mismatch: have %v, want %v", i, j, have[j], want[j])
+			}
+		}
+	}
+}
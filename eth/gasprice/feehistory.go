@@ -28,9 +28,11 @@ import (
 
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/consensus/misc"
+	"github.com/scroll-tech/go-ethereum/core/state"
 	"github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/scroll-tech/go-ethereum/rollup/fees"
+	"github.com/scroll-tech/go-ethereum/rollup/rcfg"
 	"github.com/scroll-tech/go-ethereum/rpc"
 )
 
@@ -62,6 +64,33 @@ type processedFees struct {
 	reward               []*big.Int
 	baseFee, nextBaseFee *big.Int
 	gasUsedRatio         float64
+	l1Fees               *L1Fees // only set if L1 fees are requested
+}
+
+// L1Fees contains the L1GasPriceOracle values in the post state of a block,
+// which determine the L1 data fee of the transactions in the next block.
+type L1Fees struct {
+	L1BaseFee     *big.Int
+	L1BlobBaseFee *big.Int
+	Overhead      *big.Int
+	Scalar        *big.Int
+	CommitScalar  *big.Int
+	BlobScalar    *big.Int
+}
+
+// readL1Fees reads the L1GasPriceOracle values from the given state.
+func readL1Fees(state *state.StateDB) *L1Fees {
+	slot := func(key common.Hash) *big.Int {
+		return state.GetState(rcfg.L1GasPriceOracleAddress, key).Big()
+	}
+	return &L1Fees{
+		L1BaseFee:     slot(rcfg.L1BaseFeeSlot),
+		L1BlobBaseFee: slot(rcfg.L1BlobBaseFeeSlot),
+		Overhead:      slot(rcfg.OverheadSlot),
+		Scalar:        slot(rcfg.ScalarSlot),
+		CommitScalar:  slot(rcfg.CommitScalarSlot),
+		BlobScalar:    slot(rcfg.BlobScalarSlot),
+	}
 }
 
 // txGasAndReward is sorted in ascending order based on reward
@@ -83,22 +112,32 @@ func (s sortGasAndReward) Less(i, j int) bool {
 
 // processBlock takes a blockFees structure with the blockNumber, the header and optionally
 // the block field filled in, retrieves the block from the backend if not present yet and
-// fills in the rest of the fields.
-func (oracle *Oracle) processBlock(bf *blockFees, percentiles []float64) {
+// fills in the rest of the fields. If withL1Fees is set, the L1GasPriceOracle values
+// of the block's post state are collected as well.
+func (oracle *Oracle) processBlock(bf *blockFees, percentiles []float64, withL1Fees bool) {
 	chainconfig := oracle.backend.ChainConfig()
 	if bf.results.baseFee = bf.header.BaseFee; bf.results.baseFee == nil {
 		bf.results.baseFee = new(big.Int)
 	}
-	if chainconfig.IsCurie(big.NewInt(int64(bf.blockNumber + 1))) {
+	isCurie := chainconfig.IsCurie(big.NewInt(int64(bf.blockNumber + 1)))
+	if !isCurie {
+		bf.results.nextBaseFee = new(big.Int)
+	}
+	if isCurie || withL1Fees {
+		// Only the next base fee and the L1 fees depend on the state, the other
+		// results are still filled in if it is not available.
 		state, err := oracle.backend.StateAt(bf.header.Root)
 		if err != nil || state == nil {
 			log.Error("State not found", "number", bf.header.Number, "hash", bf.header.Hash().Hex(), "state", state, "err", err)
-			return
+		} else {
+			if withL1Fees {
+				bf.results.l1Fees = readL1Fees(state)
+			}
+			if isCurie {
+				l1BaseFee := fees.GetL1BaseFee(state)
+				bf.results.nextBaseFee = misc.CalcBaseFee(chainconfig, bf.header, l1BaseFee)
+			}
 		}
-		l1BaseFee := fees.GetL1BaseFee(state)
-		bf.results.nextBaseFee = misc.CalcBaseFee(chainconfig, bf.header, l1BaseFee)
-	} else {
-		bf.results.nextBaseFee = new(big.Int)
 	}
 	bf.results.gasUsedRatio = float64(bf.header.GasUsed) / float64(bf.header.GasLimit)
 	if len(percentiles) == 0 {
@@ -206,8 +245,20 @@ func (oracle *Oracle) resolveBlockRange(ctx context.Context, lastBlock rpc.Block
 // Note: baseFee includes the next block after the newest of the returned range, because this
 // value can be derived from the newest block.
 func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLastBlock rpc.BlockNumber, rewardPercentiles []float64) (*big.Int, [][]*big.Int, []*big.Int, []float64, error) {
+	oldest, reward, baseFee, gasUsedRatio, _, err := oracle.feeHistory(ctx, blocks, unresolvedLastBlock, rewardPercentiles, false)
+	return oldest, reward, baseFee, gasUsedRatio, err
+}
+
+// L1FeeHistory is like FeeHistory, but additionally returns the L1GasPriceOracle
+// values (L1 base fee, L1 blob base fee and scalars) in the post state of each
+// processed block. An entry is nil if the state of the block is not available.
+func (oracle *Oracle) L1FeeHistory(ctx context.Context, blocks int, unresolvedLastBlock rpc.BlockNumber, rewardPercentiles []float64) (*big.Int, [][]*big.Int, []*big.Int, []float64, []*L1Fees, error) {
+	return oracle.feeHistory(ctx, blocks, unresolvedLastBlock, rewardPercentiles, true)
+}
+
+func (oracle *Oracle) feeHistory(ctx context.Context, blocks int, unresolvedLastBlock rpc.BlockNumber, rewardPercentiles []float64, withL1Fees bool) (*big.Int, [][]*big.Int, []*big.Int, []float64, []*L1Fees, error) {
 	if blocks < 1 {
-		return common.Big0, nil, nil, nil, nil // returning with no data and no error means there are no retrievable blocks
+		return common.Big0, nil, nil, nil, nil, nil // returning with no data and no error means there are no retrievable blocks
 	}
 	maxFeeHistory := oracle.maxHeaderHistory
 	if len(rewardPercentiles) != 0 {
@@ -219,10 +270,10 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLast
 	}
 	for i, p := range rewardPercentiles {
 		if p < 0 || p > 100 {
-			return common.Big0, nil, nil, nil, fmt.Errorf("%w: %f", errInvalidPercentile, p)
+			return common.Big0, nil, nil, nil, nil, fmt.Errorf("%w: %f", errInvalidPercentile, p)
 		}
 		if i > 0 && p < rewardPercentiles[i-1] {
-			return common.Big0, nil, nil, nil, fmt.Errorf("%w: #%d:%f > #%d:%f", errInvalidPercentile, i-1, rewardPercentiles[i-1], i, p)
+			return common.Big0, nil, nil, nil, nil, fmt.Errorf("%w: #%d:%f > #%d:%f", errInvalidPercentile, i-1, rewardPercentiles[i-1], i, p)
 		}
 	}
 	var (
@@ -232,7 +283,7 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLast
 	)
 	pendingBlock, pendingReceipts, lastBlock, blocks, err := oracle.resolveBlockRange(ctx, unresolvedLastBlock, blocks)
 	if err != nil || blocks == 0 {
-		return common.Big0, nil, nil, nil, err
+		return common.Big0, nil, nil, nil, nil, err
 	}
 	oldestBlock := lastBlock + 1 - uint64(blocks)
 
@@ -257,13 +308,14 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLast
 				if pendingBlock != nil && blockNumber >= pendingBlock.NumberU64() {
 					fees.block, fees.receipts = pendingBlock, pendingReceipts
 					fees.header = fees.block.Header()
-					oracle.processBlock(fees, rewardPercentiles)
+					oracle.processBlock(fees, rewardPercentiles, withL1Fees)
 					results <- fees
 				} else {
 					cacheKey := struct {
 						number      uint64
 						percentiles string
-					}{blockNumber, string(percentileKey)}
+						l1Fees      bool
+					}{blockNumber, string(percentileKey), withL1Fees}
 
 					if p, ok := oracle.historyCache.Get(cacheKey); ok {
 						fees.results = p.(processedFees)
@@ -279,7 +331,7 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLast
 							fees.header, fees.err = oracle.backend.HeaderByNumber(ctx, rpc.BlockNumber(blockNumber))
 						}
 						if fees.header != nil && fees.err == nil {
-							oracle.processBlock(fees, rewardPercentiles)
+							oracle.processBlock(fees, rewardPercentiles, withL1Fees)
 							if fees.err == nil {
 								oracle.historyCache.Add(cacheKey, fees.results)
 							}
@@ -295,16 +347,22 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLast
 		reward       = make([][]*big.Int, blocks)
 		baseFee      = make([]*big.Int, blocks+1)
 		gasUsedRatio = make([]float64, blocks)
+		l1Fees       = make([]*L1Fees, blocks)
 		firstMissing = blocks
 	)
 	for ; blocks > 0; blocks-- {
 		fees := <-results
 		if fees.err != nil {
-			return common.Big0, nil, nil, nil, fees.err
+			return common.Big0, nil, nil, nil, nil, fees.err
 		}
 		i := int(fees.blockNumber - oldestBlock)
 		if fees.results.baseFee != nil {
-			reward[i], baseFee[i], baseFee[i+1], gasUsedRatio[i] = fees.results.reward, fees.results.baseFee, fees.results.nextBaseFee, fees.results.gasUsedRatio
+			reward[i], baseFee[i], gasUsedRatio[i] = fees.results.reward, fees.results.baseFee, fees.results.gasUsedRatio
+			// The next base fee is unknown without the state, keep the one of the next block
+			if fees.results.nextBaseFee != nil {
+				baseFee[i+1] = fees.results.nextBaseFee
+			}
+			l1Fees[i] = fees.results.l1Fees
 		} else {
 			// getting no block and no error means we are requesting into the future (might happen because of a reorg)
 			if i < firstMissing {
@@ -313,13 +371,18 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLast
 		}
 	}
 	if firstMissing == 0 {
-		return common.Big0, nil, nil, nil, nil
+		return common.Big0, nil, nil, nil, nil, nil
 	}
 	if len(rewardPercentiles) != 0 {
 		reward = reward[:firstMissing]
 	} else {
 		reward = nil
 	}
+	if withL1Fees {
+		l1Fees = l1Fees[:firstMissing]
+	} else {
+		l1Fees = nil
+	}
 	baseFee, gasUsedRatio = baseFee[:firstMissing+1], gasUsedRatio[:firstMissing]
-	return new(big.Int).SetUint64(oldestBlock), reward, baseFee, gasUsedRatio, nil
+	return new(big.Int).SetUint64(oldestBlock), reward, baseFee, gasUsedRatio, l1Fees, nil
 }
@@ -22,6 +22,8 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/rollup/rcfg"
 	"github.com/scroll-tech/go-ethereum/rpc"
 )
 
@@ -87,3 +89,67 @@ func TestFeeHistory(t *testing.T) {
 		}
 	}
 }
+
+func TestL1FeeHistory(t *testing.T) {
+	backend := newL1FeeTestBackend(t, big.NewInt(16))
+	oracle := NewOracle(backend, Config{MaxHeaderHistory: 1000, MaxBlockHistory: 1000})
+
+	first, _, baseFee, _, l1Fees, err := oracle.L1FeeHistory(context.Background(), 10, 30, nil)
+	if err != nil {
+		t.Fatalf("failed to retrieve L1 fee history: %v", err)
+	}
+	if first.Uint64() != 21 {
+		t.Fatalf("first block mismatch, want %d, got %d", 21, first)
+	}
+	if len(l1Fees) != 10 || len(baseFee) != 11 {
+		t.Fatalf("array length mismatch, want %d L1 fees and %d base fees, got %d and %d", 10, 11, len(l1Fees), len(baseFee))
+	}
+	for i, fees := range l1Fees {
+		if fees == nil {
+			t.Fatalf("missing L1 fees for block %d", i)
+		}
+		if fees.L1BaseFee.Cmp(big.NewInt(testL1BaseFee)) != 0 {
+			t.Errorf("block %d: L1 base fee mismatch, want %v, got %v", i, testL1BaseFee, fees.L1BaseFee)
+		}
+		// the Curie fork initializes the blob fee fields of the oracle
+		if fees.L1BlobBaseFee.Cmp(common.Big1) != 0 {
+			t.Errorf("block %d: L1 blob base fee mismatch, want %v, got %v", i, 1, fees.L1BlobBaseFee)
+		}
+		if fees.CommitScalar.Cmp(rcfg.InitialCommitScalar) != 0 {
+			t.Errorf("block %d: commit scalar mismatch, want %v, got %v", i, rcfg.InitialCommitScalar, fees.CommitScalar)
+		}
+	}
+
+	// Plain fee history must not carry L1 fees.
+	if _, _, _, _, l1Fees, _ := oracle.feeHistory(context.Background(), 10, 30, nil, false); l1Fees != nil {
+		t.Fatalf("unexpected L1 fees in plain fee history")
+	}
+}
+
+func TestL1FeeHistoryWithoutState(t *testing.T) {
+	backend := newTestBackend(t, big.NewInt(16), false)
+	oracle := NewOracle(backend, Config{MaxHeaderHistory: 1000, MaxBlockHistory: 1000})
+
+	// The results not depending on the state are still returned
+	first, reward, _, ratio, l1Fees, err := oracle.L1FeeHistory(context.Background(), 10, 30, []float64{0, 10})
+	if err != nil {
+		t.Fatalf("failed to retrieve L1 fee history: %v", err)
+	}
+	if first.Uint64() != 21 {
+		t.Fatalf("first block mismatch, want %d, got %d", 21, first)
+	}
+	if len(reward) != 10 || len(ratio) != 10 || len(l1Fees) != 10 {
+		t.Fatalf("array length mismatch, want %d, got %d rewards, %d ratios and %d L1 fees", 10, len(reward), len(ratio), len(l1Fees))
+	}
+	for i := range l1Fees {
+		if l1Fees[i] != nil {
+			t.Errorf("block %d: unexpected L1 fees without state", i)
+		}
+		if reward[i] == nil || reward[i][1].Sign() == 0 {
+			t.Errorf("block %d: missing rewards", i)
+		}
+		if ratio[i] == 0 {
+			t.Errorf("block %d: missing gas used ratio", i)
+		}
+	}
+}
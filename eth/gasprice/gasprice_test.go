@@ -32,14 +32,19 @@ import (
 	"github.com/scroll-tech/go-ethereum/crypto"
 	"github.com/scroll-tech/go-ethereum/event"
 	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rollup/rcfg"
 	"github.com/scroll-tech/go-ethereum/rpc"
 )
 
-const testHead = 32
+const (
+	testHead      = 32
+	testL1BaseFee = 10 * params.GWei
+)
 
 type testBackend struct {
 	chain   *core.BlockChain
 	pending bool // pending block available
+	states  bool // post states of the blocks available
 }
 
 func (b *testBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
@@ -97,6 +102,26 @@ func (b *testBackend) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) eve
 }
 
 func newTestBackend(t *testing.T, londonBlock *big.Int, pending bool) *testBackend {
+	return &testBackend{chain: newTestChain(t, londonBlock, nil), pending: pending}
+}
+
+// newL1FeeTestBackend creates a test backend serving the post states of the
+// blocks, with the L1 base fee set in the L1GasPriceOracle.
+func newL1FeeTestBackend(t *testing.T, londonBlock *big.Int) *testBackend {
+	alloc := core.GenesisAlloc{
+		rcfg.L1GasPriceOracleAddress: {
+			Balance: big.NewInt(0),
+			Storage: map[common.Hash]common.Hash{
+				rcfg.L1BaseFeeSlot: common.BigToHash(big.NewInt(testL1BaseFee)),
+			},
+		},
+	}
+	return &testBackend{chain: newTestChain(t, londonBlock, alloc), states: true}
+}
+
+// newTestChain creates a chain of testHead+1 blocks, funding the sender of their
+// transactions along with the given accounts in the genesis.
+func newTestChain(t *testing.T, londonBlock *big.Int, alloc core.GenesisAlloc) *core.BlockChain {
 	var (
 		key, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
 		addr   = crypto.PubkeyToAddress(key.PublicKey)
@@ -107,12 +132,16 @@ func newTestBackend(t *testing.T, londonBlock *big.Int, pending bool) *testBacke
 		}
 		signer = types.LatestSigner(gspec.Config)
 	)
+	for address, account := range alloc {
+		gspec.Alloc[address] = account
+	}
 	config.LondonBlock = londonBlock
 	config.ArrowGlacierBlock = londonBlock
 	config.ArchimedesBlock = londonBlock
 	config.ShanghaiBlock = londonBlock
 	config.BernoulliBlock = londonBlock
 	config.CurieBlock = londonBlock
+	config.DescartesBlock = londonBlock
 	engine := ethash.NewFaker()
 	db := rawdb.NewMemoryDatabase()
 	genesis, err := gspec.Commit(db)
@@ -154,7 +183,7 @@ func newTestBackend(t *testing.T, londonBlock *big.Int, pending bool) *testBacke
 		t.Fatalf("Failed to create local chain, %v", err)
 	}
 	chain.InsertChain(blocks)
-	return &testBackend{chain: chain, pending: pending}
+	return chain
 }
 
 func (b *testBackend) CurrentHeader() *types.Header {
@@ -166,7 +195,10 @@ func (b *testBackend) GetBlockByNumber(number uint64) *types.Block {
 }
 
 func (b *testBackend) StateAt(root common.Hash) (*state.StateDB, error) {
-	return nil, nil
+	if !b.states {
+		return nil, nil
+	}
+	return b.chain.StateAt(root)
 }
 
 func TestSuggestTipCap(t *testing.T) {
@@ -0,0 +1,304 @@
+package tracers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+const (
+	// flatCallTracer is the native tracer producing Parity-style traces.
+	flatCallTracer = "flatCallTracer"
+
+	// maxTraceFilterBlocks is the maximum number of blocks a trace_filter
+	// request may span, as every block in the range is re-executed.
+	maxTraceFilterBlocks = 1000
+)
+
+// TraceAPI is the collection of Parity-style tracing APIs exposed over the
+// trace namespace. The traces are produced by the native flatCallTracer.
+type TraceAPI struct {
+	api *API
+}
+
+// NewTraceAPI creates a new API definition for the Parity-style tracing methods.
+func NewTraceAPI(api *API) *TraceAPI {
+	return &TraceAPI{api: api}
+}
+
+// flatTrace is the part of a flat call trace inspected by the trace APIs.
+type flatTrace struct {
+	Action struct {
+		From          *common.Address `json:"from"`
+		To            *common.Address `json:"to"`
+		Address       *common.Address `json:"address"`
+		RefundAddress *common.Address `json:"refundAddress"`
+	} `json:"action"`
+	Result *struct {
+		Address *common.Address `json:"address"`
+		Code    hexutil.Bytes   `json:"code"`
+		Output  hexutil.Bytes   `json:"output"`
+	} `json:"result"`
+}
+
+// from returns the account initiating the traced call.
+func (t *flatTrace) from() *common.Address {
+	if t.Action.From != nil {
+		return t.Action.From
+	}
+	return t.Action.Address // self-destructs
+}
+
+// to returns the account receiving the traced call.
+func (t *flatTrace) to() *common.Address {
+	switch {
+	case t.Action.To != nil:
+		return t.Action.To
+	case t.Action.RefundAddress != nil:
+		return t.Action.RefundAddress // self-destructs
+	case t.Result != nil:
+		return t.Result.Address // creations
+	}
+	return nil
+}
+
+// traceConfig returns the config running the flat call tracer.
+func (api *TraceAPI) traceConfig() *TraceConfig {
+	tracer := flatCallTracer
+	return &TraceConfig{Tracer: &tracer}
+}
+
+// blockTraces returns the flat call traces of all transactions in the block,
+// one list per transaction.
+func (api *TraceAPI) blockTraces(ctx context.Context, block *types.Block) ([][]json.RawMessage, error) {
+	// There are no block rewards in Scroll, so the genesis has no traces
+	if block.NumberU64() == 0 {
+		return nil, nil
+	}
+	results, err := api.api.traceBlock(ctx, block, api.traceConfig())
+	if err != nil {
+		return nil, err
+	}
+	traces := make([][]json.RawMessage, len(results))
+	for i, result := range results {
+		if result.Error != "" {
+			return nil, fmt.Errorf("failed to trace transaction %d: %s", i, result.Error)
+		}
+		if err := decodeTraces(result.Result, &traces[i]); err != nil {
+			return nil, err
+		}
+	}
+	return traces, nil
+}
+
+// decodeTraces decodes the result of the flat call tracer.
+func decodeTraces(result interface{}, traces *[]json.RawMessage) error {
+	raw, ok := result.(json.RawMessage)
+	if !ok {
+		return fmt.Errorf("unexpected trace result type %T", result)
+	}
+	return json.Unmarshal(raw, traces)
+}
+
+// Block returns the flat call traces of all transactions in the given block.
+func (api *TraceAPI) Block(ctx context.Context, number rpc.BlockNumber) ([]json.RawMessage, error) {
+	block, err := api.api.blockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	traces, err := api.blockTraces(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+	flat := []json.RawMessage{}
+	for _, txTraces := range traces {
+		flat = append(flat, txTraces...)
+	}
+	return flat, nil
+}
+
+// Transaction returns the flat call traces of the given transaction.
+func (api *TraceAPI) Transaction(ctx context.Context, hash common.Hash) ([]json.RawMessage, error) {
+	result, err := api.api.TraceTransaction(ctx, hash, api.traceConfig())
+	if err != nil {
+		return nil, err
+	}
+	var traces []json.RawMessage
+	if err := decodeTraces(result, &traces); err != nil {
+		return nil, err
+	}
+	return traces, nil
+}
+
+// replayResult is the result of replaying a single transaction.
+type replayResult struct {
+	Output          hexutil.Bytes     `json:"output"`
+	StateDiff       interface{}       `json:"stateDiff"`
+	Trace           []json.RawMessage `json:"trace"`
+	VmTrace         interface{}       `json:"vmTrace"`
+	TransactionHash common.Hash       `json:"transactionHash"`
+}
+
+// ReplayBlockTransactions replays all transactions in the given block and
+// returns the requested traces of each. Only the "trace" type is supported.
+func (api *TraceAPI) ReplayBlockTransactions(ctx context.Context, number rpc.BlockNumber, traceTypes []string) ([]*replayResult, error) {
+	var withTrace bool
+	for _, typ := range traceTypes {
+		switch typ {
+		case "trace":
+			withTrace = true
+		case "vmTrace", "stateDiff":
+			return nil, fmt.Errorf("trace type %q is not supported", typ)
+		default:
+			return nil, fmt.Errorf("invalid trace type %q", typ)
+		}
+	}
+	block, err := api.api.blockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	traces, err := api.blockTraces(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*replayResult, len(traces))
+	for i, txTraces := range traces {
+		result := &replayResult{
+			Trace:           []json.RawMessage{},
+			TransactionHash: block.Transactions()[i].Hash(),
+		}
+		// The output of the transaction is the one of the top-level call
+		if len(txTraces) > 0 {
+			var top flatTrace
+			if err := json.Unmarshal(txTraces[0], &top); err != nil {
+				return nil, err
+			}
+			if top.Result != nil {
+				result.Output = top.Result.Output
+				if top.Result.Code != nil {
+					result.Output = top.Result.Code
+				}
+			}
+		}
+		if result.Output == nil {
+			result.Output = hexutil.Bytes{}
+		}
+		if withTrace {
+			result.Trace = txTraces
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+// TraceFilterArgs represents the arguments of a trace_filter request.
+type TraceFilterArgs struct {
+	FromBlock   *rpc.BlockNumber `json:"fromBlock"`
+	ToBlock     *rpc.BlockNumber `json:"toBlock"`
+	FromAddress []common.Address `json:"fromAddress"`
+	ToAddress   []common.Address `json:"toAddress"`
+	After       *uint64          `json:"after"`
+	Count       *uint64          `json:"count"`
+}
+
+// resolveBlockNumber returns the number of the given block, defaulting to
+// the latest block.
+func (api *TraceAPI) resolveBlockNumber(ctx context.Context, number *rpc.BlockNumber) (uint64, error) {
+	if number == nil {
+		latest := rpc.LatestBlockNumber
+		number = &latest
+	}
+	header, err := api.api.backend.HeaderByNumber(ctx, *number)
+	if err != nil {
+		return 0, err
+	}
+	if header == nil {
+		return 0, fmt.Errorf("block #%d not found", *number)
+	}
+	return header.Number.Uint64(), nil
+}
+
+// containsAddress reports whether the address is in the list, an empty list
+// matching any address.
+func containsAddress(addresses []common.Address, addr *common.Address) bool {
+	if len(addresses) == 0 {
+		return true
+	}
+	if addr == nil {
+		return false
+	}
+	for _, a := range addresses {
+		if a == *addr {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the flat call traces in the given block range matching the
+// from and to addresses. Traces must match both address lists; an empty list
+// matches any address. The after and count arguments paginate the results.
+func (api *TraceAPI) Filter(ctx context.Context, args TraceFilterArgs) ([]json.RawMessage, error) {
+	from, err := api.resolveBlockNumber(ctx, args.FromBlock)
+	if err != nil {
+		return nil, err
+	}
+	to, err := api.resolveBlockNumber(ctx, args.ToBlock)
+	if err != nil {
+		return nil, err
+	}
+	if from > to {
+		return nil, errors.New("fromBlock is greater than toBlock")
+	}
+	if to-from >= maxTraceFilterBlocks {
+		return nil, fmt.Errorf("block range of %d exceeds the limit of %d blocks", to-from+1, maxTraceFilterBlocks)
+	}
+	if args.Count != nil && *args.Count == 0 {
+		return []json.RawMessage{}, nil
+	}
+	var skip uint64
+	if args.After != nil {
+		skip = *args.After
+	}
+
+	matches := []json.RawMessage{}
+	for number := from; number <= to; number++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		block, err := api.api.blockByNumber(ctx, rpc.BlockNumber(number))
+		if err != nil {
+			return nil, err
+		}
+		traces, err := api.blockTraces(ctx, block)
+		if err != nil {
+			return nil, err
+		}
+		for _, txTraces := range traces {
+			for _, raw := range txTraces {
+				var trace flatTrace
+				if err := json.Unmarshal(raw, &trace); err != nil {
+					return nil, err
+				}
+				if !containsAddress(args.FromAddress, trace.from()) || !containsAddress(args.ToAddress, trace.to()) {
+					continue
+				}
+				if skip > 0 {
+					skip--
+					continue
+				}
+				matches = append(matches, raw)
+				if args.Count != nil && uint64(len(matches)) >= *args.Count {
+					return matches, nil
+				}
+			}
+		}
+	}
+	return matches, nil
+}
@@ -0,0 +1,167 @@
+package tracers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/core/vm"
+	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+// testFlatCallTracer stands in for the native flatCallTracer, which cannot be
+// imported here, reporting the top-level call of a transaction only.
+type testFlatCallTracer struct {
+	ctx      *Context
+	number   uint64
+	from, to common.Address
+}
+
+func init() {
+	RegisterLookup(false, func(name string, ctx *Context, cfg json.RawMessage) (Tracer, error) {
+		if name != flatCallTracer {
+			return nil, errors.New("no tracer found")
+		}
+		return &testFlatCallTracer{ctx: ctx}, nil
+	})
+}
+
+func (t *testFlatCallTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.number, t.from, t.to = env.Context.BlockNumber.Uint64(), from, to
+}
+func (t *testFlatCallTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+func (t *testFlatCallTracer) CaptureStateAfter(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+func (t *testFlatCallTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+}
+func (t *testFlatCallTracer) CaptureExit(output []byte, gasUsed uint64, err error) {}
+func (t *testFlatCallTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+func (t *testFlatCallTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) {}
+func (t *testFlatCallTracer) CaptureTxStart(gasLimit uint64)                                       {}
+func (t *testFlatCallTracer) CaptureTxEnd(restGas uint64)                                          {}
+func (t *testFlatCallTracer) Stop(err error)                                                       {}
+
+func (t *testFlatCallTracer) GetResult() (json.RawMessage, error) {
+	return json.RawMessage(fmt.Sprintf(`[{"action":{"callType":"call","from":"%v","to":"%v"},"blockNumber":%d,"result":{"output":"0x01"},"subtraces":0,"traceAddress":[],"transactionHash":"%v","type":"call"}]`,
+		t.from.Hex(), t.to.Hex(), t.number, t.ctx.TxHash.Hex())), nil
+}
+
+func TestTraceAPI(t *testing.T) {
+	t.Parallel()
+
+	// Initialize test accounts, transferring to the second and third in turn
+	accounts := newAccounts(3)
+	genesis := &core.Genesis{Alloc: core.GenesisAlloc{
+		accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+	}}
+	var hashes []common.Hash
+	signer := types.HomesteadSigner{}
+	api := NewTraceAPI(NewAPI(newTestBackend(t, 4, genesis, func(i int, b *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(uint64(i), accounts[1+i%2].addr, big.NewInt(1000), params.TxGas, b.BaseFee(), nil), signer, accounts[0].key)
+		b.AddTx(tx)
+		hashes = append(hashes, tx.Hash())
+	}), nil))
+
+	type trace struct {
+		Action struct {
+			From common.Address `json:"from"`
+			To   common.Address `json:"to"`
+		} `json:"action"`
+		BlockNumber     uint64      `json:"blockNumber"`
+		TransactionHash common.Hash `json:"transactionHash"`
+	}
+	decode := func(raw []json.RawMessage) []trace {
+		traces := make([]trace, len(raw))
+		for i := range raw {
+			if err := json.Unmarshal(raw[i], &traces[i]); err != nil {
+				t.Fatalf("failed to decode trace: %v", err)
+			}
+		}
+		return traces
+	}
+
+	// Blocks and transactions
+	raw, err := api.Block(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("failed to trace block: %v", err)
+	}
+	if traces := decode(raw); len(traces) != 1 || traces[0].BlockNumber != 2 || traces[0].TransactionHash != hashes[1] || traces[0].Action.To != accounts[2].addr {
+		t.Errorf("unexpected block traces: %s", raw)
+	}
+	if raw, err = api.Block(context.Background(), 0); err != nil || len(raw) != 0 {
+		t.Errorf("unexpected genesis traces: %s, %v", raw, err)
+	}
+	raw, err = api.Transaction(context.Background(), hashes[2])
+	if err != nil {
+		t.Fatalf("failed to trace transaction: %v", err)
+	}
+	if traces := decode(raw); len(traces) != 1 || traces[0].BlockNumber != 3 || traces[0].Action.From != accounts[0].addr {
+		t.Errorf("unexpected transaction traces: %s", raw)
+	}
+
+	// Replays
+	replays, err := api.ReplayBlockTransactions(context.Background(), 1, []string{"trace"})
+	if err != nil {
+		t.Fatalf("failed to replay block: %v", err)
+	}
+	if len(replays) != 1 || len(replays[0].Trace) != 1 || replays[0].TransactionHash != hashes[0] || replays[0].Output.String() != "0x01" {
+		t.Errorf("unexpected replay: %+v", replays)
+	}
+	if replays, err = api.ReplayBlockTransactions(context.Background(), 1, nil); err != nil || len(replays[0].Trace) != 0 {
+		t.Errorf("unexpected replay without traces: %+v, %v", replays, err)
+	}
+	if _, err = api.ReplayBlockTransactions(context.Background(), 1, []string{"vmTrace"}); err == nil {
+		t.Error("expected unsupported trace type to fail")
+	}
+
+	// Filters
+	blockNumber := func(n int64) *rpc.BlockNumber {
+		number := rpc.BlockNumber(n)
+		return &number
+	}
+	uint64Ptr := func(n uint64) *uint64 { return &n }
+	for i, tt := range []struct {
+		args   TraceFilterArgs
+		blocks []uint64
+		fail   bool
+	}{
+		{args: TraceFilterArgs{FromBlock: blockNumber(0)}, blocks: []uint64{1, 2, 3, 4}},
+		{args: TraceFilterArgs{FromBlock: blockNumber(2), ToBlock: blockNumber(3)}, blocks: []uint64{2, 3}},
+		{args: TraceFilterArgs{FromBlock: blockNumber(0), ToAddress: []common.Address{accounts[2].addr}}, blocks: []uint64{2, 4}},
+		{args: TraceFilterArgs{FromBlock: blockNumber(0), FromAddress: []common.Address{accounts[1].addr}}, blocks: []uint64{}},
+		{args: TraceFilterArgs{FromBlock: blockNumber(0), FromAddress: []common.Address{accounts[0].addr}, ToAddress: []common.Address{accounts[1].addr}}, blocks: []uint64{1, 3}},
+		{args: TraceFilterArgs{FromBlock: blockNumber(0), After: uint64Ptr(1), Count: uint64Ptr(2)}, blocks: []uint64{2, 3}},
+		{args: TraceFilterArgs{FromBlock: blockNumber(0), Count: uint64Ptr(0)}, blocks: []uint64{}},
+		{args: TraceFilterArgs{FromBlock: blockNumber(3), ToBlock: blockNumber(2)}, fail: true},
+		{args: TraceFilterArgs{FromBlock: blockNumber(10)}, fail: true},
+	} {
+		raw, err := api.Filter(context.Background(), tt.args)
+		if tt.fail {
+			if err == nil {
+				t.Errorf("test %d: expected failure", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %d: failed to filter traces: %v", i, err)
+			continue
+		}
+		blocks := []uint64{}
+		for _, trace := range decode(raw) {
+			blocks = append(blocks, trace.BlockNumber)
+		}
+		if fmt.Sprint(blocks) != fmt.Sprint(tt.blocks) {
+			t.Errorf("test %d: block mismatch, have %v, want %v", i, blocks, tt.blocks)
+		}
+	}
+}
@@ -38,6 +38,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/params"
 	"github.com/scroll-tech/go-ethereum/rlp"
 	"github.com/scroll-tech/go-ethereum/rollup/fees"
+	"github.com/scroll-tech/go-ethereum/rollup/rcfg"
 	"github.com/scroll-tech/go-ethereum/tests"
 
 	// Force-load native and js pacakges, to trigger registration
@@ -184,7 +185,7 @@ func testCallTracer(tracerName string, dirPath string, t *testing.T) {
 				}
 				_, statedb = tests.MakePreState(rawdb.NewMemoryDatabase(), test.Genesis.Alloc, false)
 			)
-			tracer, err := tracers.New(tracerName, new(tracers.Context))
+			tracer, err := tracers.New(tracerName, new(tracers.Context), nil)
 			if err != nil {
 				t.Fatalf("failed to create call tracer: %v", err)
 			}
@@ -302,7 +303,7 @@ func benchTracer(tracerName string, test *callTracerTest, b *testing.B) {
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		tracer, err := tracers.New(tracerName, new(tracers.Context))
+		tracer, err := tracers.New(tracerName, new(tracers.Context), nil)
 		if err != nil {
 			b.Fatalf("failed to create call tracer: %v", err)
 		}
@@ -372,7 +373,7 @@ func TestZeroValueToNotExitCall(t *testing.T) {
 	}
 	_, statedb := tests.MakePreState(rawdb.NewMemoryDatabase(), alloc, false)
 	// Create the tracer, the EVM environment and run it
-	tracer, err := tracers.New("callTracer", nil)
+	tracer, err := tracers.New("callTracer", nil, nil)
 	if err != nil {
 		t.Fatalf("failed to create call tracer: %v", err)
 	}
@@ -405,3 +406,240 @@ func TestZeroValueToNotExitCall(t *testing.T) {
 		t.Error("have != want")
 	}
 }
+
+// traceWithConfig runs a transaction calling a contract which stores a value,
+// emits a log and calls into a reverting contract emitting another log, and
+// returns the result of the given tracer.
+func traceWithConfig(t *testing.T, tracerName string, cfg string) (json.RawMessage, common.Address, common.Address) {
+	return traceWithChainConfig(t, params.MainnetChainConfig, tracerName, cfg)
+}
+
+// traceWithChainConfig is traceWithConfig on the given chain, charging an L1
+// data fee if the chain has the fee vault enabled.
+func traceWithChainConfig(t *testing.T, chainConfig *params.ChainConfig, tracerName string, cfg string) (json.RawMessage, common.Address, common.Address) {
+	var (
+		to       = common.HexToAddress("0x00000000000000000000000000000000deadbeef")
+		reverter = common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	)
+	privkey, err := crypto.HexToECDSA("0000000000000000deadbeef00000000000000000000000000000000deadbeef")
+	if err != nil {
+		t.Fatalf("err %v", err)
+	}
+	signer := types.NewEIP155Signer(big.NewInt(1))
+	tx, err := types.SignNewTx(privkey, signer, &types.LegacyTx{
+		GasPrice: big.NewInt(1),
+		Gas:      100000,
+		To:       &to,
+	})
+	if err != nil {
+		t.Fatalf("err %v", err)
+	}
+	origin, _ := signer.Sender(tx)
+	context := vm.BlockContext{
+		CanTransfer: core.CanTransfer,
+		Transfer:    core.Transfer,
+		Coinbase:    common.Address{},
+		BlockNumber: new(big.Int).SetUint64(8000000),
+		Time:        new(big.Int).SetUint64(5),
+		Difficulty:  big.NewInt(0x30000),
+		GasLimit:    uint64(6000000),
+	}
+	var code = []byte{
+		byte(vm.PUSH1), 0x1, byte(vm.PUSH1), 0x0, byte(vm.SSTORE), // slot 0 = 1
+		byte(vm.PUSH1), 0x2a, byte(vm.PUSH1), 0x0, byte(vm.MSTORE8),
+		byte(vm.PUSH1), 0x7, byte(vm.PUSH1), 0x1, byte(vm.PUSH1), 0x0, byte(vm.LOG1), // log 0x2a with topic 7
+		byte(vm.PUSH1), 0x0, byte(vm.DUP1), byte(vm.DUP1), byte(vm.DUP1), // in and outs zero
+		byte(vm.DUP1), byte(vm.PUSH1), 0xbb, byte(vm.GAS), // value=0,address=0xbb, gas=GAS
+		byte(vm.CALL),
+	}
+	var revertCode = []byte{
+		byte(vm.PUSH1), 0x0, byte(vm.DUP1), byte(vm.LOG0),
+		byte(vm.PUSH1), 0x0, byte(vm.DUP1), byte(vm.REVERT),
+	}
+	var alloc = core.GenesisAlloc{
+		to:       core.GenesisAccount{Nonce: 1, Code: code},
+		reverter: core.GenesisAccount{Nonce: 1, Code: revertCode},
+		origin:   core.GenesisAccount{Nonce: 0, Balance: big.NewInt(500000000000000)},
+		rcfg.L1GasPriceOracleAddress: core.GenesisAccount{
+			Balance: big.NewInt(0),
+			Storage: map[common.Hash]common.Hash{
+				rcfg.L1BaseFeeSlot: common.BigToHash(big.NewInt(1000)),
+				rcfg.ScalarSlot:    common.BigToHash(big.NewInt(1000000000)),
+			},
+		},
+	}
+	_, statedb := tests.MakePreState(rawdb.NewMemoryDatabase(), alloc, false)
+
+	l1DataFee, err := fees.CalculateL1DataFee(tx, statedb, chainConfig, context.BlockNumber)
+	if err != nil {
+		t.Fatalf("failed to calculate l1DataFee: %v", err)
+	}
+	tracer, err := tracers.New(tracerName, &tracers.Context{L1DataFee: l1DataFee}, json.RawMessage(cfg))
+	if err != nil {
+		t.Fatalf("failed to create tracer: %v", err)
+	}
+	msg, err := tx.AsMessage(signer, nil)
+	if err != nil {
+		t.Fatalf("failed to prepare transaction for tracing: %v", err)
+	}
+	evm := vm.NewEVM(context, core.NewEVMTxContext(msg), statedb, chainConfig, vm.Config{Debug: true, Tracer: tracer})
+	st := core.NewStateTransition(evm, msg, new(core.GasPool).AddGas(tx.Gas()), l1DataFee)
+	if _, err = st.TransitionDb(); err != nil {
+		t.Fatalf("failed to execute transaction: %v", err)
+	}
+	res, err := tracer.GetResult()
+	if err != nil {
+		t.Fatalf("failed to retrieve trace result: %v", err)
+	}
+	return res, origin, to
+}
+
+func TestCallTracerConfig(t *testing.T) {
+	type frame struct {
+		Type  string `json:"type"`
+		Error string `json:"error"`
+		Logs  []struct {
+			Address common.Address `json:"address"`
+			Topics  []common.Hash  `json:"topics"`
+			Data    hexutil.Bytes  `json:"data"`
+		} `json:"logs"`
+		Calls []frame `json:"calls"`
+	}
+	decode := func(res json.RawMessage) *frame {
+		f := new(frame)
+		if err := json.Unmarshal(res, f); err != nil {
+			t.Fatalf("failed to unmarshal trace result: %v", err)
+		}
+		return f
+	}
+
+	// By default, sub calls are traced without logs
+	res, _, _ := traceWithConfig(t, "callTracer", "")
+	if f := decode(res); len(f.Calls) != 1 || len(f.Logs) != 0 {
+		t.Fatalf("unexpected default trace: %s", res)
+	}
+
+	// Logs of reverted calls are dropped
+	res, _, to := traceWithConfig(t, "callTracer", `{"withLog": true}`)
+	f := decode(res)
+	if len(f.Logs) != 1 || len(f.Calls) != 1 {
+		t.Fatalf("unexpected trace with logs: %s", res)
+	}
+	if log := f.Logs[0]; log.Address != to || len(log.Topics) != 1 || log.Topics[0] != (common.Hash{31: 7}) || !reflect.DeepEqual(log.Data, hexutil.Bytes{0x2a}) {
+		t.Errorf("unexpected log: %s", res)
+	}
+	if call := f.Calls[0]; call.Error != "execution reverted" || len(call.Logs) != 0 {
+		t.Errorf("unexpected reverted call: %s", res)
+	}
+
+	res, _, _ = traceWithConfig(t, "callTracer", `{"onlyTopCall": true, "withLog": true}`)
+	if f := decode(res); len(f.Calls) != 0 || len(f.Logs) != 1 {
+		t.Fatalf("unexpected top call trace: %s", res)
+	}
+
+	// Unknown options are rejected rather than falling back to the js tracer
+	if _, err := tracers.New("callTracer", nil, json.RawMessage(`{"onlyTop": true}`)); err == nil {
+		t.Fatal("expected invalid config to be rejected")
+	}
+}
+
+func TestPrestateTracerDiffMode(t *testing.T) {
+	type account struct {
+		Balance *hexutil.Big                `json:"balance"`
+		Nonce   uint64                      `json:"nonce"`
+		Storage map[common.Hash]common.Hash `json:"storage"`
+	}
+
+	// Without diff mode, the prestate of all touched accounts is returned
+	res, origin, to := traceWithConfig(t, "prestateTracer", "")
+	var prestate map[common.Address]account
+	if err := json.Unmarshal(res, &prestate); err != nil {
+		t.Fatalf("failed to unmarshal trace result: %v", err)
+	}
+	if len(prestate) != 4 {
+		t.Fatalf("unexpected prestate: %s", res)
+	}
+	if have := prestate[origin].Balance.ToInt(); have.Cmp(big.NewInt(500000000000000)) != 0 {
+		t.Errorf("sender balance mismatch: have %v, want %v", have, 500000000000000)
+	}
+
+	res, _, _ = traceWithConfig(t, "prestateTracer", `{"diffMode": true}`)
+	var diff struct {
+		Pre  map[common.Address]account `json:"pre"`
+		Post map[common.Address]account `json:"post"`
+	}
+	if err := json.Unmarshal(res, &diff); err != nil {
+		t.Fatalf("failed to unmarshal trace result: %v", err)
+	}
+	// The reverting contract is left untouched
+	if _, ok := diff.Pre[common.HexToAddress("0xbb")]; ok {
+		t.Errorf("unmodified account in prestate: %s", res)
+	}
+	if pre, post := diff.Pre[origin], diff.Post[origin]; pre.Nonce != 0 || post.Nonce != 1 || pre.Balance.ToInt().Cmp(big.NewInt(500000000000000)) != 0 || post.Balance.ToInt().Cmp(pre.Balance.ToInt()) >= 0 {
+		t.Errorf("unexpected sender diff: %s", res)
+	}
+	if pre, post := diff.Pre[to], diff.Post[to]; len(pre.Storage) != 0 || post.Storage[common.Hash{}] != (common.Hash{31: 1}) {
+		t.Errorf("unexpected storage diff: %s", res)
+	}
+}
+
+// Tests that the prestate of the sender includes the L1 data fee charged by
+// chains with the fee vault enabled.
+func TestPrestateTracerL1DataFee(t *testing.T) {
+	var (
+		vault       = common.HexToAddress("0x5300000000000000000000000000000000000005")
+		chainConfig = *params.MainnetChainConfig
+	)
+	chainConfig.Scroll.FeeVaultAddress = &vault
+
+	res, origin, _ := traceWithChainConfig(t, &chainConfig, "prestateTracer", "")
+	var prestate map[common.Address]struct {
+		Balance *hexutil.Big `json:"balance"`
+	}
+	if err := json.Unmarshal(res, &prestate); err != nil {
+		t.Fatalf("failed to unmarshal trace result: %v", err)
+	}
+	if have := prestate[origin].Balance.ToInt(); have.Cmp(big.NewInt(500000000000000)) != 0 {
+		t.Errorf("sender balance mismatch: have %v, want %v", have, 500000000000000)
+	}
+}
+
+func TestFlatCallTracer(t *testing.T) {
+	res, origin, to := traceWithConfig(t, "flatCallTracer", "")
+	var have []struct {
+		Action struct {
+			CallType string         `json:"callType"`
+			From     common.Address `json:"from"`
+			To       common.Address `json:"to"`
+			Value    *hexutil.Big   `json:"value"`
+		} `json:"action"`
+		BlockNumber uint64 `json:"blockNumber"`
+		Error       string `json:"error"`
+		Result      *struct {
+			GasUsed hexutil.Uint64 `json:"gasUsed"`
+			Output  hexutil.Bytes  `json:"output"`
+		} `json:"result"`
+		Subtraces    int    `json:"subtraces"`
+		TraceAddress []int  `json:"traceAddress"`
+		Type         string `json:"type"`
+	}
+	if err := json.Unmarshal(res, &have); err != nil {
+		t.Fatalf("failed to unmarshal trace result: %v", err)
+	}
+	if len(have) != 2 {
+		t.Fatalf("unexpected number of traces: %s", res)
+	}
+	top, sub := have[0], have[1]
+	if top.Type != "call" || top.Action.CallType != "call" || top.Action.From != origin || top.Action.To != to || top.BlockNumber != 8000000 {
+		t.Errorf("unexpected top-level trace: %s", res)
+	}
+	if top.Subtraces != 1 || len(top.TraceAddress) != 0 || top.Result == nil || top.Result.GasUsed == 0 {
+		t.Errorf("unexpected top-level trace position or result: %s", res)
+	}
+	if sub.Action.From != to || sub.Action.To != common.HexToAddress("0xbb") || sub.Action.Value.ToInt().Sign() != 0 {
+		t.Errorf("unexpected sub trace: %s", res)
+	}
+	if sub.Subtraces != 0 || !reflect.DeepEqual(sub.TraceAddress, []int{0}) || sub.Error != "Reverted" || sub.Result != nil {
+		t.Errorf("unexpected sub trace position or result: %s", res)
+	}
+}
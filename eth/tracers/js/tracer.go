@@ -425,7 +425,7 @@ type jsTracer struct {
 // New instantiates a new tracer instance. code specifies a Javascript snippet,
 // which must evaluate to an expression returning an object with 'step', 'fault'
 // and 'result' functions.
-func newJsTracer(code string, ctx *tracers2.Context) (tracers2.Tracer, error) {
+func newJsTracer(code string, ctx *tracers2.Context, cfg json.RawMessage) (tracers2.Tracer, error) {
 	if c, ok := assetTracers[code]; ok {
 		code = c
 	}
@@ -577,6 +577,24 @@ func newJsTracer(code string, ctx *tracers2.Context) (tracers2.Tracer, error) {
 	tracer.traceCallFrames = hasEnter && hasExit
 	tracer.traceSteps = hasStep
 
+	// Pass the tracer config to the optional setup method
+	hasSetup := tracer.vm.GetPropString(tracer.tracerObject, "setup")
+	tracer.vm.Pop()
+	if hasSetup {
+		if len(cfg) == 0 {
+			cfg = json.RawMessage("{}")
+		}
+		tracer.vm.PushString("setup")
+		tracer.vm.PushString(string(cfg))
+		tracer.vm.JsonDecode(-1)
+		if code := tracer.vm.PcallProp(tracer.tracerObject, 1); code != 0 {
+			err := errors.New(tracer.vm.SafeToString(-1))
+			tracer.vm.Pop()
+			return nil, fmt.Errorf("%w: %v", tracers2.ErrInvalidConfig, err)
+		}
+		tracer.vm.Pop()
+	}
+
 	// Tracer is valid, inject the big int library to access large numbers
 	tracer.vm.EvalString(bigIntegerJS)
 	tracer.vm.PutGlobalString("bigInt")
@@ -777,6 +795,10 @@ func (jst *jsTracer) CaptureEnd(output []byte, gasUsed uint64, t time.Duration,
 	}
 }
 
+func (*jsTracer) CaptureTxStart(gasLimit uint64) {}
+
+func (*jsTracer) CaptureTxEnd(restGas uint64) {}
+
 // CaptureEnter is called when EVM enters a new scope (via call, create or selfdestruct).
 func (jst *jsTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
 	if !jst.traceCallFrames {
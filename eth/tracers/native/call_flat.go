@@ -0,0 +1,292 @@
+package native
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/vm"
+	"github.com/scroll-tech/go-ethereum/eth/tracers"
+)
+
+func init() {
+	register("flatCallTracer", newFlatCallTracer)
+}
+
+// parityErrorMapping maps the EVM errors to the messages used by Parity traces.
+var parityErrorMapping = map[string]string{
+	"out of gas":                      "Out of gas",
+	"gas uint64 overflow":             "Out of gas",
+	"max code size exceeded":          "Out of gas",
+	"invalid jump destination":        "Bad jump destination",
+	"execution reverted":              "Reverted",
+	"return data out of bounds":       "Out of bounds",
+	"stack limit reached 1024 (1023)": "Out of stack",
+	"precompiled failed":              "Built-in failed",
+	"invalid input length":            "Built-in failed",
+
+	"contract creation code storage out of gas": "Out of gas",
+}
+
+// parityErrorPrefixMapping maps the EVM errors with variable suffixes.
+var parityErrorPrefixMapping = map[string]string{
+	"invalid opcode":  "Bad instruction",
+	"stack underflow": "Stack underflow",
+}
+
+// flatCallFrame is a single call in the Parity trace format.
+type flatCallFrame struct {
+	Action              flatCallAction  `json:"action"`
+	BlockHash           *common.Hash    `json:"blockHash"`
+	BlockNumber         uint64          `json:"blockNumber"`
+	Error               string          `json:"error,omitempty"`
+	Result              *flatCallResult `json:"result,omitempty"`
+	Subtraces           int             `json:"subtraces"`
+	TraceAddress        []int           `json:"traceAddress"`
+	TransactionHash     *common.Hash    `json:"transactionHash"`
+	TransactionPosition *int            `json:"transactionPosition"`
+	Type                string          `json:"type"`
+}
+
+type flatCallAction struct {
+	// calls and creations
+	CallType       string `json:"callType,omitempty"`
+	CreationMethod string `json:"creationMethod,omitempty"`
+	From           string `json:"from,omitempty"`
+	Gas            string `json:"gas,omitempty"`
+	Init           string `json:"init,omitempty"`
+	Input          string `json:"input,omitempty"`
+	To             string `json:"to,omitempty"`
+	Value          string `json:"value,omitempty"`
+
+	// self-destructs
+	Address       string `json:"address,omitempty"`
+	Balance       string `json:"balance,omitempty"`
+	RefundAddress string `json:"refundAddress,omitempty"`
+}
+
+type flatCallResult struct {
+	Address string `json:"address,omitempty"`
+	Code    string `json:"code,omitempty"`
+	GasUsed string `json:"gasUsed,omitempty"`
+	Output  string `json:"output,omitempty"`
+}
+
+// flatCallTracer reports call frames in the flat format of Parity traces. It
+// is built on top of the callTracer.
+type flatCallTracer struct {
+	tracer            *callTracer
+	ctx               *tracers.Context
+	config            flatCallTracerConfig
+	blockNumber       uint64
+	activePrecompiles []common.Address // Updated on CaptureStart based on given rules
+	skipExit          bool             // Whether the exit of a precompile call is to be skipped
+}
+
+type flatCallTracerConfig struct {
+	IncludePrecompiles bool `json:"includePrecompiles"` // If true, calls to precompiles are reported
+}
+
+// newFlatCallTracer returns a native go tracer which reports the call frames
+// of a tx as a flat list, and implements vm.EVMLogger.
+func newFlatCallTracer(ctx *tracers.Context, cfg json.RawMessage) (tracers.Tracer, error) {
+	var config flatCallTracerConfig
+	if err := parseConfig(cfg, &config); err != nil {
+		return nil, err
+	}
+	tracer, err := newCallTracer(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if ctx == nil {
+		ctx = new(tracers.Context)
+	}
+	return &flatCallTracer{tracer: tracer.(*callTracer), ctx: ctx, config: config}, nil
+}
+
+// CaptureStart implements the EVMLogger interface to initialize the tracing operation.
+func (t *flatCallTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.tracer.CaptureStart(env, from, to, create, input, gas, value)
+
+	t.blockNumber = env.Context.BlockNumber.Uint64()
+	rules := env.ChainConfig().Rules(env.Context.BlockNumber)
+	t.activePrecompiles = vm.ActivePrecompiles(rules)
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *flatCallTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) {
+	t.tracer.CaptureEnd(output, gasUsed, d, err)
+}
+
+// CaptureState implements the EVMLogger interface to trace a single step of VM execution.
+func (t *flatCallTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+
+// CaptureStateAfter for special needs, tracks SSTORE ops and records the storage change.
+func (t *flatCallTracer) CaptureStateAfter(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+
+// CaptureFault implements the EVMLogger interface to trace an execution fault.
+func (t *flatCallTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, _ *vm.ScopeContext, depth int, err error) {
+}
+
+// CaptureEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *flatCallTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	// Precompiles never call further, so only their own exit is to be skipped
+	if !t.config.IncludePrecompiles && typ != vm.SELFDESTRUCT && t.isPrecompiled(to) {
+		t.skipExit = true
+		return
+	}
+	t.tracer.CaptureEnter(typ, from, to, input, gas, value)
+}
+
+// CaptureExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *flatCallTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if t.skipExit {
+		t.skipExit = false
+		return
+	}
+	t.tracer.CaptureExit(output, gasUsed, err)
+}
+
+func (*flatCallTracer) CaptureTxStart(gasLimit uint64) {}
+
+func (*flatCallTracer) CaptureTxEnd(restGas uint64) {}
+
+// GetResult returns the json-encoded flat list of call traces, and any
+// error arising from the encoding or forceful termination (via `Stop`).
+func (t *flatCallTracer) GetResult() (json.RawMessage, error) {
+	if len(t.tracer.callstack) != 1 {
+		return nil, errors.New("incorrect number of top-level calls")
+	}
+	var (
+		blockHash *common.Hash
+		txHash    *common.Hash
+		txIndex   *int
+	)
+	if t.ctx.BlockHash != (common.Hash{}) {
+		blockHash = &t.ctx.BlockHash
+		if t.ctx.TxHash != (common.Hash{}) {
+			txHash, txIndex = &t.ctx.TxHash, &t.ctx.TxIndex
+		}
+	}
+	frames := flattenCallFrame(&t.tracer.callstack[0], nil, nil)
+	for _, frame := range frames {
+		frame.BlockHash = blockHash
+		frame.BlockNumber = t.blockNumber
+		frame.TransactionHash = txHash
+		frame.TransactionPosition = txIndex
+	}
+	res, err := json.Marshal(frames)
+	if err != nil {
+		return nil, err
+	}
+	return res, t.tracer.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *flatCallTracer) Stop(err error) {
+	t.tracer.Stop(err)
+}
+
+// isPrecompiled returns whether the addr is a precompile.
+func (t *flatCallTracer) isPrecompiled(addr common.Address) bool {
+	for _, p := range t.activePrecompiles {
+		if p == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// flattenCallFrame appends the given call frame and its sub calls in
+// depth-first order, annotated with their position in the call tree.
+func flattenCallFrame(frame *callFrame, traceAddress []int, output []*flatCallFrame) []*flatCallFrame {
+	flat := newFlatCallFrame(frame)
+	flat.Subtraces = len(frame.Calls)
+	flat.TraceAddress = append([]int{}, traceAddress...)
+	output = append(output, flat)
+
+	for i := range frame.Calls {
+		output = flattenCallFrame(&frame.Calls[i], append(traceAddress, i), output)
+	}
+	return output
+}
+
+// newFlatCallFrame converts a call frame of the callTracer to the Parity format.
+func newFlatCallFrame(frame *callFrame) *flatCallFrame {
+	flat := new(flatCallFrame)
+	switch frame.Type {
+	case "CREATE", "CREATE2":
+		flat.Type = "create"
+		flat.Action = flatCallAction{
+			CreationMethod: strings.ToLower(frame.Type),
+			From:           frame.From,
+			Gas:            frame.Gas,
+			Init:           frame.Input,
+			Value:          valueOrZero(frame.Value),
+		}
+		flat.Result = &flatCallResult{
+			Address: frame.To,
+			Code:    orEmptyBytes(frame.Output),
+			GasUsed: frame.GasUsed,
+		}
+	case "SELFDESTRUCT":
+		flat.Type = "suicide"
+		flat.Action = flatCallAction{
+			Address:       frame.From,
+			Balance:       valueOrZero(frame.Value),
+			RefundAddress: frame.To,
+		}
+	default:
+		flat.Type = "call"
+		flat.Action = flatCallAction{
+			CallType: strings.ToLower(frame.Type),
+			From:     frame.From,
+			Gas:      frame.Gas,
+			Input:    frame.Input,
+			To:       frame.To,
+			Value:    valueOrZero(frame.Value),
+		}
+		flat.Result = &flatCallResult{
+			GasUsed: frame.GasUsed,
+			Output:  orEmptyBytes(frame.Output),
+		}
+	}
+	if frame.Error != "" {
+		flat.Error = parityError(frame.Error)
+		flat.Result = nil
+	}
+	return flat
+}
+
+// parityError converts an EVM error message to its Parity counterpart.
+func parityError(err string) string {
+	if msg, ok := parityErrorMapping[err]; ok {
+		return msg
+	}
+	for prefix, msg := range parityErrorPrefixMapping {
+		if strings.HasPrefix(err, prefix) {
+			return msg
+		}
+	}
+	return err
+}
+
+func valueOrZero(value string) string {
+	if value == "" {
+		return "0x0"
+	}
+	return value
+}
+
+func orEmptyBytes(data string) string {
+	if data == "" {
+		return "0x"
+	}
+	return data
+}
@@ -50,6 +50,7 @@ type accountMarshaling struct {
 type prestateTracer struct {
 	noopTracer
 	env       *vm.EVM
+	l1DataFee *big.Int // L1 data fee charged to the sender, nil if none
 	pre       state
 	post      state
 	create    bool
@@ -59,15 +60,30 @@ type prestateTracer struct {
 	reason    error       // Textual reason for the interruption
 	created   map[common.Address]bool
 	deleted   map[common.Address]bool
+	config    prestateTracerConfig
 }
 
-func newPrestateTracer() tracers.Tracer {
-	return &prestateTracer{
-		pre:     state{},
-		post:    state{},
-		created: make(map[common.Address]bool),
-		deleted: make(map[common.Address]bool),
+type prestateTracerConfig struct {
+	DiffMode bool `json:"diffMode"` // If true, this tracer will return state modifications
+}
+
+func newPrestateTracer(ctx *tracers.Context, cfg json.RawMessage) (tracers.Tracer, error) {
+	var config prestateTracerConfig
+	if err := parseConfig(cfg, &config); err != nil {
+		return nil, err
+	}
+	var l1DataFee *big.Int
+	if ctx != nil {
+		l1DataFee = ctx.L1DataFee
 	}
+	return &prestateTracer{
+		l1DataFee: l1DataFee,
+		pre:       state{},
+		post:      state{},
+		config:    config,
+		created:   make(map[common.Address]bool),
+		deleted:   make(map[common.Address]bool),
+	}, nil
 }
 
 // CaptureStart implements the EVMLogger interface to initialize the tracing operation.
@@ -84,18 +100,30 @@ func (t *prestateTracer) CaptureStart(env *vm.EVM, from common.Address, to commo
 	toBal := new(big.Int).Sub(t.pre[to].Balance, value)
 	t.pre[to].Balance = toBal
 
-	// The sender balance is after reducing: value and gasLimit.
-	// We need to re-add them to get the pre-tx balance.
+	// The sender balance is after reducing: value, gasLimit and the L1 data
+	// fee if it is charged. We need to re-add them to get the pre-tx balance.
 	fromBal := new(big.Int).Set(t.pre[from].Balance)
 	gasPrice := env.TxContext.GasPrice
 	consumedGas := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(t.gasLimit))
 	fromBal.Add(fromBal, new(big.Int).Add(value, consumedGas))
+	if t.l1DataFee != nil && env.ChainConfig().Scroll.FeeVaultEnabled() {
+		fromBal.Add(fromBal, t.l1DataFee)
+	}
 	t.pre[from].Balance = fromBal
 	t.pre[from].Nonce--
+
+	if create && t.config.DiffMode {
+		t.created[to] = true
+	}
 }
 
 // CaptureEnd is called after the call finishes to finalize the tracing.
 func (t *prestateTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) {
+	// In diff mode the created contract is needed to compute its post state
+	// and is removed from the prestate once the transaction ends.
+	if t.config.DiffMode {
+		return
+	}
 	if t.create {
 		// Keep existing account prior to contract creation at that address
 		if s := t.pre[t.to]; s != nil && !s.exists() {
@@ -157,6 +185,9 @@ func (t *prestateTracer) CaptureTxStart(gasLimit uint64) {
 }
 
 func (t *prestateTracer) CaptureTxEnd(restGas uint64) {
+	if !t.config.DiffMode {
+		return
+	}
 	for addr, state := range t.pre {
 		// The deleted account's state is pruned from `post` but kept in `pre`
 		if _, ok := t.deleted[addr]; ok {
@@ -218,17 +249,33 @@ func (t *prestateTracer) CaptureTxEnd(restGas uint64) {
 // GetResult returns the json-encoded nested list of call traces, and any
 // error arising from the encoding or forceful termination (via `Stop`).
 func (t *prestateTracer) GetResult() (json.RawMessage, error) {
-	pre := make(map[string]accountMarshaling)
-	for addr, state := range t.pre {
-		pre[addrToHex(addr)] = state.marshal()
+	var (
+		res []byte
+		err error
+	)
+	if t.config.DiffMode {
+		res, err = json.Marshal(struct {
+			Post map[string]accountMarshaling `json:"post"`
+			Pre  map[string]accountMarshaling `json:"pre"`
+		}{marshalState(t.post), marshalState(t.pre)})
+	} else {
+		res, err = json.Marshal(marshalState(t.pre))
 	}
-	res, err := json.Marshal(pre)
 	if err != nil {
 		return nil, err
 	}
 	return res, t.reason
 }
 
+// marshalState converts the accounts of a state into their JSON representation.
+func marshalState(s state) map[string]accountMarshaling {
+	res := make(map[string]accountMarshaling, len(s))
+	for addr, account := range s {
+		res[addrToHex(addr)] = account.marshal()
+	}
+	return res
+}
+
 // Stop terminates execution of the tracer at the first opportune moment.
 func (t *prestateTracer) Stop(err error) {
 	t.reason = err
@@ -31,11 +31,17 @@ func init() {
 	register("noopTracerNative", newNoopTracer)
 }
 ```
+
+Tracers receive the user supplied `tracerConfig` as raw JSON, which they
+should decode into their own configuration struct.
 */
 package native
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
 
 	"github.com/scroll-tech/go-ethereum/eth/tracers"
 )
@@ -57,23 +63,41 @@ The go spec (https://golang.org/ref/spec#Package_initialization) says
 
 Hence, we cannot make the map in init, but must make it upon first use.
 */
-var ctors map[string]func() tracers.Tracer
+var ctors map[string]ctorFn
+
+// ctorFn is the constructor signature of a native tracer.
+type ctorFn func(*tracers.Context, json.RawMessage) (tracers.Tracer, error)
 
 // register is used by native tracers to register their presence.
-func register(name string, ctor func() tracers.Tracer) {
+func register(name string, ctor ctorFn) {
 	if ctors == nil {
-		ctors = make(map[string]func() tracers.Tracer)
+		ctors = make(map[string]ctorFn)
 	}
 	ctors[name] = ctor
 }
 
 // lookup returns a tracer, if one can be matched to the given name.
-func lookup(name string, ctx *tracers.Context) (tracers.Tracer, error) {
+func lookup(name string, ctx *tracers.Context, cfg json.RawMessage) (tracers.Tracer, error) {
 	if ctors == nil {
-		ctors = make(map[string]func() tracers.Tracer)
+		ctors = make(map[string]ctorFn)
 	}
 	if ctor, ok := ctors[name]; ok {
-		return ctor(), nil
+		tracer, err := ctor(ctx, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", tracers.ErrInvalidConfig, err)
+		}
+		return tracer, nil
 	}
 	return nil, errors.New("no tracer found")
 }
+
+// parseConfig decodes the tracer configuration into the given struct,
+// leaving the defaults in place if no configuration was supplied.
+func parseConfig(cfg json.RawMessage, config interface{}) error {
+	if len(cfg) == 0 {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(cfg))
+	dec.DisallowUnknownFields()
+	return dec.Decode(config)
+}
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/core/vm"
@@ -32,6 +33,7 @@ type Context struct {
 	BlockHash common.Hash // Hash of the block the tx is contained within (zero if dangling tx or call)
 	TxIndex   int         // Index of the transaction within a block (zero if dangling tx or call)
 	TxHash    common.Hash // Hash of the transaction being traced (zero if dangling call)
+	L1DataFee *big.Int    // L1 data fee of the transaction being traced (nil if none)
 }
 
 // Tracer interface extends vm.EVMLogger and additionally
@@ -43,7 +45,7 @@ type Tracer interface {
 	Stop(err error)
 }
 
-type lookupFunc func(string, *Context) (Tracer, error)
+type lookupFunc func(string, *Context, json.RawMessage) (Tracer, error)
 
 var (
 	lookups []lookupFunc
@@ -62,16 +64,23 @@ func RegisterLookup(wildcard bool, lookup lookupFunc) {
 }
 
 // New returns a new instance of a tracer, by iterating through the
-// registered lookups.
-func New(code string, ctx *Context) (Tracer, error) {
+// registered lookups. The tracer specific configuration is passed as
+// raw JSON and may be nil.
+func New(code string, ctx *Context, cfg json.RawMessage) (Tracer, error) {
 	for _, lookup := range lookups {
-		if tracer, err := lookup(code, ctx); err == nil {
+		if tracer, err := lookup(code, ctx, cfg); err == nil {
 			return tracer, nil
+		} else if errors.Is(err, ErrInvalidConfig) {
+			return nil, err
 		}
 	}
 	return nil, errors.New("tracer not found")
 }
 
+// ErrInvalidConfig is returned by lookups if the named tracer exists but
+// rejects the given configuration.
+var ErrInvalidConfig = errors.New("invalid tracer config")
+
 const (
 	memoryPadLimit = 1024 * 1024
 )
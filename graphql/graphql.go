@@ -28,6 +28,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/common/hexutil"
 	"github.com/scroll-tech/go-ethereum/common/math"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
 	"github.com/scroll-tech/go-ethereum/core/state"
 	"github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/eth/filters"
@@ -39,6 +40,10 @@ var (
 	errBlockInvariant = errors.New("block objects must be instantiated with at least one of num or hash")
 )
 
+// maxSkippedTransactionsRange is the maximum number of skipped transactions
+// returned by a single skippedTransactions query.
+const maxSkippedTransactionsRange = 1000
+
 type Long int64
 
 // ImplementsGraphQLType returns true if Long implements the provided GraphQL type.
@@ -474,6 +479,39 @@ func (t *Transaction) V(ctx context.Context) (hexutil.Big, error) {
 	return hexutil.Big(*v), nil
 }
 
+func (t *Transaction) QueueIndex(ctx context.Context) (*Long, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil || tx == nil {
+		return nil, err
+	}
+	msg := tx.AsL1MessageTx()
+	if msg == nil {
+		return nil, nil
+	}
+	queueIndex := Long(msg.QueueIndex)
+	return &queueIndex, nil
+}
+
+func (t *Transaction) L1Sender(ctx context.Context) (*common.Address, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil || tx == nil {
+		return nil, err
+	}
+	msg := tx.AsL1MessageTx()
+	if msg == nil {
+		return nil, nil
+	}
+	return &msg.Sender, nil
+}
+
+func (t *Transaction) L1Fee(ctx context.Context) (*hexutil.Big, error) {
+	receipt, err := t.getReceipt(ctx)
+	if err != nil || receipt == nil || receipt.L1Fee == nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(receipt.L1Fee), nil
+}
+
 type BlockType int
 
 // Block represents an Ethereum block.
@@ -735,6 +773,71 @@ func (b *Block) TotalDifficulty(ctx context.Context) (hexutil.Big, error) {
 	return hexutil.Big(*td), nil
 }
 
+// SubCircuitRowUsage represents the number of rows a block uses in a sub-circuit.
+type SubCircuitRowUsage struct {
+	usage types.SubCircuitRowUsage
+}
+
+func (u *SubCircuitRowUsage) Name() string {
+	return u.usage.Name
+}
+
+func (u *SubCircuitRowUsage) RowNumber() Long {
+	return Long(u.usage.RowNumber)
+}
+
+func (b *Block) RowConsumption(ctx context.Context) (*[]*SubCircuitRowUsage, error) {
+	header, err := b.resolveHeader(ctx)
+	if err != nil || header == nil {
+		return nil, err
+	}
+	rc := rawdb.ReadBlockRowConsumption(b.backend.ChainDb(), header.Hash())
+	if rc == nil {
+		return nil, nil
+	}
+	ret := make([]*SubCircuitRowUsage, 0, len(*rc))
+	for _, usage := range *rc {
+		ret = append(ret, &SubCircuitRowUsage{usage: usage})
+	}
+	return &ret, nil
+}
+
+// resolveCanonicalNumber returns the number of this block if it is part of
+// the canonical chain. Rollup batches only commit canonical blocks.
+func (b *Block) resolveCanonicalNumber(ctx context.Context) (*uint64, error) {
+	header, err := b.resolveHeader(ctx)
+	if err != nil || header == nil {
+		return nil, err
+	}
+	number := header.Number.Uint64()
+	if rawdb.ReadCanonicalHash(b.backend.ChainDb(), number) != header.Hash() {
+		return nil, nil
+	}
+	return &number, nil
+}
+
+func (b *Block) BatchIndex(ctx context.Context) (*Long, error) {
+	number, err := b.resolveCanonicalNumber(ctx)
+	if err != nil || number == nil {
+		return nil, err
+	}
+	index := rawdb.FindBatchIndexByL2BlockNumber(b.backend.ChainDb(), *number)
+	if index == nil {
+		return nil, nil
+	}
+	ret := Long(*index)
+	return &ret, nil
+}
+
+func (b *Block) Finalized(ctx context.Context) (bool, error) {
+	number, err := b.resolveCanonicalNumber(ctx)
+	if err != nil || number == nil {
+		return false, err
+	}
+	finalized := rawdb.ReadFinalizedL2BlockNumber(b.backend.ChainDb())
+	return finalized != nil && *number <= *finalized, nil
+}
+
 // BlockNumberArgs encapsulates arguments to accessors that specify a block number.
 type BlockNumberArgs struct {
 	// TODO: Ideally we could use input unions to allow the query to specify the
@@ -953,7 +1056,7 @@ func (b *Block) Call(ctx context.Context, args struct {
 			return nil, err
 		}
 	}
-	result, err := ethapi.DoCall(ctx, b.backend, args.Data, *b.numberOrHash, nil, b.backend.RPCEVMTimeout(), b.backend.RPCGasCap())
+	result, err := ethapi.DoCall(ctx, b.backend, args.Data, *b.numberOrHash, nil, nil, b.backend.RPCEVMTimeout(), b.backend.RPCGasCap())
 	if err != nil {
 		return nil, err
 	}
@@ -978,7 +1081,7 @@ func (b *Block) EstimateGas(ctx context.Context, args struct {
 			return 0, err
 		}
 	}
-	gas, err := ethapi.DoEstimateGas(ctx, b.backend, args.Data, *b.numberOrHash, b.backend.RPCGasCap())
+	gas, err := ethapi.DoEstimateGas(ctx, b.backend, args.Data, *b.numberOrHash, nil, nil, b.backend.RPCGasCap())
 	return Long(gas), err
 }
 
@@ -1023,7 +1126,7 @@ func (p *Pending) Call(ctx context.Context, args struct {
 	Data ethapi.TransactionArgs
 }) (*CallResult, error) {
 	pendingBlockNr := rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber)
-	result, err := ethapi.DoCall(ctx, p.backend, args.Data, pendingBlockNr, nil, p.backend.RPCEVMTimeout(), p.backend.RPCGasCap())
+	result, err := ethapi.DoCall(ctx, p.backend, args.Data, pendingBlockNr, nil, nil, p.backend.RPCEVMTimeout(), p.backend.RPCGasCap())
 	if err != nil {
 		return nil, err
 	}
@@ -1043,10 +1146,156 @@ func (p *Pending) EstimateGas(ctx context.Context, args struct {
 	Data ethapi.TransactionArgs
 }) (Long, error) {
 	pendingBlockNr := rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber)
-	gas, err := ethapi.DoEstimateGas(ctx, p.backend, args.Data, pendingBlockNr, p.backend.RPCGasCap())
+	gas, err := ethapi.DoEstimateGas(ctx, p.backend, args.Data, pendingBlockNr, nil, nil, p.backend.RPCGasCap())
 	return Long(gas), err
 }
 
+// L1Message represents a message enqueued on L1 to be included in L2.
+type L1Message struct {
+	backend ethapi.Backend
+	msg     *types.L1MessageTx
+}
+
+func (m *L1Message) QueueIndex() Long {
+	return Long(m.msg.QueueIndex)
+}
+
+func (m *L1Message) Hash() common.Hash {
+	return types.NewTx(m.msg).Hash()
+}
+
+func (m *L1Message) Sender() common.Address {
+	return m.msg.Sender
+}
+
+func (m *L1Message) Target() *common.Address {
+	return m.msg.To
+}
+
+func (m *L1Message) Value() hexutil.Big {
+	if m.msg.Value == nil {
+		return hexutil.Big{}
+	}
+	return hexutil.Big(*m.msg.Value)
+}
+
+func (m *L1Message) Gas() Long {
+	return Long(m.msg.Gas)
+}
+
+func (m *L1Message) Data() hexutil.Bytes {
+	return m.msg.Data
+}
+
+func (m *L1Message) Transaction(ctx context.Context) (*Transaction, error) {
+	tx := &Transaction{
+		backend: m.backend,
+		hash:    m.Hash(),
+	}
+	// Only messages included in a block have a transaction
+	if _, err := tx.resolve(ctx); err != nil || tx.block == nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// SkippedTransaction represents a transaction that was skipped by the sequencer,
+// e.g. because it exceeded the circuit capacity.
+type SkippedTransaction struct {
+	backend ethapi.Backend
+	index   uint64
+	stx     *rawdb.SkippedTransactionV2
+}
+
+func (s *SkippedTransaction) Index() Long {
+	return Long(s.index)
+}
+
+func (s *SkippedTransaction) Transaction() *Transaction {
+	return &Transaction{
+		backend: s.backend,
+		hash:    s.stx.Tx.Hash(),
+		tx:      s.stx.Tx,
+	}
+}
+
+func (s *SkippedTransaction) Reason() string {
+	return s.stx.Reason
+}
+
+func (s *SkippedTransaction) BlockNumber() Long {
+	return Long(s.stx.BlockNumber)
+}
+
+func (s *SkippedTransaction) BlockHash() *common.Hash {
+	return s.stx.BlockHash
+}
+
+// Batch represents a batch of L2 blocks committed to L1.
+type Batch struct {
+	backend ethapi.Backend
+	index   uint64
+	chunks  []*rawdb.ChunkBlockRange
+	meta    *rawdb.FinalizedBatchMeta // nil if the batch is not finalized
+}
+
+func (b *Batch) Index() Long {
+	return Long(b.index)
+}
+
+func (b *Batch) ChunkCount() int32 {
+	return int32(len(b.chunks))
+}
+
+func (b *Batch) blockAt(number uint64) *Block {
+	numberOrHash := rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(number))
+	return &Block{
+		backend:      b.backend,
+		numberOrHash: &numberOrHash,
+	}
+}
+
+func (b *Batch) StartBlock() *Block {
+	return b.blockAt(b.chunks[0].StartBlockNumber)
+}
+
+func (b *Batch) EndBlock() *Block {
+	return b.blockAt(b.chunks[len(b.chunks)-1].EndBlockNumber)
+}
+
+func (b *Batch) Finalized() bool {
+	return b.meta != nil
+}
+
+func (b *Batch) Hash() *common.Hash {
+	if b.meta == nil {
+		return nil
+	}
+	return &b.meta.BatchHash
+}
+
+func (b *Batch) TotalL1MessagePopped() *Long {
+	if b.meta == nil {
+		return nil
+	}
+	popped := Long(b.meta.TotalL1MessagePopped)
+	return &popped
+}
+
+func (b *Batch) StateRoot() *common.Hash {
+	if b.meta == nil {
+		return nil
+	}
+	return &b.meta.StateRoot
+}
+
+func (b *Batch) WithdrawRoot() *common.Hash {
+	if b.meta == nil {
+		return nil
+	}
+	return &b.meta.WithdrawRoot
+}
+
 // Resolver is the top-level object in the GraphQL hierarchy.
 type Resolver struct {
 	backend ethapi.Backend
@@ -1137,6 +1386,59 @@ func (r *Resolver) Transaction(ctx context.Context, args struct{ Hash common.Has
 	return tx, nil
 }
 
+func (r *Resolver) L1Message(ctx context.Context, args struct{ QueueIndex Long }) (*L1Message, error) {
+	if args.QueueIndex < 0 {
+		return nil, nil
+	}
+	msg := rawdb.ReadL1Message(r.backend.ChainDb(), uint64(args.QueueIndex))
+	if msg == nil {
+		return nil, nil
+	}
+	return &L1Message{backend: r.backend, msg: msg}, nil
+}
+
+func (r *Resolver) SkippedTransactions(ctx context.Context, args struct {
+	From Long
+	To   Long
+}) ([]*SkippedTransaction, error) {
+	if args.From < 0 || args.To < args.From {
+		return []*SkippedTransaction{}, nil
+	}
+	if args.To-args.From >= maxSkippedTransactionsRange {
+		return nil, fmt.Errorf("skipped transaction range too large: %d, maximum %d", args.To-args.From+1, maxSkippedTransactionsRange)
+	}
+	db := r.backend.ChainDb()
+	it := rawdb.IterateSkippedTransactionsFrom(db, uint64(args.From))
+	defer it.Release()
+
+	ret := []*SkippedTransaction{}
+	for it.Next() && it.Index() <= uint64(args.To) {
+		stx := rawdb.ReadSkippedTransaction(db, it.TransactionHash())
+		if stx == nil {
+			return nil, fmt.Errorf("skipped transaction %d (%x) missing", it.Index(), it.TransactionHash())
+		}
+		ret = append(ret, &SkippedTransaction{backend: r.backend, index: it.Index(), stx: stx})
+	}
+	return ret, nil
+}
+
+func (r *Resolver) Batch(ctx context.Context, args struct{ Index Long }) (*Batch, error) {
+	if args.Index < 0 {
+		return nil, nil
+	}
+	db := r.backend.ChainDb()
+	chunks := rawdb.ReadBatchChunkRanges(db, uint64(args.Index))
+	if len(chunks) == 0 {
+		return nil, nil
+	}
+	return &Batch{
+		backend: r.backend,
+		index:   uint64(args.Index),
+		chunks:  chunks,
+		meta:    rawdb.ReadFinalizedBatchMeta(db, uint64(args.Index)),
+	}, nil
+}
+
 func (r *Resolver) SendRawTransaction(ctx context.Context, args struct{ Data hexutil.Bytes }) (common.Hash, error) {
 	tx := new(types.Transaction)
 	if err := tx.UnmarshalBinary(args.Data); err != nil {
@@ -28,6 +28,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/consensus/ethash"
 	"github.com/scroll-tech/go-ethereum/core"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
 	"github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/core/vm"
 	"github.com/scroll-tech/go-ethereum/crypto"
@@ -197,6 +198,94 @@ func TestGraphQLBlockSerializationEIP2718(t *testing.T) {
 	}
 }
 
+func TestGraphQLRollupData(t *testing.T) {
+	stack := createNode(t, false, false)
+	defer stack.Close()
+	ethBackend := createGQLService(t, stack)
+	if err := stack.Start(); err != nil {
+		t.Fatalf("could not start node: %v", err)
+	}
+
+	// Populate the rollup data as the sync services would
+	var (
+		db      = ethBackend.ChainDb()
+		block1  = ethBackend.BlockChain().GetBlockByNumber(1)
+		target  = common.Address{2}
+		l1Msg   = types.L1MessageTx{QueueIndex: 3, Gas: 21000, To: &target, Value: big.NewInt(7), Data: []byte{0x01}, Sender: common.Address{1}}
+		skipped = types.NewTx(&types.LegacyTx{Nonce: 1, Gas: 21000, GasPrice: big.NewInt(1)})
+	)
+	rawdb.WriteL1Message(db, l1Msg)
+	rawdb.WriteSkippedTransaction(db, skipped, nil, "row consumption overflow", 4, nil)
+	rawdb.WriteBlockRowConsumption(db, block1.Hash(), &types.RowConsumption{{Name: "evm", RowNumber: 42}})
+	rawdb.WriteBatchChunkRanges(db, 0, []*rawdb.ChunkBlockRange{{StartBlockNumber: 0, EndBlockNumber: 0}})
+	rawdb.WriteBatchChunkRanges(db, 1, []*rawdb.ChunkBlockRange{{StartBlockNumber: 1, EndBlockNumber: 2}, {StartBlockNumber: 3, EndBlockNumber: 4}})
+	rawdb.WriteBatchChunkRanges(db, 2, []*rawdb.ChunkBlockRange{{StartBlockNumber: 5, EndBlockNumber: 6}})
+	rawdb.WriteFinalizedBatchMeta(db, 1, &rawdb.FinalizedBatchMeta{BatchHash: common.Hash{1}, TotalL1MessagePopped: 4, StateRoot: common.Hash{2}, WithdrawRoot: common.Hash{3}})
+	rawdb.WriteFinalizedL2BlockNumber(db, 4)
+
+	for i, tt := range []struct {
+		body string
+		want string
+	}{
+		{
+			body: `{"query": "{block(number:1){rowConsumption{name rowNumber} batchIndex finalized}}"}`,
+			want: `{"data":{"block":{"rowConsumption":[{"name":"evm","rowNumber":42}],"batchIndex":1,"finalized":true}}}`,
+		},
+		{
+			body: `{"query": "{block(number:6){rowConsumption{name} batchIndex finalized}}"}`,
+			want: `{"data":{"block":{"rowConsumption":null,"batchIndex":2,"finalized":false}}}`,
+		},
+		{
+			body: `{"query": "{block(number:8){batchIndex finalized}}"}`,
+			want: `{"data":{"block":{"batchIndex":null,"finalized":false}}}`,
+		},
+		{
+			body: `{"query": "{l1Message(queueIndex:3){queueIndex sender target value gas data transaction{hash}}}"}`,
+			want: `{"data":{"l1Message":{"queueIndex":3,"sender":"0x0100000000000000000000000000000000000000","target":"0x0200000000000000000000000000000000000000","value":"0x7","gas":21000,"data":"0x01","transaction":null}}}`,
+		},
+		{
+			body: `{"query": "{l1Message(queueIndex:4){queueIndex}}"}`,
+			want: `{"data":{"l1Message":null}}`,
+		},
+		{
+			body: `{"query": "{skippedTransactions(from:0,to:5){index reason blockNumber blockHash transaction{hash nonce queueIndex l1Sender}}}"}`,
+			want: fmt.Sprintf(`{"data":{"skippedTransactions":[{"index":0,"reason":"row consumption overflow","blockNumber":4,"blockHash":null,"transaction":{"hash":"%v","nonce":"0x1","queueIndex":null,"l1Sender":null}}]}}`, skipped.Hash().Hex()),
+		},
+		{
+			body: `{"query": "{skippedTransactions(from:1,to:5){index}}"}`,
+			want: `{"data":{"skippedTransactions":[]}}`,
+		},
+		{
+			body: `{"query": "{skippedTransactions(from:0,to:1000){index}}"}`,
+			want: `{"errors":[{"message":"skipped transaction range too large: 1001, maximum 1000","path":["skippedTransactions"]}],"data":null}`,
+		},
+		{
+			body: `{"query": "{batch(index:1){index startBlock{number} endBlock{number} chunkCount finalized hash totalL1MessagePopped}}"}`,
+			want: `{"data":{"batch":{"index":1,"startBlock":{"number":1},"endBlock":{"number":4},"chunkCount":2,"finalized":true,"hash":"0x0100000000000000000000000000000000000000000000000000000000000000","totalL1MessagePopped":4}}}`,
+		},
+		{
+			body: `{"query": "{batch(index:2){chunkCount finalized hash stateRoot withdrawRoot}}"}`,
+			want: `{"data":{"batch":{"chunkCount":1,"finalized":false,"hash":null,"stateRoot":null,"withdrawRoot":null}}}`,
+		},
+		{
+			body: `{"query": "{batch(index:3){index}}"}`,
+			want: `{"data":{"batch":null}}`,
+		},
+	} {
+		resp, err := http.Post(fmt.Sprintf("%s/graphql", stack.HTTPEndpoint()), "application/json", strings.NewReader(tt.body))
+		if err != nil {
+			t.Fatalf("could not post: %v", err)
+		}
+		bodyBytes, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("could not read from response body: %v", err)
+		}
+		if have := string(bodyBytes); have != tt.want {
+			t.Errorf("testcase %d %s,\nhave:\n%v\nwant:\n%v", i, tt.body, have, tt.want)
+		}
+	}
+}
+
 // Tests that a graphQL request is not handled successfully when graphql is not enabled on the specified endpoint
 func TestGraphQLHTTPOnSamePort_GQLRequest_Unsuccessful(t *testing.T) {
 	stack := createNode(t, false, false)
@@ -234,7 +323,7 @@ func createNode(t *testing.T, gqlEnabled bool, txEnabled bool) *node.Node {
 	return stack
 }
 
-func createGQLService(t *testing.T, stack *node.Node) {
+func createGQLService(t *testing.T, stack *node.Node) *eth.Ethereum {
 	// create backend
 	ethConf := &ethconfig.Config{
 		Genesis: &core.Genesis{
@@ -269,6 +358,7 @@ func createGQLService(t *testing.T, stack *node.Node) {
 	if err != nil {
 		t.Fatalf("could not create graphql service: %v", err)
 	}
+	return ethBackend
 }
 
 func createGQLServiceWithTransactions(t *testing.T, stack *node.Node) {
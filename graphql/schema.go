@@ -138,6 +138,15 @@ const schema string = `
         #Envelope transaction support
         type: Int
         accessList: [AccessTuple!]
+        # QueueIndex is the index of the L1 message in the L1 message queue. If
+        # the transaction is not an L1 message, this field will be null.
+        queueIndex: Long
+        # L1Sender is the address that enqueued the L1 message on L1. If the
+        # transaction is not an L1 message, this field will be null.
+        l1Sender: Address
+        # L1Fee is the fee paid for posting this transaction's data to L1. If
+        # the transaction has not yet been mined, this field will be null.
+        l1Fee: BigInt
     }
 
     # BlockFilterCriteria encapsulates log filter criteria for a filter applied
@@ -231,6 +240,85 @@ const schema string = `
         # EstimateGas estimates the amount of gas that will be required for
         # successful execution of a transaction at the current block's state.
         estimateGas(data: CallData!): Long!
+        # RowConsumption is the number of circuit rows used by this block, per
+        # sub-circuit. If it is unavailable, this field will be null.
+        rowConsumption: [SubCircuitRowUsage!]
+        # BatchIndex is the index of the rollup batch this block was committed
+        # in. If the block is not part of a committed batch, this field will be null.
+        batchIndex: Long
+        # Finalized is true if this block is part of a batch finalized on L1.
+        finalized: Boolean!
+    }
+
+    # SubCircuitRowUsage is the number of rows a block uses in a sub-circuit.
+    type SubCircuitRowUsage {
+        # Name is the name of the sub-circuit.
+        name: String!
+        # RowNumber is the number of rows used in the sub-circuit.
+        rowNumber: Long!
+    }
+
+    # L1Message is a message enqueued on L1 to be included in L2.
+    type L1Message {
+        # QueueIndex is the index of the message in the L1 message queue.
+        queueIndex: Long!
+        # Hash is the hash of the L1 message transaction.
+        hash: Bytes32!
+        # Sender is the address that enqueued the message on L1.
+        sender: Address!
+        # Target is the L2 recipient of the message, or null for contract creation.
+        target: Address
+        # Value is the value, in wei, transferred by the message.
+        value: BigInt!
+        # Gas is the gas limit of the message.
+        gas: Long!
+        # Data is the calldata of the message.
+        data: Bytes!
+        # Transaction is the L2 transaction of the message. If the message has not
+        # yet been included in a block, this field will be null.
+        transaction: Transaction
+    }
+
+    # SkippedTransaction is a transaction skipped by the sequencer, e.g.
+    # because it exceeded the circuit capacity.
+    type SkippedTransaction {
+        # Index is the index of the transaction in the skipped transaction list.
+        index: Long!
+        # Transaction is the skipped transaction.
+        transaction: Transaction!
+        # Reason is the reason why the transaction was skipped.
+        reason: String!
+        # BlockNumber is the number of the block the transaction was skipped in.
+        blockNumber: Long!
+        # BlockHash is the hash of the block the transaction was skipped in, if known.
+        blockHash: Bytes32
+    }
+
+    # Batch is a batch of L2 blocks committed to L1.
+    type Batch {
+        # Index is the index of the batch.
+        index: Long!
+        # StartBlock is the first block of the batch.
+        startBlock: Block!
+        # EndBlock is the last block of the batch.
+        endBlock: Block!
+        # ChunkCount is the number of chunks in the batch.
+        chunkCount: Int!
+        # Finalized is true if the batch has been finalized on L1.
+        finalized: Boolean!
+        # Hash is the hash of the batch. If the batch has not yet been
+        # finalized, this field will be null.
+        hash: Bytes32
+        # TotalL1MessagePopped is the total number of L1 messages popped before
+        # and in this batch. If the batch has not yet been finalized, this field
+        # will be null.
+        totalL1MessagePopped: Long
+        # StateRoot is the state root after the batch. If the batch has not yet
+        # been finalized, this field will be null.
+        stateRoot: Bytes32
+        # WithdrawRoot is the withdraw trie root after the batch. If the batch
+        # has not yet been finalized, this field will be null.
+        withdrawRoot: Bytes32
     }
 
     # CallData represents the data associated with a local contract call.
@@ -343,6 +431,13 @@ const schema string = `
         syncing: SyncState
         # ChainID returns the current chain ID for transaction replay protection.
         chainID: BigInt!
+        # L1Message returns the L1 message with the given queue index.
+        l1Message(queueIndex: Long!): L1Message
+        # SkippedTransactions returns the skipped transactions with indices
+        # between from and to, inclusive. At most 1000 indices can be queried.
+        skippedTransactions(from: Long!, to: Long!): [SkippedTransaction!]!
+        # Batch returns the committed batch with the given index.
+        batch(index: Long!): Batch
     }
 
     type Mutation {
@@ -0,0 +1,184 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+const (
+	// defaultAccountHistoryLimit is the number of transactions of a page of the
+	// history of an account, unless specified.
+	defaultAccountHistoryLimit = 100
+
+	// maxAccountHistoryLimit is the maximum number of transactions of a page of
+	// the history of an account.
+	maxAccountHistoryLimit = 1000
+)
+
+// accountRoles are the names of the roles of an account in a transaction.
+var accountRoles = []struct {
+	role uint8
+	name string
+}{
+	{rawdb.AccountRoleFrom, "from"},
+	{rawdb.AccountRoleTo, "to"},
+	{rawdb.AccountRoleCreated, "create"},
+	{rawdb.AccountRoleInternal, "internal"},
+}
+
+// AccountHistoryArgs selects a page of the transactions touching an account,
+// which are listed from the newest to the oldest.
+type AccountHistoryArgs struct {
+	FromBlock *rpc.BlockNumber      `json:"fromBlock"` // Oldest block, the genesis by default
+	ToBlock   *rpc.BlockNumber      `json:"toBlock"`   // Newest block, the latest by default
+	Cursor    *AccountHistoryCursor `json:"cursor"`    // Start of the page, overriding ToBlock
+	Limit     *hexutil.Uint64       `json:"limit"`     // Maximum number of transactions of the page
+}
+
+// AccountHistoryCursor is the position of the first transaction of a page of
+// the history of an account.
+type AccountHistoryCursor struct {
+	BlockNumber      hexutil.Uint64 `json:"blockNumber"`
+	TransactionIndex hexutil.Uint64 `json:"transactionIndex"`
+}
+
+// AccountTransaction is a transaction touching an account, along with the roles
+// of the account in the transaction.
+type AccountTransaction struct {
+	BlockHash        common.Hash    `json:"blockHash"`
+	BlockNumber      hexutil.Uint64 `json:"blockNumber"`
+	TransactionHash  common.Hash    `json:"transactionHash"`
+	TransactionIndex hexutil.Uint64 `json:"transactionIndex"`
+	Roles            []string       `json:"roles"`
+}
+
+// UntracedBlockRange is a range of blocks, inclusive, whose internal calls are
+// missing from the account history.
+type UntracedBlockRange struct {
+	FromBlock hexutil.Uint64 `json:"fromBlock"`
+	ToBlock   hexutil.Uint64 `json:"toBlock"`
+}
+
+// AccountHistory is a page of the transactions touching an account.
+type AccountHistory struct {
+	Transactions     []*AccountTransaction `json:"transactions"`
+	Next             *AccountHistoryCursor `json:"next"`             // Cursor of the next page, nil on the last page
+	LastIndexedBlock *hexutil.Uint64       `json:"lastIndexedBlock"` // Newer blocks are not indexed yet
+	UntracedBlocks   []*UntracedBlockRange `json:"untracedBlocks"`   // Blocks of the page indexed without internal calls, state unavailable
+}
+
+// GetTransactionsByAddress returns a page of the transactions of the canonical
+// chain touching the given account, from the newest to the oldest: the ones it
+// sends or receives, the contract creating it and the ones with internal calls
+// from or to it. The following pages are retrieved with the cursor returned
+// along with the page. Requires the account history to be indexed. The internal
+// calls of the blocks whose state was unavailable when indexed are missing, and
+// these blocks are returned along with the page.
+func (s *PublicTransactionPoolAPI) GetTransactionsByAddress(ctx context.Context, address common.Address, args *AccountHistoryArgs) (*AccountHistory, error) {
+	enabled, indexed := s.b.AccountHistoryStatus()
+	if !enabled {
+		return nil, errors.New("account history not indexed")
+	}
+	if args == nil {
+		args = new(AccountHistoryArgs)
+	}
+	limit := uint64(defaultAccountHistoryLimit)
+	if args.Limit != nil {
+		limit = uint64(*args.Limit)
+		if limit == 0 || limit > maxAccountHistoryLimit {
+			return nil, fmt.Errorf("invalid limit %d, must be between 1 and %d", limit, maxAccountHistoryLimit)
+		}
+	}
+	var (
+		oldest  uint64
+		number  uint64 = math.MaxUint64
+		txIndex uint32 = math.MaxUint32
+		err     error
+	)
+	if args.FromBlock != nil {
+		if oldest, err = s.resolveBlockNumber(ctx, *args.FromBlock); err != nil {
+			return nil, err
+		}
+	}
+	if args.ToBlock != nil {
+		if number, err = s.resolveBlockNumber(ctx, *args.ToBlock); err != nil {
+			return nil, err
+		}
+	}
+	if args.Cursor != nil {
+		if args.Cursor.TransactionIndex > math.MaxUint32 {
+			return nil, fmt.Errorf("invalid cursor transaction index %d", args.Cursor.TransactionIndex)
+		}
+		number, txIndex = uint64(args.Cursor.BlockNumber), uint32(args.Cursor.TransactionIndex)
+	}
+	// Read one more transaction than requested to find the start of the next page
+	entries := rawdb.ReadAccountHistory(s.b.ChainDb(), address, number, txIndex, oldest, int(limit)+1)
+
+	history := &AccountHistory{Transactions: make([]*AccountTransaction, 0, len(entries))}
+	if indexed > 0 {
+		last := hexutil.Uint64(indexed - 1)
+		history.LastIndexedBlock = &last
+	}
+	if uint64(len(entries)) > limit {
+		next := entries[limit]
+		history.Next = &AccountHistoryCursor{
+			BlockNumber:      hexutil.Uint64(next.BlockNumber),
+			TransactionIndex: hexutil.Uint64(next.TxIndex),
+		}
+		entries = entries[:limit]
+	}
+	// The page spans the blocks from its start to the next page, or to the
+	// oldest block on the last page
+	first, last := oldest, number
+	if history.Next != nil {
+		first = uint64(history.Next.BlockNumber)
+	}
+	if history.LastIndexedBlock != nil && last > uint64(*history.LastIndexedBlock) {
+		last = uint64(*history.LastIndexedBlock)
+	}
+	history.UntracedBlocks = []*UntracedBlockRange{}
+	if first <= last {
+		for _, r := range rawdb.ReadUntracedBlockRanges(s.b.ChainDb(), first, last) {
+			history.UntracedBlocks = append(history.UntracedBlocks, &UntracedBlockRange{
+				FromBlock: hexutil.Uint64(r.First),
+				ToBlock:   hexutil.Uint64(r.Last),
+			})
+		}
+	}
+	for _, entry := range entries {
+		tx := &AccountTransaction{
+			BlockHash:        entry.BlockHash,
+			BlockNumber:      hexutil.Uint64(entry.BlockNumber),
+			TransactionHash:  entry.TxHash,
+			TransactionIndex: hexutil.Uint64(entry.TxIndex),
+			Roles:            []string{},
+		}
+		for _, role := range accountRoles {
+			if entry.Roles&role.role != 0 {
+				tx.Roles = append(tx.Roles, role.name)
+			}
+		}
+		history.Transactions = append(history.Transactions, tx)
+	}
+	return history, nil
+}
+
+// resolveBlockNumber returns the number of the block with the given number or
+// tag.
+func (s *PublicTransactionPoolAPI) resolveBlockNumber(ctx context.Context, number rpc.BlockNumber) (uint64, error) {
+	header, err := s.b.HeaderByNumber(ctx, number)
+	if err != nil {
+		return 0, err
+	}
+	if header == nil {
+		return 0, fmt.Errorf("block %d not found", number.Int64())
+	}
+	return header.Number.Uint64(), nil
+}
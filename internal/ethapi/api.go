@@ -154,8 +154,9 @@ func NewPublicTxPoolAPI(b Backend) *PublicTxPoolAPI {
 // Content returns the transactions contained within the transaction pool.
 func (s *PublicTxPoolAPI) Content() map[string]map[string]map[string]*RPCTransaction {
 	content := map[string]map[string]map[string]*RPCTransaction{
-		"pending": make(map[string]map[string]*RPCTransaction),
-		"queued":  make(map[string]map[string]*RPCTransaction),
+		"pending":    make(map[string]map[string]*RPCTransaction),
+		"queued":     make(map[string]map[string]*RPCTransaction),
+		"overflowed": make(map[string]map[string]*RPCTransaction),
 	}
 	pending, queue := s.b.TxPoolContent()
 	curHeader := s.b.CurrentHeader()
@@ -184,6 +185,18 @@ func (s *PublicTxPoolAPI) Content() map[string]map[string]map[string]*RPCTransac
 		}
 		content["queued"][account.Hex()] = dump
 	}
+	// Flatten the transactions evicted for exceeding the circuit capacity, by
+	// hash as several replacements of the same nonce may have been evicted
+	for _, overflowed := range s.b.TxPoolCircuitOverflowed() {
+		dump, ok := content["overflowed"][overflowed.From.Hex()]
+		if !ok {
+			dump = make(map[string]*RPCTransaction)
+			content["overflowed"][overflowed.From.Hex()] = dump
+		}
+		rpcTx := newRPCPendingTransaction(overflowed.Tx, curHeader, s.b.ChainConfig(), l1BaseFee)
+		rpcTx.CircuitOverflowReason = overflowed.Reason
+		dump[overflowed.Tx.Hash().Hex()] = rpcTx
+	}
 	return content
 }
 
@@ -231,8 +244,9 @@ func (s *PublicTxPoolAPI) Status() map[string]hexutil.Uint {
 // easily inspectable list.
 func (s *PublicTxPoolAPI) Inspect() map[string]map[string]map[string]string {
 	content := map[string]map[string]map[string]string{
-		"pending": make(map[string]map[string]string),
-		"queued":  make(map[string]map[string]string),
+		"pending":    make(map[string]map[string]string),
+		"queued":     make(map[string]map[string]string),
+		"overflowed": make(map[string]map[string]string),
 	}
 	pending, queue := s.b.TxPoolContent()
 
@@ -259,6 +273,16 @@ func (s *PublicTxPoolAPI) Inspect() map[string]map[string]map[string]string {
 		}
 		content["queued"][account.Hex()] = dump
 	}
+	// Flatten the transactions evicted for exceeding the circuit capacity, by
+	// hash as several replacements of the same nonce may have been evicted
+	for _, overflowed := range s.b.TxPoolCircuitOverflowed() {
+		dump, ok := content["overflowed"][overflowed.From.Hex()]
+		if !ok {
+			dump = make(map[string]string)
+			content["overflowed"][overflowed.From.Hex()] = dump
+		}
+		dump[overflowed.Tx.Hash().Hex()] = fmt.Sprintf("%s (%s)", format(overflowed.Tx), overflowed.Reason)
+	}
 	return content
 }
 
@@ -911,16 +935,65 @@ func (diff *StateOverride) Apply(state *state.StateDB) error {
 	return nil
 }
 
-func EstimateL1MsgFee(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, timeout time.Duration, globalGasCap uint64, config *params.ChainConfig) (*big.Int, error) {
+// L1FeeOverride indicates the L1GasPriceOracle values to use instead of the
+// ones stored in the oracle contract during the execution of a message call.
+type L1FeeOverride struct {
+	L1BaseFee     *hexutil.Big `json:"l1BaseFee"`
+	Overhead      *hexutil.Big `json:"overhead"`
+	Scalar        *hexutil.Big `json:"scalar"`
+	L1BlobBaseFee *hexutil.Big `json:"l1BlobBaseFee"`
+	CommitScalar  *hexutil.Big `json:"commitScalar"`
+	BlobScalar    *hexutil.Big `json:"blobScalar"`
+}
+
+// ToGPOOverride converts the RPC override into its rollup fee representation.
+func (o *L1FeeOverride) ToGPOOverride() *fees.GPOOverride {
+	if o == nil {
+		return nil
+	}
+	return &fees.GPOOverride{
+		L1BaseFee:     (*big.Int)(o.L1BaseFee),
+		Overhead:      (*big.Int)(o.Overhead),
+		Scalar:        (*big.Int)(o.Scalar),
+		L1BlobBaseFee: (*big.Int)(o.L1BlobBaseFee),
+		CommitScalar:  (*big.Int)(o.CommitScalar),
+		BlobScalar:    (*big.Int)(o.BlobScalar),
+	}
+}
+
+// applyOverrides applies the state overrides followed by the L1 fee overrides,
+// so that the latter take precedence over oracle storage given in the former.
+func applyOverrides(state *state.StateDB, overrides *StateOverride, l1FeeOverrides *L1FeeOverride) error {
+	if err := overrides.Apply(state); err != nil {
+		return err
+	}
+	l1FeeOverrides.ToGPOOverride().Apply(state)
+	return nil
+}
+
+func EstimateL1MsgFee(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, l1FeeOverrides *L1FeeOverride, timeout time.Duration, globalGasCap uint64, config *params.ChainConfig) (*big.Int, error) {
 	if !config.Scroll.FeeVaultEnabled() {
 		return big.NewInt(0), nil
 	}
+	breakdown, err := EstimateL1MsgFeeBreakdown(ctx, b, args, blockNrOrHash, overrides, l1FeeOverrides, timeout, globalGasCap, config)
+	if err != nil {
+		return nil, err
+	}
+	return breakdown.L1DataFee, nil
+}
 
+// EstimateL1MsgFeeBreakdown estimates the L1 data fee of the given call and
+// returns its commit and blob components along with the oracle values used.
+func EstimateL1MsgFeeBreakdown(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, l1FeeOverrides *L1FeeOverride, timeout time.Duration, globalGasCap uint64, config *params.ChainConfig) (*fees.L1DataFeeBreakdown, error) {
 	state, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
 	if state == nil || err != nil {
 		return nil, err
 	}
-	if err := overrides.Apply(state); err != nil {
+	// Without the fee vault no L1 data fee is charged, like in the state transition
+	if !config.Scroll.FeeVaultEnabled() {
+		return fees.ZeroL1DataFeeBreakdown(config, header.Number), nil
+	}
+	if err := applyOverrides(state, overrides, l1FeeOverrides); err != nil {
 		return nil, err
 	}
 	// Setup context so it may be cancelled the call has completed
@@ -952,17 +1025,17 @@ func EstimateL1MsgFee(ctx context.Context, b Backend, args TransactionArgs, bloc
 	}()
 
 	signer := types.MakeSigner(config, header.Number)
-	return fees.EstimateL1DataFeeForMessage(msg, header.BaseFee, config, signer, evm.StateDB, header.Number)
+	return fees.EstimateL1DataFeeBreakdownForMessage(msg, header.BaseFee, config, signer, evm.StateDB, header.Number)
 }
 
-func DoCall(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, timeout time.Duration, globalGasCap uint64) (*core.ExecutionResult, error) {
+func DoCall(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, l1FeeOverrides *L1FeeOverride, timeout time.Duration, globalGasCap uint64) (*core.ExecutionResult, error) {
 	defer func(start time.Time) { log.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
 
 	state, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
 	if state == nil || err != nil {
 		return nil, err
 	}
-	if err := overrides.Apply(state); err != nil {
+	if err := applyOverrides(state, overrides, l1FeeOverrides); err != nil {
 		return nil, err
 	}
 	// Setup context so it may be cancelled the call has completed
@@ -1043,12 +1116,13 @@ func (e *revertError) ErrorData() interface{} {
 
 // Call executes the given transaction on the state for the given block number.
 //
-// Additionally, the caller can specify a batch of contract for fields overriding.
+// Additionally, the caller can specify a batch of contract for fields overriding,
+// as well as the L1GasPriceOracle values to use.
 //
 // Note, this function doesn't make and changes in the state/blockchain and is
 // useful to execute and retrieve values.
-func (s *PublicBlockChainAPI) Call(ctx context.Context, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride) (hexutil.Bytes, error) {
-	result, err := DoCall(ctx, s.b, args, blockNrOrHash, overrides, s.b.RPCEVMTimeout(), s.b.RPCGasCap())
+func (s *PublicBlockChainAPI) Call(ctx context.Context, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, l1FeeOverrides *L1FeeOverride) (hexutil.Bytes, error) {
+	result, err := DoCall(ctx, s.b, args, blockNrOrHash, overrides, l1FeeOverrides, s.b.RPCEVMTimeout(), s.b.RPCGasCap())
 	if err != nil {
 		return nil, err
 	}
@@ -1059,7 +1133,7 @@ func (s *PublicBlockChainAPI) Call(ctx context.Context, args TransactionArgs, bl
 	return result.Return(), result.Err
 }
 
-func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, gasCap uint64) (hexutil.Uint64, error) {
+func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, l1FeeOverrides *L1FeeOverride, gasCap uint64) (hexutil.Uint64, error) {
 	// Binary search the gas requirement, as it may be higher than the amount used
 	var (
 		lo  uint64 = params.TxGas - 1
@@ -1101,6 +1175,9 @@ func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNr
 		if err != nil {
 			return 0, err
 		}
+		if err := applyOverrides(state, overrides, l1FeeOverrides); err != nil {
+			return 0, err
+		}
 		balance := state.GetBalance(*args.From) // from can't be nil
 		available := new(big.Int).Set(balance)
 
@@ -1113,7 +1190,7 @@ func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNr
 		}
 
 		// account for l1 fee
-		l1DataFee, err := EstimateL1MsgFee(ctx, b, args, blockNrOrHash, nil, 0, gasCap, b.ChainConfig())
+		l1DataFee, err := EstimateL1MsgFee(ctx, b, args, blockNrOrHash, overrides, l1FeeOverrides, 0, gasCap, b.ChainConfig())
 		if err != nil {
 			return 0, err
 		}
@@ -1146,7 +1223,7 @@ func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNr
 	executable := func(gas uint64) (bool, *core.ExecutionResult, error) {
 		args.Gas = (*hexutil.Uint64)(&gas)
 
-		result, err := DoCall(ctx, b, args, blockNrOrHash, nil, 0, gasCap)
+		result, err := DoCall(ctx, b, args, blockNrOrHash, overrides, l1FeeOverrides, 0, gasCap)
 		if err != nil {
 			if errors.Is(err, core.ErrIntrinsicGas) {
 				return true, nil, nil // Special case, raise gas limit
@@ -1193,13 +1270,14 @@ func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNr
 }
 
 // EstimateGas returns an estimate of the amount of gas needed to execute the
-// given transaction against the current pending block.
-func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args TransactionArgs, blockNrOrHash *rpc.BlockNumberOrHash) (hexutil.Uint64, error) {
+// given transaction against the current pending block. State and L1 fee
+// overrides are applied before estimating.
+func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args TransactionArgs, blockNrOrHash *rpc.BlockNumberOrHash, overrides *StateOverride, l1FeeOverrides *L1FeeOverride) (hexutil.Uint64, error) {
 	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber)
 	if blockNrOrHash != nil {
 		bNrOrHash = *blockNrOrHash
 	}
-	return DoEstimateGas(ctx, s.b, args, bNrOrHash, s.b.RPCGasCap())
+	return DoEstimateGas(ctx, s.b, args, bNrOrHash, overrides, l1FeeOverrides, s.b.RPCGasCap())
 }
 
 // RPCMarshalHeader converts the given header to the RPC output .
@@ -1313,6 +1391,9 @@ type RPCTransaction struct {
 	// L1 message transaction fields:
 	Sender     *common.Address `json:"sender,omitempty"`
 	QueueIndex *hexutil.Uint64 `json:"queueIndex,omitempty"`
+
+	// Set for transactions evicted from the pool for exceeding the circuit capacity
+	CircuitOverflowReason string `json:"circuitOverflowReason,omitempty"`
 }
 
 // NewRPCTransaction returns a transaction that will serialize to the RPC
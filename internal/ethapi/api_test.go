@@ -0,0 +1,106 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/core"
+	"github.com/scroll-tech/go-ethereum/core/state"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+// Tests that the L1 data fee is only estimated on chains charging it, i.e. with
+// the fee vault enabled.
+func TestEstimateL1MsgFeeBreakdown(t *testing.T) {
+	data := hexutil.Bytes(make([]byte, 100))
+	args := TransactionArgs{From: &testAddr, To: &emptyAddr, Data: &data}
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+
+	tests := []struct {
+		config  *params.ChainConfig
+		charged bool
+	}{
+		{params.TestChainConfig, true},
+		{params.AllEthashProtocolChanges, false},
+	}
+	for i, tt := range tests {
+		b := newTestBackend(t, tt.config)
+		breakdown, err := EstimateL1MsgFeeBreakdown(context.Background(), b, args, latest, nil, nil, 0, b.RPCGasCap(), tt.config)
+		if err != nil {
+			t.Fatalf("test %d: failed to estimate L1 data fee: %v", i, err)
+		}
+		if charged := breakdown.L1DataFee.Sign() > 0; charged != tt.charged {
+			t.Errorf("test %d: L1 data fee %v, want charged %v", i, breakdown.L1DataFee, tt.charged)
+		}
+		fee, err := EstimateL1MsgFee(context.Background(), b, args, latest, nil, nil, 0, b.RPCGasCap(), tt.config)
+		if err != nil {
+			t.Fatalf("test %d: failed to estimate L1 data fee: %v", i, err)
+		}
+		if fee.Cmp(breakdown.L1DataFee) != 0 {
+			t.Errorf("test %d: breakdown fee %v mismatches fee %v", i, breakdown.L1DataFee, fee)
+		}
+	}
+}
+
+// txPoolTestBackend serves a transaction pool holding only evicted transactions.
+type txPoolTestBackend struct {
+	*testBackend
+	overflowed []*core.CircuitOverflowTx
+}
+
+func (b *txPoolTestBackend) CurrentHeader() *types.Header { return b.chain.CurrentHeader() }
+func (b *txPoolTestBackend) StateAt(root common.Hash) (*state.StateDB, error) {
+	return b.chain.StateAt(root)
+}
+func (b *txPoolTestBackend) TxPoolContent() (map[common.Address]types.Transactions, map[common.Address]types.Transactions) {
+	return nil, nil
+}
+func (b *txPoolTestBackend) TxPoolCircuitOverflowed() []*core.CircuitOverflowTx { return b.overflowed }
+
+// Tests that the replacements of a transaction evicted for exceeding the circuit
+// capacity are all listed.
+func TestTxPoolOverflowed(t *testing.T) {
+	b := &txPoolTestBackend{testBackend: newTestBackend(t, params.AllEthashProtocolChanges)}
+	signer := types.HomesteadSigner{}
+	for _, price := range []int64{params.InitialBaseFee, 2 * params.InitialBaseFee} {
+		tx, _ := types.SignTx(types.NewTransaction(0, emptyAddr, common.Big0, params.TxGas, big.NewInt(price), nil), signer, testKey)
+		b.overflowed = append(b.overflowed, &core.CircuitOverflowTx{Tx: tx, From: testAddr, Reason: "row consumption overflow"})
+	}
+	api := NewPublicTxPoolAPI(b)
+
+	content := api.Content()["overflowed"][testAddr.Hex()]
+	inspect := api.Inspect()["overflowed"][testAddr.Hex()]
+	if len(content) != 2 || len(inspect) != 2 {
+		t.Fatalf("wrong number of overflowed txs: content %d, inspect %d, want 2", len(content), len(inspect))
+	}
+	for _, overflowed := range b.overflowed {
+		hash := overflowed.Tx.Hash()
+		if tx := content[hash.Hex()]; tx == nil || tx.Hash != hash || tx.CircuitOverflowReason != overflowed.Reason {
+			t.Errorf("tx %x: wrong content %+v", hash, tx)
+		}
+		if _, ok := inspect[hash.Hex()]; !ok {
+			t.Errorf("tx %x: missing from inspection", hash)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/core"
+	"github.com/scroll-tech/go-ethereum/core/state"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/core/vm"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rollup/fees"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+// maxSimulateBlocks is the maximum number of blocks a single simulation
+// request may span.
+const maxSimulateBlocks = 256
+
+// BlockOverrides is a set of header fields to override when simulating calls,
+// along with the L1GasPriceOracle values to use.
+type BlockOverrides struct {
+	Number   *hexutil.Big    `json:"number"`
+	Time     *hexutil.Uint64 `json:"time"`
+	GasLimit *hexutil.Uint64 `json:"gasLimit"`
+	Coinbase *common.Address `json:"coinbase"`
+	BaseFee  *hexutil.Big    `json:"baseFee"`
+	L1Fee    *L1FeeOverride  `json:"l1Fee"`
+}
+
+// makeHeader returns the header of a block simulated on top of the given
+// parent. Fields that are not overridden default to the next block number, a
+// timestamp one block period after the parent and the parent's gas limit and
+// base fee.
+func (o *BlockOverrides) makeHeader(parent *types.Header, period uint64) *types.Header {
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Coinbase:   parent.Coinbase,
+		Difficulty: new(big.Int),
+		Number:     new(big.Int).Add(parent.Number, common.Big1),
+		GasLimit:   parent.GasLimit,
+		Time:       parent.Time + period,
+	}
+	if parent.BaseFee != nil {
+		header.BaseFee = new(big.Int).Set(parent.BaseFee)
+	}
+	if o == nil {
+		return header
+	}
+	if o.Number != nil {
+		header.Number = new(big.Int).Set(o.Number.ToInt())
+	}
+	if o.Time != nil {
+		header.Time = uint64(*o.Time)
+	}
+	if o.GasLimit != nil {
+		header.GasLimit = uint64(*o.GasLimit)
+	}
+	if o.Coinbase != nil {
+		header.Coinbase = *o.Coinbase
+	}
+	if o.BaseFee != nil {
+		header.BaseFee = new(big.Int).Set(o.BaseFee.ToInt())
+	}
+	return header
+}
+
+// SimulatedBlock is a block of calls to simulate in order.
+type SimulatedBlock struct {
+	BlockOverrides *BlockOverrides   `json:"blockOverrides"`
+	Calls          []TransactionArgs `json:"calls"`
+}
+
+// SimulatedCallResult is the result of a single simulated call.
+type SimulatedCallResult struct {
+	ReturnData hexutil.Bytes  `json:"returnData"`
+	Logs       []*types.Log   `json:"logs"`
+	GasUsed    hexutil.Uint64 `json:"gasUsed"`
+	L1DataFee  *hexutil.Big   `json:"l1DataFee"`
+	Error      string         `json:"error,omitempty"`
+}
+
+// SimulatedBlockResult is the result of a simulated block.
+type SimulatedBlockResult struct {
+	Number   hexutil.Uint64         `json:"number"`
+	Time     hexutil.Uint64         `json:"timestamp"`
+	GasLimit hexutil.Uint64         `json:"gasLimit"`
+	GasUsed  hexutil.Uint64         `json:"gasUsed"`
+	Coinbase common.Address         `json:"miner"`
+	BaseFee  *hexutil.Big           `json:"baseFeePerGas,omitempty"`
+	Calls    []*SimulatedCallResult `json:"calls"`
+}
+
+// DoSimulate executes the given blocks of calls in order on top of the state
+// of the given block. Every call sees the state changes of the calls before
+// it, and is charged its L1 data fee the same way as transactions included by
+// the sequencer. A call failing before execution, for example because of an
+// insufficient balance, fails the whole simulation.
+func DoSimulate(ctx context.Context, b Backend, blocks []SimulatedBlock, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, timeout time.Duration, globalGasCap uint64) ([]*SimulatedBlockResult, error) {
+	defer func(start time.Time) { log.Debug("Executing EVM simulation finished", "runtime", time.Since(start)) }(time.Now())
+
+	if len(blocks) == 0 {
+		return nil, errors.New("empty simulation")
+	}
+	if len(blocks) > maxSimulateBlocks {
+		return nil, fmt.Errorf("too many blocks: %d, limit %d", len(blocks), maxSimulateBlocks)
+	}
+	state, parent, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	if err := overrides.Apply(state); err != nil {
+		return nil, err
+	}
+	// Setup context so it may be cancelled the simulation has completed
+	// or, in case of unmetered gas, setup a context with a timeout.
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	var period uint64 = 1
+	if clique := b.ChainConfig().Clique; clique != nil && clique.Period > 0 {
+		period = clique.Period
+	}
+	results := make([]*SimulatedBlockResult, len(blocks))
+	for i, block := range blocks {
+		header := block.BlockOverrides.makeHeader(parent, period)
+		if header.Number.Cmp(parent.Number) <= 0 {
+			return nil, fmt.Errorf("block %d: number %v is not greater than parent number %v", i, header.Number, parent.Number)
+		}
+		if block.BlockOverrides != nil {
+			block.BlockOverrides.L1Fee.ToGPOOverride().Apply(state)
+		}
+		result, err := simulateBlock(ctx, b, state, header, block, block.BlockOverrides != nil && block.BlockOverrides.Coinbase != nil, timeout, globalGasCap)
+		if err != nil {
+			return nil, fmt.Errorf("block %d: %w", i, err)
+		}
+		results[i] = result
+		parent = header
+	}
+	return results, nil
+}
+
+// simulateBlock executes the calls of a simulated block in order.
+func simulateBlock(ctx context.Context, b Backend, state *state.StateDB, header *types.Header, block SimulatedBlock, overrideCoinbase bool, timeout time.Duration, globalGasCap uint64) (*SimulatedBlockResult, error) {
+	var (
+		gp     = new(core.GasPool).AddGas(header.GasLimit)
+		signer = types.MakeSigner(b.ChainConfig(), header.Number)
+		result = &SimulatedBlockResult{
+			Number:   hexutil.Uint64(header.Number.Uint64()),
+			Time:     hexutil.Uint64(header.Time),
+			GasLimit: hexutil.Uint64(header.GasLimit),
+			Coinbase: header.Coinbase,
+			Calls:    make([]*SimulatedCallResult, len(block.Calls)),
+		}
+	)
+	if header.BaseFee != nil {
+		result.BaseFee = (*hexutil.Big)(header.BaseFee)
+	}
+	for i, args := range block.Calls {
+		// Default to the remaining gas of the block and the current nonce of
+		// the sender, so that consecutive calls are told apart by their hash.
+		if args.Gas == nil {
+			gas := hexutil.Uint64(gp.Gas())
+			args.Gas = &gas
+		}
+		if args.Nonce == nil {
+			nonce := hexutil.Uint64(state.GetNonce(args.from()))
+			args.Nonce = &nonce
+		}
+		msg, err := args.ToMessage(globalGasCap, header.BaseFee)
+		if err != nil {
+			return nil, fmt.Errorf("call %d: %w", i, err)
+		}
+		evm, vmError, err := b.GetEVM(ctx, msg, state, header, &vm.Config{NoBaseFee: true})
+		if err != nil {
+			return nil, err
+		}
+		if overrideCoinbase {
+			evm.Context.Coinbase = header.Coinbase
+		}
+		result.Coinbase = evm.Context.Coinbase
+		// Wait for the context to be done and cancel the evm. Even if the
+		// EVM has finished, cancelling may be done (repeatedly)
+		go func() {
+			<-ctx.Done()
+			evm.Cancel()
+		}()
+
+		l1DataFee, err := fees.EstimateL1DataFeeForMessage(msg, header.BaseFee, b.ChainConfig(), signer, state, header.Number)
+		if err != nil {
+			return nil, fmt.Errorf("call %d: %w", i, err)
+		}
+		txHash := args.toTransaction().Hash()
+		state.SetTxContext(txHash, i)
+
+		res, err := core.ApplyMessage(evm, msg, gp, l1DataFee)
+		if err := vmError(); err != nil {
+			return nil, err
+		}
+		// If the timer caused an abort, return an appropriate error message
+		if evm.Cancelled() {
+			return nil, fmt.Errorf("execution aborted (timeout = %v)", timeout)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("call %d: %w (supplied gas %d)", i, err, msg.Gas())
+		}
+		// Finalise the call like the state processor does after every
+		// transaction, so that the next calls meter their storage changes
+		// and refunds against its results
+		state.Finalise(true)
+
+		logs := state.GetLogs(txHash, common.Hash{})
+		for _, l := range logs {
+			l.BlockNumber = header.Number.Uint64()
+		}
+		call := &SimulatedCallResult{
+			ReturnData: res.Return(),
+			Logs:       logs,
+			GasUsed:    hexutil.Uint64(res.UsedGas),
+			L1DataFee:  (*hexutil.Big)(l1DataFee),
+		}
+		if call.Logs == nil {
+			call.Logs = []*types.Log{}
+		}
+		if len(res.Revert()) > 0 {
+			call.ReturnData = res.Revert()
+			call.Error = newRevertError(res).Error()
+		} else if res.Err != nil {
+			call.Error = res.Err.Error()
+		}
+		result.Calls[i] = call
+		result.GasUsed += hexutil.Uint64(res.UsedGas)
+	}
+	return result, nil
+}
+
+// CallBundle executes the given calls in order within a single block on top of
+// the state of the given block, and returns the result of every call.
+//
+// Additionally, the caller can specify a batch of contract for fields overriding,
+// as well as the header fields and L1GasPriceOracle values of the simulated block.
+//
+// Note, this function doesn't make any changes in the state/blockchain and is
+// useful to simulate bundles of transactions before submitting them.
+func (s *PublicBlockChainAPI) CallBundle(ctx context.Context, calls []TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, blockOverrides *BlockOverrides) ([]*SimulatedCallResult, error) {
+	results, err := DoSimulate(ctx, s.b, []SimulatedBlock{{BlockOverrides: blockOverrides, Calls: calls}}, blockNrOrHash, overrides, s.b.RPCEVMTimeout(), s.b.RPCGasCap())
+	if err != nil {
+		return nil, err
+	}
+	return results[0].Calls, nil
+}
+
+// Simulate executes the given blocks of calls in order on top of the state of
+// the given block, and returns the result of every block and call.
+func (s *PublicBlockChainAPI) Simulate(ctx context.Context, blocks []SimulatedBlock, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride) ([]*SimulatedBlockResult, error) {
+	return DoSimulate(ctx, s.b, blocks, blockNrOrHash, overrides, s.b.RPCEVMTimeout(), s.b.RPCGasCap())
+}
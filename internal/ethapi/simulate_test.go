@@ -0,0 +1,258 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/consensus/ethash"
+	"github.com/scroll-tech/go-ethereum/core"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/core/state"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/core/vm"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rollup/rcfg"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+var (
+	testKey, _         = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testAddr           = crypto.PubkeyToAddress(testKey.PublicKey)
+	emptyAccountKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f292")
+	emptyAddr          = crypto.PubkeyToAddress(emptyAccountKey.PublicKey)
+	testBalance        = big.NewInt(2e15)
+
+	// revertAddr holds code reverting without data.
+	revertAddr = common.HexToAddress("0xbb")
+	// logNumberAddr holds code emitting an empty log and returning the block number.
+	logNumberAddr = common.HexToAddress("0xcc")
+	// storeAddr holds code storing its first calldata word in slot 0.
+	storeAddr = common.HexToAddress("0xdd")
+)
+
+// testBackend serves the simulation from a chain in memory. The methods of
+// Backend not needed by the simulation are left unimplemented.
+type testBackend struct {
+	Backend
+	chain *core.BlockChain
+}
+
+func newTestBackend(t *testing.T, config *params.ChainConfig) *testBackend {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		genesis = &core.Genesis{
+			Config: config,
+			Alloc: core.GenesisAlloc{
+				testAddr: {Balance: testBalance},
+				rcfg.L1GasPriceOracleAddress: {
+					Balance: big.NewInt(0),
+					Storage: map[common.Hash]common.Hash{
+						rcfg.L1BaseFeeSlot:     common.BigToHash(big.NewInt(10000)),
+						rcfg.OverheadSlot:      common.BigToHash(big.NewInt(10000)),
+						rcfg.ScalarSlot:        common.BigToHash(big.NewInt(10000)),
+						rcfg.L1BlobBaseFeeSlot: common.BigToHash(big.NewInt(10000)),
+						rcfg.CommitScalarSlot:  common.BigToHash(big.NewInt(10000)),
+						rcfg.BlobScalarSlot:    common.BigToHash(big.NewInt(10000)),
+						rcfg.IsCurieSlot:       common.BytesToHash([]byte{1}),
+					},
+				},
+			},
+			Timestamp: 9000,
+		}
+		engine = ethash.NewFaker()
+	)
+	gblock := genesis.MustCommit(db)
+	blocks, _ := core.GenerateChain(config, gblock, engine, db, 1, func(i int, g *core.BlockGen) {
+		g.OffsetTime(5)
+	})
+	chain, err := core.NewBlockChain(db, nil, config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	t.Cleanup(chain.Stop)
+	return &testBackend{chain: chain}
+}
+
+func (b *testBackend) ChainConfig() *params.ChainConfig { return b.chain.Config() }
+func (b *testBackend) RPCGasCap() uint64                { return 25000000 }
+func (b *testBackend) RPCEVMTimeout() time.Duration     { return 5 * time.Second }
+
+func (b *testBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *types.Header, error) {
+	var header *types.Header
+	if hash, ok := blockNrOrHash.Hash(); ok {
+		header = b.chain.GetHeaderByHash(hash)
+	} else if number, ok := blockNrOrHash.Number(); ok && number >= 0 {
+		header = b.chain.GetHeaderByNumber(uint64(number))
+	} else {
+		header = b.chain.CurrentHeader()
+	}
+	if header == nil {
+		return nil, nil, errors.New("header not found")
+	}
+	statedb, err := b.chain.StateAt(header.Root)
+	return statedb, header, err
+}
+
+func (b *testBackend) GetEVM(ctx context.Context, msg core.Message, state *state.StateDB, header *types.Header, vmConfig *vm.Config) (*vm.EVM, func() error, error) {
+	context := core.NewEVMBlockContext(header, b.chain, b.chain.Config(), nil)
+	return vm.NewEVM(context, core.NewEVMTxContext(msg), state, b.chain.Config(), *vmConfig), func() error { return nil }, nil
+}
+
+// newTestClient serves the blockchain API of a test backend in process.
+func newTestClient(t *testing.T) *rpc.Client {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", NewPublicBlockChainAPI(newTestBackend(t, params.AllEthashProtocolChanges))); err != nil {
+		t.Fatalf("failed to register api: %v", err)
+	}
+	client := rpc.DialInProc(server)
+	t.Cleanup(func() {
+		client.Close()
+		server.Stop()
+	})
+	return client
+}
+
+// simulateOverrides returns the state overrides deploying the simulated contracts.
+func simulateOverrides() map[common.Address]interface{} {
+	return map[common.Address]interface{}{
+		revertAddr:    map[string]interface{}{"code": "0x60006000fd"},
+		logNumberAddr: map[string]interface{}{"code": "0x60006000a04360005260206000f3"},
+		storeAddr:     map[string]interface{}{"code": "0x60003560005500"},
+	}
+}
+
+type simulatedCall struct {
+	ReturnData hexutil.Bytes  `json:"returnData"`
+	Logs       []*types.Log   `json:"logs"`
+	GasUsed    hexutil.Uint64 `json:"gasUsed"`
+	L1DataFee  *hexutil.Big   `json:"l1DataFee"`
+	Error      string         `json:"error"`
+}
+
+func TestCallBundle(t *testing.T) {
+	client := newTestClient(t)
+	calls := []map[string]interface{}{
+		// Fund the empty account, which then transfers back
+		{"from": testAddr, "to": emptyAddr, "value": "0x5208"},
+		{"from": emptyAddr, "to": testAddr, "value": "0x1", "gas": "0x5208"},
+		{"from": testAddr, "to": revertAddr},
+		{"from": testAddr, "to": logNumberAddr},
+	}
+	var results []simulatedCall
+	if err := client.CallContext(context.Background(), &results, "eth_callBundle", calls, "latest", simulateOverrides(), map[string]interface{}{"number": "0x64"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != len(calls) {
+		t.Fatalf("result count mismatch: have %d, want %d", len(results), len(calls))
+	}
+	for i := 0; i < 2; i++ {
+		if results[i].Error != "" || results[i].GasUsed != 21000 {
+			t.Errorf("call %d: unexpected result: %+v", i, results[i])
+		}
+		if results[i].L1DataFee == nil || results[i].L1DataFee.ToInt().Sign() <= 0 {
+			t.Errorf("call %d: missing l1 data fee", i)
+		}
+	}
+	if results[2].Error != "execution reverted" {
+		t.Errorf("unexpected revert error: %q", results[2].Error)
+	}
+	if have := new(big.Int).SetBytes(results[3].ReturnData); have.Uint64() != 100 {
+		t.Errorf("block number mismatch: have %v, want 100", have)
+	}
+	if len(results[3].Logs) != 1 || results[3].Logs[0].Address != logNumberAddr || results[3].Logs[0].BlockNumber != 100 {
+		t.Errorf("unexpected logs: %+v", results[3].Logs)
+	}
+
+	// Without funding, the transfer back cannot be paid for
+	err := client.CallContext(context.Background(), &results, "eth_callBundle", calls[1:2], "latest", nil, nil)
+	if err == nil || !strings.Contains(err.Error(), core.ErrInsufficientFunds.Error()) {
+		t.Errorf("expected insufficient funds, have %v", err)
+	}
+}
+
+// Tests that the calls of a bundle are finalised one after the other, so that
+// clearing a slot set by a previous call is refunded like in a block.
+func TestCallBundleRefund(t *testing.T) {
+	client := newTestClient(t)
+	calls := []map[string]interface{}{
+		{"from": testAddr, "to": storeAddr, "data": common.BigToHash(common.Big1)},
+		{"from": testAddr, "to": storeAddr, "data": common.Hash{}},
+	}
+	var results []simulatedCall
+	if err := client.CallContext(context.Background(), &results, "eth_callBundle", calls, "latest", simulateOverrides(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != len(calls) {
+		t.Fatalf("result count mismatch: have %d, want %d", len(results), len(calls))
+	}
+	// Setting the slot: intrinsic 21140, SSTORE of a new slot 22100 and 9 for
+	// the code. Clearing it: intrinsic 21128, SSTORE of an original slot 5000
+	// and 9 for the code, minus the refund of 4800.
+	for i, want := range []hexutil.Uint64{43249, 21337} {
+		if results[i].Error != "" || results[i].GasUsed != want {
+			t.Errorf("call %d: unexpected result: %+v, want gas used %d", i, results[i], want)
+		}
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	client := newTestClient(t)
+	call := map[string]interface{}{"from": testAddr, "to": logNumberAddr}
+	blocks := []map[string]interface{}{
+		{
+			"blockOverrides": map[string]interface{}{
+				"time":  "0x3e8",
+				"l1Fee": map[string]interface{}{"l1BaseFee": "0x0", "l1BlobBaseFee": "0x0"},
+			},
+			"calls": []interface{}{call},
+		},
+		{"calls": []interface{}{call, call}},
+	}
+	var results []struct {
+		Number  hexutil.Uint64  `json:"number"`
+		Time    hexutil.Uint64  `json:"timestamp"`
+		GasUsed hexutil.Uint64  `json:"gasUsed"`
+		Calls   []simulatedCall `json:"calls"`
+	}
+	if err := client.CallContext(context.Background(), &results, "eth_simulate", blocks, "latest", simulateOverrides()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 || len(results[0].Calls) != 1 || len(results[1].Calls) != 2 {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	for i, result := range results {
+		if want := hexutil.Uint64(2 + i); result.Number != want {
+			t.Errorf("block %d: number mismatch: have %d, want %d", i, result.Number, want)
+		}
+		if result.Time != 1000+hexutil.Uint64(i) {
+			t.Errorf("block %d: time mismatch: have %d, want %d", i, result.Time, 1000+i)
+		}
+		var gasUsed hexutil.Uint64
+		for j, call := range result.Calls {
+			if have := new(big.Int).SetBytes(call.ReturnData); have.Uint64() != uint64(result.Number) {
+				t.Errorf("block %d call %d: returned number %v", i, j, have)
+			}
+			// The l1 fee override outlives the block it is given for
+			if call.L1DataFee.ToInt().Sign() != 0 {
+				t.Errorf("block %d call %d: l1 data fee %v, want 0", i, j, call.L1DataFee)
+			}
+			gasUsed += call.GasUsed
+		}
+		if result.GasUsed != gasUsed {
+			t.Errorf("block %d: gas used mismatch: have %d, want %d", i, result.GasUsed, gasUsed)
+		}
+	}
+	if err := client.CallContext(context.Background(), &results, "eth_simulate", []interface{}{}, "latest", nil); err == nil {
+		t.Error("expected empty simulation to fail")
+	}
+}
@@ -233,6 +233,10 @@ func (b *LesApiBackend) TxPoolContentFrom(addr common.Address) (types.Transactio
 	return b.eth.txPool.ContentFrom(addr)
 }
 
+func (b *LesApiBackend) TxPoolCircuitOverflowed() []*core.CircuitOverflowTx {
+	return nil
+}
+
 func (b *LesApiBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
 	return b.eth.txPool.SubscribeNewTxsEvent(ch)
 }
@@ -322,6 +326,14 @@ func (b *LesApiBackend) ServiceFilter(ctx context.Context, session *bloombits.Ma
 	}
 }
 
+func (b *LesApiBackend) LogIndexStatus() (uint64, uint64) {
+	return 0, 0
+}
+
+func (b *LesApiBackend) AccountHistoryStatus() (bool, uint64) {
+	return false, 0
+}
+
 func (b *LesApiBackend) Engine() consensus.Engine {
 	return b.eth.engine
 }
@@ -59,6 +59,12 @@ type Config struct {
 
 	StoreSkippedTxTraces bool // Whether store the wrapped traces when storing a skipped tx
 	MaxAccountsNum       int  // Maximum number of accounts that miner will fetch the pending transactions of when building a new block
+
+	LeaseFile string        `toml:",omitempty"` // Path of the sequencer leader lease shared with standby sequencers (empty = no failover)
+	LeaseID   string        `toml:",omitempty"` // Identifier of this node in the sequencer leader lease
+	LeaseTTL  time.Duration `toml:",omitempty"` // Time after which a standby sequencer takes over an unrenewed lease
+
+	Signer string `toml:",omitempty"` // External signer (clef) endpoint holding the etherbase key for clique sealing
 }
 
 // Miner creates blocks and searches for proof-of-work values.
@@ -174,6 +174,9 @@ func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus
 	// Subscribe NewTxsEvent for tx pool
 	worker.txsSub = eth.TxPool().SubscribeNewTxsEvent(worker.txsCh)
 
+	// Let the tx pool evict pending transactions that can never fit into a block
+	eth.TxPool().SetCapacityChecker(newTxCapacityChecker(worker.chain, worker.config.GasCeil))
+
 	// Subscribe events for blockchain
 	worker.chainHeadSub = eth.BlockChain().SubscribeChainHeadEvent(worker.chainHeadCh)
 	worker.chainSideSub = eth.BlockChain().SubscribeChainSideEvent(worker.chainSideCh)
@@ -601,14 +604,20 @@ func (w *worker) handlePipelineResult(res *pipeline.Result) error {
 			if !w.config.StoreSkippedTxTraces {
 				overflowingTrace = nil
 			}
-			rawdb.WriteSkippedTransaction(w.eth.ChainDb(), res.OverflowingTx, overflowingTrace, res.CCCErr.Error(),
+			w.chain.WriteSkippedTransaction(res.OverflowingTx, overflowingTrace, res.CCCErr.Error(),
 				w.currentPipeline.Header.Number.Uint64(), nil)
 
 			if overflowingL1MsgTx := res.OverflowingTx.AsL1MessageTx(); overflowingL1MsgTx != nil {
 				rawdb.WriteFirstQueueIndexNotInL2Block(w.eth.ChainDb(), w.currentPipeline.Header.ParentHash, overflowingL1MsgTx.QueueIndex+1)
 			} else {
 				w.prioritizedTx = nil
-				w.eth.TxPool().RemoveTx(res.OverflowingTx.Hash(), true)
+				// Only remember the txs actually overflowing the circuits, the
+				// checker may fail for other, transient, reasons
+				if errors.Is(res.CCCErr, circuitcapacitychecker.ErrBlockRowConsumptionOverflow) {
+					w.eth.TxPool().RemoveCircuitOverflowTx(res.OverflowingTx.Hash(), res.CCCErr)
+				} else {
+					w.eth.TxPool().RemoveTx(res.OverflowingTx.Hash(), true)
+				}
 			}
 		} else if !res.OverflowingTx.IsL1MessageTx() {
 			// prioritize overflowing L2 message as the first txn next block
@@ -783,7 +792,7 @@ func (w *worker) onTxFailingInPipeline(txIndex int, tx *types.Transaction, err e
 		if w.config.StoreSkippedTxTraces && errors.As(err, &errWithTrace) {
 			trace = errWithTrace.Trace
 		}
-		rawdb.WriteSkippedTransaction(w.eth.ChainDb(), tx, trace, err.Error(),
+		w.chain.WriteSkippedTransaction(tx, trace, err.Error(),
 			w.currentPipeline.Header.Number.Uint64(), nil)
 	}
 
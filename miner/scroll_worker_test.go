@@ -926,6 +926,60 @@ func TestPrioritizeOverflowTx(t *testing.T) {
 	assert.False(b.txPool.Has(tx4.Hash()))
 }
 
+// TestOverflowTxEviction tests that a single tx failing the circuit capacity
+// checker is evicted from the pool, and only remembered as overflowing the
+// circuits if it actually does.
+func TestOverflowTxEviction(t *testing.T) {
+	tests := []struct {
+		err      error
+		overflow bool
+	}{
+		{circuitcapacitychecker.ErrBlockRowConsumptionOverflow, true},
+		{circuitcapacitychecker.ErrUnknown, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			var (
+				chainConfig = params.AllCliqueProtocolChanges
+				db          = rawdb.NewMemoryDatabase()
+			)
+			chainConfig.Clique = &params.CliqueConfig{Period: 1, Epoch: 30000}
+			chainConfig.LondonBlock = big.NewInt(0)
+			chainConfig.Scroll.FeeVaultAddress = &common.Address{}
+			engine := clique.New(chainConfig.Clique, db)
+
+			w, b := newTestWorker(t, chainConfig, engine, db, 0)
+			defer w.close()
+
+			tx, _ := types.SignTx(types.NewTransaction(b.txPool.Nonce(testBankAddress), testUserAddress, big.NewInt(0), params.TxGas, big.NewInt(20*params.InitialBaseFee), nil), types.HomesteadSigner{}, testBankKey)
+			w.getCCC().Skip(tx.Hash(), tt.err)
+			if errs := b.txPool.AddRemotesSync([]*types.Transaction{tx}); errs[0] != nil {
+				t.Fatalf("failed to add tx: %v", errs[0])
+			}
+			w.start()
+			for deadline := time.Now().Add(3 * time.Second); b.txPool.Has(tx.Hash()); time.Sleep(10 * time.Millisecond) {
+				if time.Now().After(deadline) {
+					t.Fatal("timeout waiting for the tx to be evicted")
+				}
+			}
+			w.stop()
+
+			overflowed := b.txPool.CircuitOverflowed()
+			if tt.overflow {
+				if len(overflowed) != 1 || overflowed[0].Tx.Hash() != tx.Hash() {
+					t.Errorf("overflowing tx not remembered: %v", overflowed)
+				}
+			} else if len(overflowed) != 0 {
+				t.Errorf("tx remembered as overflowing: %v", overflowed)
+			}
+			// Only the txs overflowing the circuits are rejected when resubmitted
+			if err := b.txPool.AddRemotesSync([]*types.Transaction{tx})[0]; (err != nil) != tt.overflow {
+				t.Errorf("unexpected resubmission error: %v", err)
+			}
+		})
+	}
+}
+
 func TestSkippedTransactionDatabaseEntries(t *testing.T) {
 	assert := assert.New(t)
 
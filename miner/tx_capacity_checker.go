@@ -0,0 +1,86 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/consensus/misc"
+	"github.com/scroll-tech/go-ethereum/core"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/rollup/circuitcapacitychecker"
+	"github.com/scroll-tech/go-ethereum/rollup/fees"
+	"github.com/scroll-tech/go-ethereum/rollup/tracing"
+)
+
+// txCapacityChecker implements core.TxCapacityChecker by tracing a transaction
+// as the only one in a block on top of the current head and running the trace
+// through a dedicated circuit capacity checker.
+type txCapacityChecker struct {
+	chain    *core.BlockChain
+	gasCeil  uint64
+	ccc      *circuitcapacitychecker.CircuitCapacityChecker
+	cccMutex sync.Mutex
+}
+
+func newTxCapacityChecker(chain *core.BlockChain, gasCeil uint64) *txCapacityChecker {
+	return &txCapacityChecker{
+		chain:   chain,
+		gasCeil: gasCeil,
+		ccc:     circuitcapacitychecker.NewCircuitCapacityChecker(true),
+	}
+}
+
+// CheckTransaction implements core.TxCapacityChecker.
+func (c *txCapacityChecker) CheckTransaction(tx *types.Transaction) error {
+	parent := c.chain.CurrentBlock()
+	statedb, err := c.chain.StateAt(parent.Root())
+	if err != nil {
+		return err
+	}
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number(), common.Big1),
+		GasLimit:   core.CalcGasLimit(parent.GasLimit(), c.gasCeil),
+		Time:       uint64(time.Now().Unix()),
+	}
+	if c.chain.Config().IsCurie(header.Number) {
+		header.BaseFee = misc.CalcBaseFee(c.chain.Config(), parent.Header(), fees.GetL1BaseFee(statedb))
+	}
+	trace, err := tracing.NewTracerWrapper().CreateTraceEnvAndGetBlockTrace(c.chain.Config(), c.chain, c.chain.Engine(), c.chain.Database(),
+		statedb, parent, types.NewBlockWithHeader(header).WithBody([]*types.Transaction{tx}, nil), false)
+	if err != nil {
+		return err
+	}
+
+	c.cccMutex.Lock()
+	defer c.cccMutex.Unlock()
+
+	c.ccc.Reset()
+	if _, err := c.ccc.ApplyTransaction(trace); err != nil {
+		if errors.Is(err, circuitcapacitychecker.ErrBlockRowConsumptionOverflow) {
+			return fmt.Errorf("%w: %v", core.ErrCircuitCapacityExceeded, err)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/consensus/ethash"
+	"github.com/scroll-tech/go-ethereum/core"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
+	"github.com/scroll-tech/go-ethereum/rollup/circuitcapacitychecker"
+)
+
+func newTestTxCapacityChecker(t *testing.T) (*txCapacityChecker, *testWorkerBackend) {
+	backend := newTestWorkerBackend(t, ethashChainConfig, ethash.NewFaker(), rawdb.NewMemoryDatabase(), 0)
+	t.Cleanup(backend.chain.Stop)
+	return newTxCapacityChecker(backend.chain, testConfig.GasCeil), backend
+}
+
+func TestTxCapacityCheckerFits(t *testing.T) {
+	checker, backend := newTestTxCapacityChecker(t)
+
+	if err := checker.CheckTransaction(backend.newRandomTx(false)); err != nil {
+		t.Fatalf("transfer rejected: %v", err)
+	}
+	if err := checker.CheckTransaction(backend.newRandomTx(true)); err != nil {
+		t.Fatalf("contract creation rejected: %v", err)
+	}
+}
+
+func TestTxCapacityCheckerOverflow(t *testing.T) {
+	checker, backend := newTestTxCapacityChecker(t)
+	tx := backend.newRandomTx(false)
+
+	checker.ccc.ScheduleError(1, circuitcapacitychecker.ErrBlockRowConsumptionOverflow)
+	err := checker.CheckTransaction(tx)
+	if !errors.Is(err, core.ErrCircuitCapacityExceeded) {
+		t.Fatalf("overflow not reported as exceeding capacity: %v", err)
+	}
+	// The checker is reset between transactions, the overflow doesn't stick
+	if err := checker.CheckTransaction(tx); err != nil {
+		t.Fatalf("transaction rejected after reset: %v", err)
+	}
+}
+
+func TestTxCapacityCheckerSkip(t *testing.T) {
+	checker, backend := newTestTxCapacityChecker(t)
+	var (
+		skipped = backend.newRandomTx(false)
+		other   = backend.newRandomTx(true)
+	)
+	// Only the overflowing transaction exceeds capacity
+	checker.ccc.Skip(skipped.Hash(), circuitcapacitychecker.ErrBlockRowConsumptionOverflow)
+	for i := 0; i < 2; i++ {
+		if err := checker.CheckTransaction(skipped); !errors.Is(err, core.ErrCircuitCapacityExceeded) {
+			t.Fatalf("attempt %d: overflow not reported as exceeding capacity: %v", i, err)
+		}
+		if err := checker.CheckTransaction(other); err != nil {
+			t.Fatalf("attempt %d: other transaction rejected: %v", i, err)
+		}
+	}
+	// Other checker failures are not mistaken for overflows, so the pool keeps
+	// the transaction
+	checker.ccc.Skip(skipped.Hash(), circuitcapacitychecker.ErrUnknown)
+	err := checker.CheckTransaction(skipped)
+	if err == nil || errors.Is(err, core.ErrCircuitCapacityExceeded) {
+		t.Fatalf("unexpected error for failing checker: %v", err)
+	}
+	if !errors.Is(err, circuitcapacitychecker.ErrUnknown) {
+		t.Fatalf("checker error not propagated: %v", err)
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/crypto"
@@ -65,6 +66,11 @@ type Config struct {
 	// in memory.
 	DataDir string
 
+	// DBEngine is the key-value store backend of the databases in DataDir, either
+	// "leveldb" or "pebble". If empty, the backend of an existing database is
+	// used and new databases are created with leveldb.
+	DBEngine string `toml:",omitempty"`
+
 	// Configuration of peer-to-peer networking.
 	P2P p2p.Config
 
@@ -167,6 +173,42 @@ type Config struct {
 	// private APIs to untrusted users is a major security risk.
 	WSExposeAll bool `toml:",omitempty"`
 
+	// AuthAddr is the host interface on which to start the authenticated HTTP and
+	// websocket RPC server. It is only started if JWTSecret is set.
+	AuthAddr string `toml:",omitempty"`
+
+	// AuthPort is the TCP port number on which to start the authenticated RPC server.
+	AuthPort int `toml:",omitempty"`
+
+	// AuthVirtualHosts is the list of virtual hostnames which are allowed on incoming
+	// requests to the authenticated RPC server.
+	AuthVirtualHosts []string `toml:",omitempty"`
+
+	// AuthModules is a list of API modules to expose to the JWTs without a role claim
+	// via the authenticated RPC server. If the module list is empty, all RPC API
+	// endpoints designated public will be exposed.
+	AuthModules []string `toml:",omitempty"`
+
+	// AuthRoles maps the role claim of JWTs to the API modules exposed to them via
+	// the authenticated RPC server, along with per-method rate limits. JWTs with a
+	// role that is not listed are rejected.
+	AuthRoles map[string]AuthRole `toml:",omitempty"`
+
+	// JWTSecret is the path to the hex-encoded HS256 secret used to verify the JWTs
+	// of requests to the authenticated RPC server.
+	JWTSecret string `toml:",omitempty"`
+
+	// RPCBudget configures the request budgets of the clients of the HTTP, websocket
+	// and authenticated RPC servers, which share them. Clients are identified by the
+	// subject or role of their JWT, or else by their IP address. Budgets are not
+	// enforced if nil.
+	RPCBudget *rpc.BudgetConfig `toml:",omitempty"`
+
+	// RPCSlowCallThreshold is the duration above which the calls served by the HTTP,
+	// websocket and authenticated RPC servers are logged along with their parameters.
+	// Slow calls are not logged if zero.
+	RPCSlowCallThreshold time.Duration `toml:",omitempty"`
+
 	// GraphQLCors is the Cross-Origin Resource Sharing header to send to requesting
 	// clients. Please be aware that CORS is a browser enforced security, it's fully
 	// useless for custom HTTP clients.
@@ -197,6 +239,19 @@ type Config struct {
 	L1Confirmations rpc.BlockNumber `toml:",omitempty"`
 	// L1 bridge deployment block number
 	L1DeploymentBlock uint64 `toml:",omitempty"`
+	// Number of L1 endpoints that must agree on logs and headers
+	L1Quorum int `toml:",omitempty"`
+}
+
+// AuthRole is the access granted to the JWTs carrying its name in their role claim.
+type AuthRole struct {
+	// Modules is the list of API modules exposed to the role. If the module list is
+	// empty, all RPC API endpoints designated public will be exposed.
+	Modules []string
+
+	// RateLimits is the maximum number of requests per second to each listed
+	// method, shared by all JWTs of the role.
+	RateLimits map[string]float64 `toml:",omitempty"`
 }
 
 // IPCEndpoint resolves an IPC endpoint based on a configured value, taking into
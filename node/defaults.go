@@ -34,8 +34,31 @@ const (
 	DefaultWSPort      = 8546        // Default TCP port for the websocket RPC server
 	DefaultGraphQLHost = "localhost" // Default host interface for the GraphQL server
 	DefaultGraphQLPort = 8547        // Default TCP port for the GraphQL server
+	DefaultAuthHost    = "localhost" // Default host interface for the authenticated RPC server
+	DefaultAuthPort    = 8551        // Default TCP port for the authenticated RPC server
 )
 
+// DefaultAuthOrigins is the list of origins the authenticated RPC server accepts
+// websocket requests from.
+var DefaultAuthOrigins = []string{"localhost"}
+
+// DefaultRPCCosts are the costs charged to the request budgets of RPC clients
+// for calling the expensive methods, all other methods costing one.
+var DefaultRPCCosts = map[string]int{
+	"eth_call":                           5,
+	"eth_estimateGas":                    5,
+	"eth_getLogs":                        10,
+	"eth_callBundle":                     20,
+	"eth_simulate":                       50,
+	"debug_traceCall":                    50,
+	"debug_traceTransaction":             50,
+	"debug_traceBlockByHash":             200,
+	"debug_traceBlockByNumber":           200,
+	"trace_block":                        200,
+	"trace_filter":                       500,
+	"scroll_getBlockTraceByNumberOrHash": 200,
+}
+
 // DefaultConfig contains reasonable default settings.
 var DefaultConfig = Config{
 	DataDir:             DefaultDataDir(),
@@ -46,6 +69,9 @@ var DefaultConfig = Config{
 	WSPort:              DefaultWSPort,
 	WSModules:           []string{"net", "web3"},
 	GraphQLVirtualHosts: []string{"localhost"},
+	AuthAddr:            DefaultAuthHost,
+	AuthPort:            DefaultAuthPort,
+	AuthVirtualHosts:    []string{"localhost"},
 	P2P: p2p.Config{
 		ListenAddr: ":30303",
 		MaxPeers:   50,
@@ -0,0 +1,166 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/rpc"
+)
+
+// jwtExpiryTimeout is the maximum drift allowed between the issuance time of a
+// JWT and the time it is received at.
+const jwtExpiryTimeout = 60 * time.Second
+
+// jwtClaims are the claims of the JWTs authenticating RPC requests.
+type jwtClaims struct {
+	jwt.RegisteredClaims
+	Role string `json:"role,omitempty"` // Name of the role granting access, if any
+}
+
+// clientID returns the identity of the bearer of the JWT, used to enforce its
+// request budget. JWTs without a subject share the budget of their role, if any.
+func (c *jwtClaims) clientID() string {
+	switch {
+	case c.Subject != "":
+		return "jwt:" + c.Subject
+	case c.Role != "":
+		return "role:" + c.Role
+	}
+	return ""
+}
+
+// jwtAuthConfig is the JWT authentication configuration of an RPC server.
+type jwtAuthConfig struct {
+	secret []byte
+	roles  map[string]AuthRole
+}
+
+// jwtHandler is a handler which authenticates requests with a JWT, dispatching
+// them to the handler of the role they are issued for.
+type jwtHandler struct {
+	keyFunc func(token *jwt.Token) (interface{}, error)
+	next    http.Handler            // handler of the JWTs without a role claim
+	roles   map[string]http.Handler // handlers of the JWTs by role claim
+}
+
+// newJWTHandler creates a http.Handler with jwt authentication support.
+func newJWTHandler(secret []byte, next http.Handler, roles map[string]http.Handler) http.Handler {
+	return &jwtHandler{
+		keyFunc: func(token *jwt.Token) (interface{}, error) {
+			return secret, nil
+		},
+		next:  next,
+		roles: roles,
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (handler *jwtHandler) ServeHTTP(out http.ResponseWriter, r *http.Request) {
+	var (
+		strToken string
+		claims   jwtClaims
+	)
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		strToken = strings.TrimPrefix(auth, "Bearer ")
+	}
+	if len(strToken) == 0 {
+		http.Error(out, "missing token", http.StatusUnauthorized)
+		return
+	}
+	// We explicitly set only HS256 allowed, and also disables the claim-check:
+	// the RegisteredClaims internally requires 'iat' to be no later than 'now',
+	// but we allow for a bit of drift.
+	token, err := jwt.ParseWithClaims(strToken, &claims, handler.keyFunc,
+		jwt.WithValidMethods([]string{"HS256"}),
+		jwt.WithoutClaimsValidation())
+
+	switch {
+	case err != nil:
+		http.Error(out, err.Error(), http.StatusUnauthorized)
+	case !token.Valid:
+		http.Error(out, "invalid token", http.StatusUnauthorized)
+	case !claims.VerifyExpiresAt(time.Now(), false): // optional
+		http.Error(out, "token is expired", http.StatusUnauthorized)
+	case claims.IssuedAt == nil:
+		http.Error(out, "missing issued-at", http.StatusUnauthorized)
+	case time.Since(claims.IssuedAt.Time) > jwtExpiryTimeout:
+		http.Error(out, "stale token", http.StatusUnauthorized)
+	case time.Until(claims.IssuedAt.Time) > jwtExpiryTimeout:
+		http.Error(out, "future token", http.StatusUnauthorized)
+	default:
+		next := handler.next
+		if claims.Role != "" {
+			var ok bool
+			if next, ok = handler.roles[claims.Role]; !ok {
+				http.Error(out, fmt.Sprintf("unknown role %q", claims.Role), http.StatusForbidden)
+				return
+			}
+		}
+		if id := claims.clientID(); id != "" {
+			r = r.WithContext(rpc.WithClientID(r.Context(), id))
+		}
+		next.ServeHTTP(out, r)
+	}
+}
+
+// newAuthHandler returns the handler authenticating the requests to the given
+// RPC server, which serves the JWTs without a role claim. Every role is served
+// by an RPC server of its own, exposing the modules of the role only and
+// enforcing its rate limits after the given middlewares. The servers of the roles
+// are returned along with the handler so that they can be stopped.
+func newAuthHandler(apis []rpc.API, srv *rpc.Server, auth *jwtAuthConfig, middlewares []rpc.Middleware, serve func(*rpc.Server) http.Handler) (http.Handler, []*rpc.Server, error) {
+	var (
+		handlers = make(map[string]http.Handler, len(auth.roles))
+		servers  = make([]*rpc.Server, 0, len(auth.roles))
+	)
+	for name, role := range auth.roles {
+		roleSrv := rpc.NewServer()
+		if err := RegisterApis(apis, role.Modules, roleSrv, false); err != nil {
+			for _, s := range servers {
+				s.Stop()
+			}
+			return nil, nil, err
+		}
+		roleSrv.Use(middlewares...)
+		roleSrv.SetRateLimits(role.RateLimits)
+		handlers[name] = serve(roleSrv)
+		servers = append(servers, roleSrv)
+	}
+	return newJWTHandler(auth.secret, serve(srv), handlers), servers, nil
+}
+
+// readJWTSecret loads the hex-encoded 32 byte JWT secret from the given file.
+func readJWTSecret(fileName string) ([]byte, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWT secret: %w", err)
+	}
+	secret := common.FromHex(strings.TrimSpace(string(data)))
+	if len(secret) != 32 {
+		return nil, errors.New("invalid JWT secret, must be 32 hex-encoded bytes")
+	}
+	return secret, nil
+}
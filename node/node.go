@@ -56,6 +56,7 @@ type Node struct {
 	rpcAPIs       []rpc.API   // List of APIs currently provided by the node
 	http          *httpServer //
 	ws            *httpServer //
+	httpAuth      *httpServer // Serves the JWT-authenticated HTTP and WebSocket APIs
 	ipc           *ipcServer  // Stores information about the ipc http server
 	inprocHandler *rpc.Server // In-process RPC request handler to process the API requests
 
@@ -149,6 +150,7 @@ func New(conf *Config) (*Node, error) {
 	// Configure RPC servers.
 	node.http = newHTTPServer(node.log, conf.HTTPTimeouts)
 	node.ws = newHTTPServer(node.log, rpc.DefaultHTTPTimeouts)
+	node.httpAuth = newHTTPServer(node.log, conf.HTTPTimeouts)
 	node.ipc = newIPCServer(node.log, conf.IPCEndpoint())
 
 	return node, nil
@@ -352,12 +354,14 @@ func (n *Node) startRPC() error {
 	}
 
 	// Configure HTTP.
+	middlewares := n.rpcMiddlewares()
 	if n.config.HTTPHost != "" {
 		config := httpConfig{
 			CorsAllowedOrigins: n.config.HTTPCors,
 			Vhosts:             n.config.HTTPVirtualHosts,
 			Modules:            n.config.HTTPModules,
 			prefix:             n.config.HTTPPathPrefix,
+			middlewares:        middlewares,
 		}
 		if err := n.http.setListenAddr(n.config.HTTPHost, n.config.HTTPPort); err != nil {
 			return err
@@ -371,9 +375,10 @@ func (n *Node) startRPC() error {
 	if n.config.WSHost != "" {
 		server := n.wsServerForPort(n.config.WSPort)
 		config := wsConfig{
-			Modules: n.config.WSModules,
-			Origins: n.config.WSOrigins,
-			prefix:  n.config.WSPathPrefix,
+			Modules:     n.config.WSModules,
+			Origins:     n.config.WSOrigins,
+			prefix:      n.config.WSPathPrefix,
+			middlewares: middlewares,
 		}
 		if err := server.setListenAddr(n.config.WSHost, n.config.WSPort); err != nil {
 			return err
@@ -383,10 +388,54 @@ func (n *Node) startRPC() error {
 		}
 	}
 
+	// Configure the authenticated HTTP and WebSocket endpoint.
+	if n.config.JWTSecret != "" && n.config.AuthAddr != "" {
+		secret, err := readJWTSecret(n.config.JWTSecret)
+		if err != nil {
+			return err
+		}
+		auth := &jwtAuthConfig{secret: secret, roles: n.config.AuthRoles}
+		if err := n.httpAuth.setListenAddr(n.config.AuthAddr, n.config.AuthPort); err != nil {
+			return err
+		}
+		if err := n.httpAuth.enableRPC(n.rpcAPIs, httpConfig{
+			Vhosts:      n.config.AuthVirtualHosts,
+			Modules:     n.config.AuthModules,
+			jwtAuth:     auth,
+			middlewares: middlewares,
+		}); err != nil {
+			return err
+		}
+		if err := n.httpAuth.enableWS(n.rpcAPIs, wsConfig{
+			Origins:     DefaultAuthOrigins,
+			Modules:     n.config.AuthModules,
+			jwtAuth:     auth,
+			middlewares: middlewares,
+		}); err != nil {
+			return err
+		}
+	}
+
 	if err := n.http.start(); err != nil {
 		return err
 	}
-	return n.ws.start()
+	if err := n.ws.start(); err != nil {
+		return err
+	}
+	return n.httpAuth.start()
+}
+
+// rpcMiddlewares returns the middlewares wrapping the calls served by the HTTP,
+// websocket and authenticated RPC servers, sharing the request budgets.
+func (n *Node) rpcMiddlewares() []rpc.Middleware {
+	var middlewares []rpc.Middleware
+	if n.config.RPCBudget != nil {
+		middlewares = append(middlewares, rpc.NewBudgetLimiter(*n.config.RPCBudget).Middleware())
+	}
+	if n.config.RPCSlowCallThreshold > 0 {
+		middlewares = append(middlewares, rpc.NewSlowCallLogger(n.config.RPCSlowCallThreshold))
+	}
+	return middlewares
 }
 
 func (n *Node) wsServerForPort(port int) *httpServer {
@@ -399,6 +448,7 @@ func (n *Node) wsServerForPort(port int) *httpServer {
 func (n *Node) stopRPC() {
 	n.http.stop()
 	n.ws.stop()
+	n.httpAuth.stop()
 	n.ipc.stop()
 	n.stopInProc()
 }
@@ -546,6 +596,12 @@ func (n *Node) WSEndpoint() string {
 	return "ws://" + n.ws.listenAddr() + n.ws.wsConfig.prefix
 }
 
+// AuthEndpoint returns the URL of the authenticated HTTP server, which also
+// serves JSON-RPC over WebSocket.
+func (n *Node) AuthEndpoint() string {
+	return "http://" + n.httpAuth.listenAddr()
+}
+
 // EventMux retrieves the event multiplexer used by all the network services in
 // the current protocol stack.
 func (n *Node) EventMux() *event.TypeMux {
@@ -567,7 +623,10 @@ func (n *Node) OpenDatabase(name string, cache, handles int, namespace string, r
 	if n.config.DataDir == "" {
 		db = rawdb.NewMemoryDatabase()
 	} else {
-		db, err = rawdb.NewLevelDBDatabase(n.ResolvePath(name), cache, handles, namespace, readonly)
+		var kvdb ethdb.KeyValueStore
+		if kvdb, err = rawdb.OpenKeyValueStore(n.config.DBEngine, n.ResolvePath(name), cache, handles, namespace, readonly); err == nil {
+			db = rawdb.NewDatabase(kvdb)
+		}
 	}
 
 	if err == nil {
@@ -600,7 +659,12 @@ func (n *Node) OpenDatabaseWithFreezer(name string, cache, handles int, freezer,
 		case !filepath.IsAbs(freezer):
 			freezer = n.ResolvePath(freezer)
 		}
-		db, err = rawdb.NewLevelDBDatabaseWithFreezer(root, cache, handles, freezer, namespace, readonly)
+		var kvdb ethdb.KeyValueStore
+		if kvdb, err = rawdb.OpenKeyValueStore(n.config.DBEngine, root, cache, handles, namespace, readonly); err == nil {
+			if db, err = rawdb.NewDatabaseWithFreezer(kvdb, freezer, namespace, readonly); err != nil {
+				kvdb.Close()
+			}
+		}
 	}
 
 	if err == nil {
@@ -40,19 +40,32 @@ type httpConfig struct {
 	Modules            []string
 	CorsAllowedOrigins []string
 	Vhosts             []string
-	prefix             string // path prefix on which to mount http handler
+	prefix             string           // path prefix on which to mount http handler
+	jwtAuth            *jwtAuthConfig   // JWT authentication of the requests, if any
+	middlewares        []rpc.Middleware // wrapping the method calls
 }
 
 // wsConfig is the JSON-RPC/Websocket configuration
 type wsConfig struct {
-	Origins []string
-	Modules []string
-	prefix  string // path prefix on which to mount ws handler
+	Origins     []string
+	Modules     []string
+	prefix      string           // path prefix on which to mount ws handler
+	jwtAuth     *jwtAuthConfig   // JWT authentication of the requests, if any
+	middlewares []rpc.Middleware // wrapping the method calls
 }
 
 type rpcHandler struct {
 	http.Handler
-	server *rpc.Server
+	server      *rpc.Server
+	roleServers []*rpc.Server // servers of the JWT roles, if authenticated
+}
+
+// stop stops the RPC servers of the handler.
+func (h *rpcHandler) stop() {
+	h.server.Stop()
+	for _, srv := range h.roleServers {
+		srv.Stop()
+	}
 }
 
 type httpServer struct {
@@ -255,11 +268,11 @@ func (h *httpServer) doStop() {
 	wsHandler := h.wsHandler.Load().(*rpcHandler)
 	if httpHandler != nil {
 		h.httpHandler.Store((*rpcHandler)(nil))
-		httpHandler.server.Stop()
+		httpHandler.stop()
 	}
 	if wsHandler != nil {
 		h.wsHandler.Store((*rpcHandler)(nil))
-		wsHandler.server.Stop()
+		wsHandler.stop()
 	}
 	h.server.Shutdown(context.Background())
 	h.listener.Close()
@@ -284,11 +297,21 @@ func (h *httpServer) enableRPC(apis []rpc.API, config httpConfig) error {
 	if err := RegisterApis(apis, config.Modules, srv, false); err != nil {
 		return err
 	}
+	srv.Use(config.middlewares...)
+	handler := &rpcHandler{server: srv}
+	if config.jwtAuth != nil {
+		authHandler, servers, err := newAuthHandler(apis, srv, config.jwtAuth, config.middlewares, func(srv *rpc.Server) http.Handler { return srv })
+		if err != nil {
+			srv.Stop()
+			return err
+		}
+		handler.Handler = NewHTTPHandlerStack(authHandler, config.CorsAllowedOrigins, config.Vhosts)
+		handler.roleServers = servers
+	} else {
+		handler.Handler = NewHTTPHandlerStack(srv, config.CorsAllowedOrigins, config.Vhosts)
+	}
 	h.httpConfig = config
-	h.httpHandler.Store(&rpcHandler{
-		Handler: NewHTTPHandlerStack(srv, config.CorsAllowedOrigins, config.Vhosts),
-		server:  srv,
-	})
+	h.httpHandler.Store(handler)
 	return nil
 }
 
@@ -297,7 +320,7 @@ func (h *httpServer) disableRPC() bool {
 	handler := h.httpHandler.Load().(*rpcHandler)
 	if handler != nil {
 		h.httpHandler.Store((*rpcHandler)(nil))
-		handler.server.Stop()
+		handler.stop()
 	}
 	return handler != nil
 }
@@ -316,11 +339,23 @@ func (h *httpServer) enableWS(apis []rpc.API, config wsConfig) error {
 	if err := RegisterApis(apis, config.Modules, srv, false); err != nil {
 		return err
 	}
+	srv.Use(config.middlewares...)
+	handler := &rpcHandler{server: srv}
+	if config.jwtAuth != nil {
+		authHandler, servers, err := newAuthHandler(apis, srv, config.jwtAuth, config.middlewares, func(srv *rpc.Server) http.Handler {
+			return srv.WebsocketHandler(config.Origins)
+		})
+		if err != nil {
+			srv.Stop()
+			return err
+		}
+		handler.Handler = authHandler
+		handler.roleServers = servers
+	} else {
+		handler.Handler = srv.WebsocketHandler(config.Origins)
+	}
 	h.wsConfig = config
-	h.wsHandler.Store(&rpcHandler{
-		Handler: srv.WebsocketHandler(config.Origins),
-		server:  srv,
-	})
+	h.wsHandler.Store(handler)
 	return nil
 }
 
@@ -341,7 +376,7 @@ func (h *httpServer) disableWS() bool {
 	ws := h.wsHandler.Load().(*rpcHandler)
 	if ws != nil {
 		h.wsHandler.Store((*rpcHandler)(nil))
-		ws.server.Stop()
+		ws.stop()
 	}
 	return ws != nil
 }
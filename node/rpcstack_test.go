@@ -18,13 +18,17 @@ package node
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 
@@ -292,3 +296,118 @@ func rpcRequest(t *testing.T, url string, extraHeaders ...string) *http.Response
 	}
 	return resp
 }
+
+type echoAPI struct{}
+
+func (echoAPI) Echo(s string) string { return s }
+
+// authRequest performs a JSON-RPC request to the given URL, authenticated with
+// the given JWT, returning the status code and the decoded response.
+func authRequest(t *testing.T, url, token, method string) (int, map[string]interface{}) {
+	t.Helper()
+
+	body := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":%q,"params":["hi"]}`, method)
+	if method == "rpc_modules" {
+		body = `{"jsonrpc":"2.0","id":1,"method":"rpc_modules","params":[]}`
+	}
+	req, err := http.NewRequest("POST", url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal("could not create http request:", err)
+	}
+	req.Header.Set("content-type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var result map[string]interface{}
+	if resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatal("could not decode response:", err)
+		}
+	}
+	return resp.StatusCode, result
+}
+
+// TestJWT makes sure the JWT authentication and role access control are
+// properly handled on the http server.
+func TestJWT(t *testing.T) {
+	secret := bytes.Repeat([]byte{0x42}, 32)
+	issue := func(secret []byte, claims jwt.MapClaims) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+		if err != nil {
+			t.Fatal("could not sign token:", err)
+		}
+		return token
+	}
+	now := time.Now().Unix()
+
+	apis := []rpc.API{
+		{Namespace: "test", Service: echoAPI{}, Public: true},
+		{Namespace: "debug", Service: echoAPI{}},
+	}
+	auth := &jwtAuthConfig{secret: secret, roles: map[string]AuthRole{
+		"prover": {Modules: []string{"debug"}, RateLimits: map[string]float64{"debug_echo": 1}},
+	}}
+	srv := newHTTPServer(testlog.Logger(t, log.LvlDebug), rpc.DefaultHTTPTimeouts)
+	assert.NoError(t, srv.enableRPC(apis, httpConfig{jwtAuth: auth}))
+	assert.NoError(t, srv.setListenAddr("localhost", 0))
+	assert.NoError(t, srv.start())
+	defer srv.stop()
+	url := "http://" + srv.listenAddr()
+
+	// Rejected tokens
+	for i, token := range []string{
+		"",
+		issue(bytes.Repeat([]byte{0x43}, 32), jwt.MapClaims{"iat": now}),
+		issue(secret, jwt.MapClaims{}),
+		issue(secret, jwt.MapClaims{"iat": now - 120}),
+		issue(secret, jwt.MapClaims{"iat": now + 120}),
+		issue(secret, jwt.MapClaims{"iat": now, "exp": now - 1}),
+	} {
+		if code, _ := authRequest(t, url, token, "rpc_modules"); code != http.StatusUnauthorized {
+			t.Errorf("token %d: status mismatch: have %d, want %d", i, code, http.StatusUnauthorized)
+		}
+	}
+	if code, _ := authRequest(t, url, issue(secret, jwt.MapClaims{"iat": now, "role": "miner"}), "rpc_modules"); code != http.StatusForbidden {
+		t.Errorf("unknown role: status mismatch: have %d, want %d", code, http.StatusForbidden)
+	}
+
+	// Modules exposed to the tokens with and without a role
+	for _, tt := range []struct {
+		role    string
+		modules []string
+	}{
+		{"", []string{"rpc", "test"}},
+		{"prover", []string{"debug", "rpc"}},
+	} {
+		claims := jwt.MapClaims{"iat": now}
+		if tt.role != "" {
+			claims["role"] = tt.role
+		}
+		code, resp := authRequest(t, url, issue(secret, claims), "rpc_modules")
+		if code != http.StatusOK {
+			t.Fatalf("role %q: status mismatch: have %d, want %d", tt.role, code, http.StatusOK)
+		}
+		var modules []string
+		for module := range resp["result"].(map[string]interface{}) {
+			modules = append(modules, module)
+		}
+		sort.Strings(modules)
+		assert.Equal(t, tt.modules, modules, "role %q", tt.role)
+	}
+
+	// Rate limits of the role
+	token := issue(secret, jwt.MapClaims{"iat": now, "role": "prover"})
+	if _, resp := authRequest(t, url, token, "debug_echo"); resp["result"] != "hi" {
+		t.Errorf("unexpected response: %v", resp)
+	}
+	_, resp := authRequest(t, url, token, "debug_echo")
+	if err, ok := resp["error"].(map[string]interface{}); !ok || err["code"] != float64(-32005) {
+		t.Errorf("expected rate limit error, have %v", resp)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"math/big"
+	"sort"
 
 	"golang.org/x/crypto/sha3"
 
@@ -535,6 +536,75 @@ type ScrollConfig struct {
 
 	// L1 config
 	L1Config *L1Config `json:"l1Config,omitempty"`
+
+	// L2 base fee parameters, scheduled by activation block [optional]
+	L2BaseFee []*L2BaseFeeConfig `json:"l2BaseFee,omitempty"`
+}
+
+// Default parameters of the L2 base fee model, used unless overridden in
+// ScrollConfig.L2BaseFee.
+var (
+	DefaultL2SequencerFee    = big.NewInt(10000000)    // 0.01 Gwei
+	DefaultL2ProvingFee      = big.NewInt(140000000)   // 0.14 Gwei
+	DefaultL2L1BaseFeeScalar = big.NewInt(14000000)    // 0.014, in units of L2BaseFeePrecision
+	DefaultL2MaxBaseFee      = big.NewInt(10000000000) // 10 Gwei, only exceeded if L1 base fee hits 700 Gwei
+)
+
+// L2BaseFeePrecision is the precision of L2BaseFeeConfig.L1BaseFeeScalar.
+const L2BaseFeePrecision = 1000000000
+
+// L2BaseFeeConfig contains the parameters of the L2 base fee model, effective
+// from Block onwards. The base fee of a block is
+//
+//	sequencerFee + provingFee + parentL1BaseFee * l1BaseFeeScalar / L2BaseFeePrecision
+//
+// capped at maxBaseFee. If ElasticityMultiplier is non-zero, an EIP-1559 style
+// congestion component is enabled: the base fee moves up or down from the
+// parent's base fee depending on the parent's gas usage relative to its target,
+// but never drops below the formula above. Unset fields keep their values from
+// the previous entry of the schedule, or the defaults for the first one. A zero
+// ElasticityMultiplier disables the congestion component again.
+type L2BaseFeeConfig struct {
+	Block                    *big.Int `json:"block"`
+	SequencerFee             *big.Int `json:"sequencerFee,omitempty"`
+	ProvingFee               *big.Int `json:"provingFee,omitempty"`
+	L1BaseFeeScalar          *big.Int `json:"l1BaseFeeScalar,omitempty"`
+	MaxBaseFee               *big.Int `json:"maxBaseFee,omitempty"`
+	ElasticityMultiplier     *uint64  `json:"elasticityMultiplier,omitempty"`
+	BaseFeeChangeDenominator uint64   `json:"baseFeeChangeDenominator,omitempty"`
+}
+
+func (c *L2BaseFeeConfig) String() string {
+	elasticityMultiplier := "<nil>"
+	if c.ElasticityMultiplier != nil {
+		elasticityMultiplier = fmt.Sprintf("%v", *c.ElasticityMultiplier)
+	}
+	return fmt.Sprintf("{block: %v, sequencerFee: %v, provingFee: %v, l1BaseFeeScalar: %v, maxBaseFee: %v, elasticityMultiplier: %v, baseFeeChangeDenominator: %v}",
+		c.Block, c.SequencerFee, c.ProvingFee, c.L1BaseFeeScalar, c.MaxBaseFee, elasticityMultiplier, c.BaseFeeChangeDenominator)
+}
+
+// CongestionPricing returns whether the congestion component of the base fee
+// is enabled.
+func (c *L2BaseFeeConfig) CongestionPricing() bool {
+	return c.ElasticityMultiplier != nil && *c.ElasticityMultiplier != 0
+}
+
+// equal returns whether the given parameters of the base fee model are the same,
+// regardless of their activation blocks.
+func (c *L2BaseFeeConfig) equal(other *L2BaseFeeConfig) bool {
+	var elasticity, otherElasticity uint64
+	if c.ElasticityMultiplier != nil {
+		elasticity = *c.ElasticityMultiplier
+	}
+	if other.ElasticityMultiplier != nil {
+		otherElasticity = *other.ElasticityMultiplier
+	}
+	return configNumEqual(c.SequencerFee, other.SequencerFee) &&
+		configNumEqual(c.ProvingFee, other.ProvingFee) &&
+		configNumEqual(c.L1BaseFeeScalar, other.L1BaseFeeScalar) &&
+		configNumEqual(c.MaxBaseFee, other.MaxBaseFee) &&
+		elasticity == otherElasticity &&
+		c.BaseFeeChangeDenominator == other.BaseFeeChangeDenominator
 }
 
 // L1Config contains the l1 parameters needed to sync l1 contract events (e.g., l1 messages, commit/revert/finalize batches) in the sequencer
@@ -577,8 +647,86 @@ func (s ScrollConfig) String() string {
 		maxTxPayloadBytesPerBlock = fmt.Sprintf("%v", *s.MaxTxPayloadBytesPerBlock)
 	}
 
-	return fmt.Sprintf("{useZktrie: %v, maxTxPerBlock: %v, MaxTxPayloadBytesPerBlock: %v, feeVaultAddress: %v, l1Config: %v}",
-		s.UseZktrie, maxTxPerBlock, maxTxPayloadBytesPerBlock, s.FeeVaultAddress, s.L1Config.String())
+	return fmt.Sprintf("{useZktrie: %v, maxTxPerBlock: %v, MaxTxPayloadBytesPerBlock: %v, feeVaultAddress: %v, l1Config: %v, l2BaseFee: %v}",
+		s.UseZktrie, maxTxPerBlock, maxTxPayloadBytesPerBlock, s.FeeVaultAddress, s.L1Config.String(), s.L2BaseFee)
+}
+
+// L2BaseFeeConfigAt returns the L2 base fee parameters in effect at the given
+// block. The entries of the schedule activated up to the block are applied in
+// order on top of the defaults, each one overriding the fields it sets. The
+// schedule is ordered by activation block, see CheckConfigForkOrder.
+func (s ScrollConfig) L2BaseFeeConfigAt(num *big.Int) L2BaseFeeConfig {
+	cfg := L2BaseFeeConfig{
+		Block:                    common.Big0,
+		SequencerFee:             DefaultL2SequencerFee,
+		ProvingFee:               DefaultL2ProvingFee,
+		L1BaseFeeScalar:          DefaultL2L1BaseFeeScalar,
+		MaxBaseFee:               DefaultL2MaxBaseFee,
+		BaseFeeChangeDenominator: BaseFeeChangeDenominator,
+	}
+	for _, c := range s.L2BaseFee {
+		if c == nil || !isForked(c.Block, num) {
+			continue
+		}
+		cfg.Block = c.Block
+		if c.SequencerFee != nil {
+			cfg.SequencerFee = c.SequencerFee
+		}
+		if c.ProvingFee != nil {
+			cfg.ProvingFee = c.ProvingFee
+		}
+		if c.L1BaseFeeScalar != nil {
+			cfg.L1BaseFeeScalar = c.L1BaseFeeScalar
+		}
+		if c.MaxBaseFee != nil {
+			cfg.MaxBaseFee = c.MaxBaseFee
+		}
+		if c.ElasticityMultiplier != nil {
+			cfg.ElasticityMultiplier = c.ElasticityMultiplier
+		}
+		if c.BaseFeeChangeDenominator != 0 {
+			cfg.BaseFeeChangeDenominator = c.BaseFeeChangeDenominator
+		}
+	}
+	return cfg
+}
+
+// checkL2BaseFeeOrder checks that the entries of the L2 base fee schedule have
+// strictly increasing activation blocks.
+func (s ScrollConfig) checkL2BaseFeeOrder() error {
+	var last *big.Int
+	for i, c := range s.L2BaseFee {
+		if c == nil || c.Block == nil {
+			return fmt.Errorf("l2BaseFee entry %d has no activation block", i)
+		}
+		if last != nil && last.Cmp(c.Block) >= 0 {
+			return fmt.Errorf("unsupported l2BaseFee ordering: entry %d enabled at %v, but entry %d enabled at %v", i-1, last, i, c.Block)
+		}
+		last = c.Block
+	}
+	return nil
+}
+
+// checkL2BaseFeeCompatible checks that the L2 base fee parameters of the blocks
+// up to the head stay the same with the new schedule, returning an error
+// rewinding to the first activation block where they change.
+func (s ScrollConfig) checkL2BaseFeeCompatible(news ScrollConfig, head *big.Int) *ConfigCompatError {
+	var blocks []*big.Int
+	for _, schedule := range [][]*L2BaseFeeConfig{s.L2BaseFee, news.L2BaseFee} {
+		for _, c := range schedule {
+			if c != nil && isForked(c.Block, head) {
+				blocks = append(blocks, c.Block)
+			}
+		}
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].Cmp(blocks[j]) < 0 })
+	for _, block := range blocks {
+		stored, next := s.L2BaseFeeConfigAt(block), news.L2BaseFeeConfigAt(block)
+		if !stored.equal(&next) {
+			return newCompatError("L2 base fee schedule", block, block)
+		}
+	}
+	return nil
 }
 
 // IsValidTxCount returns whether the given block's transaction count is below the limit.
@@ -814,7 +962,7 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 			lastFork = cur
 		}
 	}
-	return nil
+	return c.Scroll.checkL2BaseFeeOrder()
 }
 
 func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *ConfigCompatError {
@@ -882,6 +1030,9 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *Confi
 	if isForkIncompatible(c.DescartesBlock, newcfg.DescartesBlock, head) {
 		return newCompatError("Descartes fork block", c.DescartesBlock, newcfg.DescartesBlock)
 	}
+	if err := c.Scroll.checkL2BaseFeeCompatible(newcfg.Scroll, head); err != nil {
+		return err
+	}
 	return nil
 }
 
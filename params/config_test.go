@@ -87,6 +87,30 @@ func TestCheckCompatible(t *testing.T) {
 				RewindTo:     30,
 			},
 		},
+		{
+			stored:  &ChainConfig{Scroll: ScrollConfig{L2BaseFee: []*L2BaseFeeConfig{{Block: big.NewInt(10), SequencerFee: big.NewInt(1)}}}},
+			new:     &ChainConfig{Scroll: ScrollConfig{L2BaseFee: []*L2BaseFeeConfig{{Block: big.NewInt(10), SequencerFee: big.NewInt(1)}, {Block: big.NewInt(20), ProvingFee: big.NewInt(2)}}}},
+			head:    15,
+			wantErr: nil,
+		},
+		{
+			// an entry repeating the parameters in effect changes nothing
+			stored:  &ChainConfig{Scroll: ScrollConfig{L2BaseFee: []*L2BaseFeeConfig{{Block: big.NewInt(10), SequencerFee: big.NewInt(1)}}}},
+			new:     &ChainConfig{Scroll: ScrollConfig{L2BaseFee: []*L2BaseFeeConfig{{Block: big.NewInt(10), SequencerFee: big.NewInt(1)}, {Block: big.NewInt(20), SequencerFee: big.NewInt(1)}}}},
+			head:    25,
+			wantErr: nil,
+		},
+		{
+			stored: &ChainConfig{Scroll: ScrollConfig{L2BaseFee: []*L2BaseFeeConfig{{Block: big.NewInt(10), SequencerFee: big.NewInt(1)}}}},
+			new:    &ChainConfig{Scroll: ScrollConfig{L2BaseFee: []*L2BaseFeeConfig{{Block: big.NewInt(10), SequencerFee: big.NewInt(1)}, {Block: big.NewInt(20), ProvingFee: big.NewInt(2)}}}},
+			head:   25,
+			wantErr: &ConfigCompatError{
+				What:         "L2 base fee schedule",
+				StoredConfig: big.NewInt(20),
+				NewConfig:    big.NewInt(20),
+				RewindTo:     19,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -96,3 +120,49 @@ func TestCheckCompatible(t *testing.T) {
 		}
 	}
 }
+
+func TestL2BaseFeeConfigAt(t *testing.T) {
+	elasticity := uint64(2)
+	scroll := ScrollConfig{L2BaseFee: []*L2BaseFeeConfig{
+		{Block: big.NewInt(10), SequencerFee: big.NewInt(1), ProvingFee: big.NewInt(2)},
+		{Block: big.NewInt(20), ProvingFee: big.NewInt(3), ElasticityMultiplier: &elasticity},
+	}}
+	for _, test := range []struct {
+		number       int64
+		sequencerFee *big.Int
+		provingFee   *big.Int
+		congestion   bool
+	}{
+		{9, DefaultL2SequencerFee, DefaultL2ProvingFee, false},
+		{10, big.NewInt(1), big.NewInt(2), false},
+		{20, big.NewInt(1), big.NewInt(3), true}, // sequencer fee inherited
+	} {
+		cfg := scroll.L2BaseFeeConfigAt(big.NewInt(test.number))
+		if cfg.SequencerFee.Cmp(test.sequencerFee) != 0 || cfg.ProvingFee.Cmp(test.provingFee) != 0 || cfg.CongestionPricing() != test.congestion {
+			t.Errorf("block %d: wrong parameters %v", test.number, &cfg)
+		}
+		if cfg.MaxBaseFee.Cmp(DefaultL2MaxBaseFee) != 0 {
+			t.Errorf("block %d: max base fee mismatch: have %v, want %v", test.number, cfg.MaxBaseFee, DefaultL2MaxBaseFee)
+		}
+	}
+}
+
+func TestCheckL2BaseFeeOrder(t *testing.T) {
+	for i, test := range []struct {
+		schedule []*L2BaseFeeConfig
+		ok       bool
+	}{
+		{nil, true},
+		{[]*L2BaseFeeConfig{{Block: big.NewInt(0)}, {Block: big.NewInt(10)}}, true},
+		{[]*L2BaseFeeConfig{{Block: big.NewInt(10)}, {Block: big.NewInt(10)}}, false},
+		{[]*L2BaseFeeConfig{{Block: big.NewInt(10)}, {Block: big.NewInt(5)}}, false},
+		{[]*L2BaseFeeConfig{{Block: big.NewInt(10)}, nil}, false},
+		{[]*L2BaseFeeConfig{{}}, false},
+	} {
+		config := *TestChainConfig
+		config.Scroll.L2BaseFee = test.schedule
+		if err := config.CheckConfigForkOrder(); (err == nil) != test.ok {
+			t.Errorf("test %d: unexpected result %v", i, err)
+		}
+	}
+}
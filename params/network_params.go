@@ -32,6 +32,9 @@ const (
 	// considered probably final and its rotated bits are calculated.
 	BloomConfirms = 256
 
+	// LogIndexBlocks is the number of blocks a single log index section covers.
+	LogIndexBlocks uint64 = 32768
+
 	// CHTFrequency is the block frequency for creating CHTs
 	CHTFrequency = 32768
 
@@ -52,9 +52,90 @@ type gpoState struct {
 	blobScalar    *big.Int
 }
 
+// GPOOverride replaces values stored in the L1GasPriceOracle contract, e.g. to
+// simulate the L1 data fee under different L1 gas prices. Nil fields keep the
+// value found in the state.
+type GPOOverride struct {
+	L1BaseFee     *big.Int
+	Overhead      *big.Int
+	Scalar        *big.Int
+	L1BlobBaseFee *big.Int
+	CommitScalar  *big.Int
+	BlobScalar    *big.Int
+}
+
+// Apply writes the overridden values into the L1GasPriceOracle storage slots,
+// so that both the L1 data fee computation and contracts reading the oracle
+// observe them.
+func (o *GPOOverride) Apply(state interface {
+	SetState(common.Address, common.Hash, common.Hash)
+}) {
+	if o == nil {
+		return
+	}
+	slots := []struct {
+		slot  common.Hash
+		value *big.Int
+	}{
+		{rcfg.L1BaseFeeSlot, o.L1BaseFee},
+		{rcfg.OverheadSlot, o.Overhead},
+		{rcfg.ScalarSlot, o.Scalar},
+		{rcfg.L1BlobBaseFeeSlot, o.L1BlobBaseFee},
+		{rcfg.CommitScalarSlot, o.CommitScalar},
+		{rcfg.BlobScalarSlot, o.BlobScalar},
+	}
+	for _, s := range slots {
+		if s.value != nil {
+			state.SetState(rcfg.L1GasPriceOracleAddress, s.slot, common.BigToHash(s.value))
+		}
+	}
+}
+
+// L1DataFeeBreakdown describes how the L1 data fee of a transaction is
+// composed. Before Curie only the commit component is non-zero. The total is
+// rounded once, so it can exceed the sum of the rounded components by one wei.
+type L1DataFeeBreakdown struct {
+	Curie     bool
+	TxSize    uint64
+	L1Gas     *big.Int // L1 gas charged for the calldata, pre-Curie only
+	Commit    *big.Int // commit (calldata) component
+	Blob      *big.Int // blob component, post-Curie only
+	L1DataFee *big.Int
+
+	L1BaseFee     *big.Int
+	Overhead      *big.Int
+	Scalar        *big.Int
+	L1BlobBaseFee *big.Int
+	CommitScalar  *big.Int
+	BlobScalar    *big.Int
+}
+
+// ZeroL1DataFeeBreakdown returns the breakdown of a transaction charged no L1
+// data fee, e.g. an L1 message or any transaction without the fee vault.
+func ZeroL1DataFeeBreakdown(config *params.ChainConfig, blockNumber *big.Int) *L1DataFeeBreakdown {
+	return &L1DataFeeBreakdown{
+		Curie:     config.IsCurie(blockNumber),
+		L1Gas:     big.NewInt(0),
+		Commit:    big.NewInt(0),
+		Blob:      big.NewInt(0),
+		L1DataFee: big.NewInt(0),
+	}
+}
+
 func EstimateL1DataFeeForMessage(msg Message, baseFee *big.Int, config *params.ChainConfig, signer types.Signer, state StateDB, blockNumber *big.Int) (*big.Int, error) {
+	breakdown, err := EstimateL1DataFeeBreakdownForMessage(msg, baseFee, config, signer, state, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return breakdown.L1DataFee, nil
+}
+
+// EstimateL1DataFeeBreakdownForMessage estimates the L1 data fee of a message
+// and returns it together with the oracle values and fee components it was
+// computed from.
+func EstimateL1DataFeeBreakdownForMessage(msg Message, baseFee *big.Int, config *params.ChainConfig, signer types.Signer, state StateDB, blockNumber *big.Int) (*L1DataFeeBreakdown, error) {
 	if msg.IsL1MessageTx() {
-		return big.NewInt(0), nil
+		return ZeroL1DataFeeBreakdown(config, blockNumber), nil
 	}
 
 	unsigned := asUnsignedTx(msg, baseFee, config.ChainID)
@@ -70,16 +151,7 @@ func EstimateL1DataFeeForMessage(msg Message, baseFee *big.Int, config *params.C
 	}
 
 	gpoState := readGPOStorageSlots(rcfg.L1GasPriceOracleAddress, state)
-
-	var l1DataFee *big.Int
-
-	if !config.IsCurie(blockNumber) {
-		l1DataFee = calculateEncodedL1DataFee(raw, gpoState.overhead, gpoState.l1BaseFee, gpoState.scalar)
-	} else {
-		l1DataFee = calculateEncodedL1DataFeeCurie(raw, gpoState.l1BaseFee, gpoState.l1BlobBaseFee, gpoState.commitScalar, gpoState.blobScalar)
-	}
-
-	return l1DataFee, nil
+	return calculateL1DataFeeBreakdown(raw, gpoState, config.IsCurie(blockNumber)), nil
 }
 
 // asUnsignedTx turns a Message into a types.Transaction
@@ -178,6 +250,34 @@ func calculateEncodedL1DataFeeCurie(data []byte, l1BaseFee *big.Int, l1BlobBaseF
 	return l1DataFee
 }
 
+// calculateL1DataFeeBreakdown computes the L1 fee of an RLP-encoded tx along
+// with its components.
+func calculateL1DataFeeBreakdown(data []byte, gpoState gpoState, isCurie bool) *L1DataFeeBreakdown {
+	b := &L1DataFeeBreakdown{
+		Curie:         isCurie,
+		TxSize:        uint64(len(data)),
+		L1BaseFee:     gpoState.l1BaseFee,
+		Overhead:      gpoState.overhead,
+		Scalar:        gpoState.scalar,
+		L1BlobBaseFee: gpoState.l1BlobBaseFee,
+		CommitScalar:  gpoState.commitScalar,
+		BlobScalar:    gpoState.blobScalar,
+	}
+	if !isCurie {
+		b.L1Gas = calculateL1GasUsed(data, gpoState.overhead)
+		b.L1DataFee = calculateEncodedL1DataFee(data, gpoState.overhead, gpoState.l1BaseFee, gpoState.scalar)
+		b.Commit = new(big.Int).Set(b.L1DataFee)
+		b.Blob = big.NewInt(0)
+		return b
+	}
+	b.L1Gas = big.NewInt(0)
+	b.Commit = mulAndScale(gpoState.commitScalar, gpoState.l1BaseFee, rcfg.Precision)
+	blobGas := new(big.Int).Mul(new(big.Int).SetUint64(b.TxSize), gpoState.l1BlobBaseFee)
+	b.Blob = mulAndScale(blobGas, gpoState.blobScalar, rcfg.Precision)
+	b.L1DataFee = calculateEncodedL1DataFeeCurie(data, gpoState.l1BaseFee, gpoState.l1BlobBaseFee, gpoState.commitScalar, gpoState.blobScalar)
+	return b
+}
+
 // calculateL1GasUsed computes the L1 gas used based on the calldata and
 // constant sized overhead. The overhead can be decreased as the cost of the
 // batch submission goes down via contract optimizations. This will not overflow
@@ -222,18 +322,47 @@ func CalculateL1DataFee(tx *types.Transaction, state StateDB, config *params.Cha
 	}
 
 	gpoState := readGPOStorageSlots(rcfg.L1GasPriceOracleAddress, state)
-
-	var l1DataFee *big.Int
-
-	if !config.IsCurie(blockNumber) {
-		l1DataFee = calculateEncodedL1DataFee(raw, gpoState.overhead, gpoState.l1BaseFee, gpoState.scalar)
-	} else {
-		l1DataFee = calculateEncodedL1DataFeeCurie(raw, gpoState.l1BaseFee, gpoState.l1BlobBaseFee, gpoState.commitScalar, gpoState.blobScalar)
-	}
-
-	return l1DataFee, nil
+	return calculateL1DataFeeBreakdown(raw, gpoState, config.IsCurie(blockNumber)).L1DataFee, nil
 }
 
 func GetL1BaseFee(state StateDB) *big.Int {
 	return state.GetState(rcfg.L1GasPriceOracleAddress, rcfg.L1BaseFeeSlot).Big()
 }
+
+// CalculateBlockFees splits the fees paid by the transactions of a block into
+// the L2 execution fee and the L1 data fee credited to the fee recipient, and
+// the base fee that was burned. It mirrors the fee payment of the state
+// transition and relies on the receipts carrying GasUsed and L1Fee. The L1 data
+// fee is only charged to the senders if the fee vault is enabled, and never to
+// L1 messages.
+func CalculateBlockFees(config *params.ChainConfig, header *types.Header, txs types.Transactions, receipts types.Receipts) *types.BlockFees {
+	blockFees := types.NewBlockFees()
+	burnBaseFee := config.IsCurie(header.Number) && !config.Scroll.FeeVaultEnabled()
+	chargeL1DataFee := config.Scroll.FeeVaultEnabled()
+
+	for i, tx := range txs {
+		if tx.IsL1MessageTx() || i >= len(receipts) {
+			continue
+		}
+		receipt := receipts[i]
+		gasUsed := new(big.Int).SetUint64(receipt.GasUsed)
+
+		gasPrice := tx.GasPrice()
+		if header.BaseFee != nil {
+			tip, _ := tx.EffectiveGasTip(header.BaseFee)
+			gasPrice = new(big.Int).Add(tip, header.BaseFee)
+		}
+		effectiveTip := gasPrice
+		if burnBaseFee {
+			effectiveTip, _ = tx.EffectiveGasTip(header.BaseFee)
+		}
+
+		l2Fee := new(big.Int).Mul(gasUsed, effectiveTip)
+		blockFees.L2Fee.Add(blockFees.L2Fee, l2Fee)
+		blockFees.BurnedFee.Add(blockFees.BurnedFee, new(big.Int).Sub(new(big.Int).Mul(gasUsed, gasPrice), l2Fee))
+		if chargeL1DataFee && receipt.L1Fee != nil {
+			blockFees.L1DataFee.Add(blockFees.L1DataFee, receipt.L1Fee)
+		}
+	}
+	return blockFees
+}
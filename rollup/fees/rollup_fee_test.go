@@ -5,6 +5,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/scroll-tech/go-ethereum/rollup/rcfg"
 )
 
 func TestL1DataFeeBeforeCurie(t *testing.T) {
@@ -31,3 +36,80 @@ func TestL1DataFeeAfterCurie(t *testing.T) {
 	actual := calculateEncodedL1DataFeeCurie(data, l1BaseFee, l1BlobBaseFee, commitScalar, blobScalar)
 	assert.Equal(t, expected, actual)
 }
+
+func TestL1DataFeeBreakdown(t *testing.T) {
+	data := []byte{0, 10, 1, 0}
+
+	before := calculateL1DataFeeBreakdown(data, gpoState{
+		l1BaseFee: new(big.Int).SetUint64(15000000),
+		overhead:  new(big.Int).SetUint64(100),
+		scalar:    new(big.Int).SetUint64(10),
+	}, false)
+	assert.Equal(t, new(big.Int).SetUint64(30), before.L1DataFee)
+	assert.Equal(t, before.L1DataFee, before.Commit)
+	assert.Equal(t, new(big.Int).SetUint64(204), before.L1Gas)
+	assert.Zero(t, before.Blob.Sign())
+
+	after := calculateL1DataFeeBreakdown(data, gpoState{
+		l1BaseFee:     new(big.Int).SetUint64(1500000000),
+		l1BlobBaseFee: new(big.Int).SetUint64(150000000),
+		commitScalar:  new(big.Int).SetUint64(10),
+		blobScalar:    new(big.Int).SetUint64(10),
+	}, true)
+	assert.Equal(t, new(big.Int).SetUint64(21), after.L1DataFee)
+	assert.Equal(t, new(big.Int).SetUint64(15), after.Commit)
+	assert.Equal(t, new(big.Int).SetUint64(6), after.Blob)
+	assert.Equal(t, uint64(len(data)), after.TxSize)
+}
+
+type mapState map[common.Hash]common.Hash
+
+func (s mapState) GetState(_ common.Address, key common.Hash) common.Hash { return s[key] }
+func (s mapState) GetBalance(common.Address) *big.Int                     { return new(big.Int) }
+func (s mapState) SetState(_ common.Address, key, value common.Hash)      { s[key] = value }
+
+func TestGPOOverride(t *testing.T) {
+	state := mapState{
+		rcfg.L1BaseFeeSlot: common.BigToHash(big.NewInt(1000)),
+		rcfg.ScalarSlot:    common.BigToHash(big.NewInt(10)),
+	}
+	override := &GPOOverride{L1BaseFee: big.NewInt(2000), BlobScalar: big.NewInt(5)}
+	override.Apply(state)
+
+	gpo := readGPOStorageSlots(rcfg.L1GasPriceOracleAddress, state)
+	assert.Equal(t, big.NewInt(2000), gpo.l1BaseFee)
+	assert.Equal(t, big.NewInt(10), gpo.scalar)
+	assert.Equal(t, big.NewInt(5), gpo.blobScalar)
+
+	// a nil override must leave the state untouched
+	(*GPOOverride)(nil).Apply(state)
+	assert.Equal(t, big.NewInt(2000), GetL1BaseFee(state))
+}
+
+func TestCalculateBlockFees(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(10), BaseFee: big.NewInt(100)}
+	txs := types.Transactions{
+		types.NewTx(&types.DynamicFeeTx{GasTipCap: big.NewInt(10), GasFeeCap: big.NewInt(200)}),
+		types.NewTx(&types.LegacyTx{GasPrice: big.NewInt(150)}),
+		types.NewTx(&types.L1MessageTx{}),
+	}
+	receipts := types.Receipts{
+		{GasUsed: 1000, L1Fee: big.NewInt(7)},
+		{GasUsed: 2000, L1Fee: big.NewInt(3)},
+		{GasUsed: 5000},
+	}
+
+	// base fee is burned if the fee vault is disabled
+	config := &params.ChainConfig{CurieBlock: big.NewInt(0)}
+	blockFees := CalculateBlockFees(config, header, txs, receipts)
+	assert.Equal(t, big.NewInt(1000*10+2000*50), blockFees.L2Fee)
+	assert.Equal(t, big.NewInt(1000*100+2000*100), blockFees.BurnedFee)
+	assert.Zero(t, blockFees.L1DataFee.Sign()) // not charged without the fee vault
+
+	// everything goes to the fee vault otherwise
+	config.Scroll.FeeVaultAddress = &rcfg.ScrollFeeVaultAddress
+	blockFees = CalculateBlockFees(config, header, txs, receipts)
+	assert.Equal(t, big.NewInt(1000*110+2000*150), blockFees.L2Fee)
+	assert.Zero(t, blockFees.BurnedFee.Sign())
+	assert.Equal(t, big.NewInt(10), blockFees.L1DataFee)
+}
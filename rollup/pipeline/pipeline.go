@@ -165,6 +165,21 @@ func (p *Pipeline) TryPushTxn(tx *types.Transaction) (*Result, error) {
 	}
 }
 
+// Finish signals that no more transactions will be pushed to the pipeline and
+// waits for the final result. It returns nil if the result was already returned
+// by TryPushTxns or TryPushTxn.
+func (p *Pipeline) Finish() *Result {
+	if p.txnQueue != nil {
+		close(p.txnQueue)
+		p.txnQueue = nil
+	}
+
+	res := <-p.ResultCh
+	for range p.applyStageRespCh {
+	}
+	return res
+}
+
 func (p *Pipeline) Kill() {
 	if p.txnQueue != nil {
 		close(p.txnQueue)
@@ -0,0 +1,205 @@
+package rollup_sync_service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/crypto/kzg4844"
+	"github.com/scroll-tech/go-ethereum/rollup/types/encoding/codecv1"
+)
+
+const (
+	// defaultBeaconRequestTimeout is the timeout of a single beacon node API request.
+	defaultBeaconRequestTimeout = 15 * time.Second
+
+	// lenBlobBytes is the length of an EIP-4844 blob in bytes.
+	lenBlobBytes = len(kzg4844.Blob{})
+)
+
+// BlobClient retrieves the EIP-4844 blobs of codecv1 batches.
+type BlobClient interface {
+	// GetBlobByVersionedHashAndBlockTime returns the blob with the given
+	// versioned hash included in the L1 block with the given timestamp.
+	// Returned blobs are verified against their versioned hash.
+	GetBlobByVersionedHashAndBlockTime(ctx context.Context, versionedHash common.Hash, blockTime uint64) (*kzg4844.Blob, error)
+}
+
+// verifyBlob checks that the KZG commitment of the blob matches the versioned hash.
+func verifyBlob(blob *kzg4844.Blob, versionedHash common.Hash) error {
+	commitment, err := kzg4844.BlobToCommitment(blob)
+	if err != nil {
+		return fmt.Errorf("failed to create blob commitment: %w", err)
+	}
+	if have := common.Hash(kzg4844.CalcBlobHashV1(sha256.New(), &commitment)); have != versionedHash {
+		return fmt.Errorf("blob versioned hash mismatch, have: %v, want: %v", have.Hex(), versionedHash.Hex())
+	}
+	return nil
+}
+
+// GetBatchTransactionsFromBlob retrieves the blob of a codecv1 batch and decodes
+// the L2 transactions of the encoded chunks from it. The versioned hash must be
+// the BlobVersionedHash committed for the batch, the block time the timestamp of
+// the L1 block including the commit batch transaction.
+func GetBatchTransactionsFromBlob(ctx context.Context, client BlobClient, chunks [][]byte, versionedHash common.Hash, blockTime uint64) ([]*codecv1.DAChunkRawTx, error) {
+	daChunks, err := codecv1.DecodeDAChunksRawTx(chunks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode chunks: %w", err)
+	}
+	blob, err := client.GetBlobByVersionedHashAndBlockTime(ctx, versionedHash, blockTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blob, versioned hash: %v, err: %w", versionedHash.Hex(), err)
+	}
+	if err := codecv1.DecodeTxsFromBlob(blob, daChunks); err != nil {
+		return nil, fmt.Errorf("failed to decode blob, versioned hash: %v, err: %w", versionedHash.Hex(), err)
+	}
+	return daChunks, nil
+}
+
+// BeaconNodeClient retrieves blobs from the blob sidecars API of a beacon node.
+type BeaconNodeClient struct {
+	apiEndpoint    string
+	client         *http.Client
+	genesisTime    uint64
+	secondsPerSlot uint64
+}
+
+// NewBeaconNodeClient creates a BeaconNodeClient for the given beacon API
+// endpoint, loading the genesis time and slot duration of the beacon chain.
+func NewBeaconNodeClient(ctx context.Context, apiEndpoint string) (*BeaconNodeClient, error) {
+	c := &BeaconNodeClient{
+		apiEndpoint: apiEndpoint,
+		client:      &http.Client{Timeout: defaultBeaconRequestTimeout},
+	}
+
+	var genesis struct {
+		Data struct {
+			GenesisTime string `json:"genesis_time"`
+		} `json:"data"`
+	}
+	if err := c.get(ctx, "eth/v1/beacon/genesis", &genesis); err != nil {
+		return nil, fmt.Errorf("failed to query beacon genesis: %w", err)
+	}
+	genesisTime, err := strconv.ParseUint(genesis.Data.GenesisTime, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid beacon genesis time %q: %w", genesis.Data.GenesisTime, err)
+	}
+
+	var spec struct {
+		Data struct {
+			SecondsPerSlot string `json:"SECONDS_PER_SLOT"`
+		} `json:"data"`
+	}
+	if err := c.get(ctx, "eth/v1/config/spec", &spec); err != nil {
+		return nil, fmt.Errorf("failed to query beacon spec: %w", err)
+	}
+	secondsPerSlot, err := strconv.ParseUint(spec.Data.SecondsPerSlot, 10, 64)
+	if err != nil || secondsPerSlot == 0 {
+		return nil, fmt.Errorf("invalid beacon slot duration %q", spec.Data.SecondsPerSlot)
+	}
+
+	c.genesisTime, c.secondsPerSlot = genesisTime, secondsPerSlot
+	return c, nil
+}
+
+// get performs a GET request against the beacon API and decodes the JSON response.
+func (c *BeaconNodeClient) get(ctx context.Context, path string, result interface{}) error {
+	u, err := url.JoinPath(c.apiEndpoint, path)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("beacon API request %v failed with status %v", path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// GetBlobByVersionedHashAndBlockTime implements BlobClient.
+func (c *BeaconNodeClient) GetBlobByVersionedHashAndBlockTime(ctx context.Context, versionedHash common.Hash, blockTime uint64) (*kzg4844.Blob, error) {
+	if blockTime < c.genesisTime {
+		return nil, fmt.Errorf("block time %v is before beacon genesis %v", blockTime, c.genesisTime)
+	}
+	slot := (blockTime - c.genesisTime) / c.secondsPerSlot
+
+	var sidecars struct {
+		Data []struct {
+			Blob          hexutil.Bytes `json:"blob"`
+			KZGCommitment hexutil.Bytes `json:"kzg_commitment"`
+		} `json:"data"`
+	}
+	if err := c.get(ctx, fmt.Sprintf("eth/v1/beacon/blob_sidecars/%d", slot), &sidecars); err != nil {
+		return nil, fmt.Errorf("failed to query blob sidecars of slot %d: %w", slot, err)
+	}
+	for _, sidecar := range sidecars.Data {
+		var commitment kzg4844.Commitment
+		if len(sidecar.KZGCommitment) != len(commitment) {
+			continue
+		}
+		copy(commitment[:], sidecar.KZGCommitment)
+		if common.Hash(kzg4844.CalcBlobHashV1(sha256.New(), &commitment)) != versionedHash {
+			continue
+		}
+		if len(sidecar.Blob) != lenBlobBytes {
+			return nil, fmt.Errorf("invalid blob length %d in slot %d", len(sidecar.Blob), slot)
+		}
+		blob := new(kzg4844.Blob)
+		copy(blob[:], sidecar.Blob)
+
+		// the commitment is advertised by the beacon node, check it against the blob
+		if err := verifyBlob(blob, versionedHash); err != nil {
+			return nil, err
+		}
+		return blob, nil
+	}
+	return nil, fmt.Errorf("blob %v not found in slot %d", versionedHash.Hex(), slot)
+}
+
+// BlobDirClient retrieves blobs from a local directory holding one file per
+// blob, named by its versioned hash. It is meant to stand in for a beacon node.
+type BlobDirClient struct {
+	dir string
+}
+
+// NewBlobDirClient creates a BlobDirClient reading blobs from the given directory.
+func NewBlobDirClient(dir string) *BlobDirClient {
+	return &BlobDirClient{dir: dir}
+}
+
+// GetBlobByVersionedHashAndBlockTime implements BlobClient.
+func (c *BlobDirClient) GetBlobByVersionedHashAndBlockTime(ctx context.Context, versionedHash common.Hash, blockTime uint64) (*kzg4844.Blob, error) {
+	data, err := os.ReadFile(filepath.Join(c.dir, versionedHash.Hex()))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != lenBlobBytes {
+		return nil, errors.New("invalid blob file length")
+	}
+	blob := new(kzg4844.Blob)
+	copy(blob[:], data)
+
+	if err := verifyBlob(blob, versionedHash); err != nil {
+		return nil, err
+	}
+	return blob, nil
+}
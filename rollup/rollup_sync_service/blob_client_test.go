@@ -0,0 +1,130 @@
+package rollup_sync_service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto/kzg4844"
+
+	"github.com/scroll-tech/go-ethereum/rollup/types/encoding"
+	"github.com/scroll-tech/go-ethereum/rollup/types/encoding/codecv1"
+)
+
+// newTestBlobBatch builds a codecv1 batch from the block traces in testdata.
+func newTestBlobBatch(t *testing.T) ([]*encoding.Chunk, *codecv1.DABatch) {
+	block2 := readBlockFromJSON(t, "./testdata/blockTrace_02.json")
+	block3 := readBlockFromJSON(t, "./testdata/blockTrace_03.json")
+	block4 := readBlockFromJSON(t, "./testdata/blockTrace_04.json")
+	chunks := []*encoding.Chunk{
+		{Blocks: []*encoding.Block{block2, block3}},
+		{Blocks: []*encoding.Block{block4}},
+	}
+	batch, err := codecv1.NewDABatch(&encoding.Batch{Index: 1, Chunks: chunks})
+	require.NoError(t, err)
+	return chunks, batch
+}
+
+func TestBlobDirClient(t *testing.T) {
+	chunks, batch := newTestBlobBatch(t)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, batch.BlobVersionedHash.Hex()), batch.Blob()[:], 0644))
+	client := NewBlobDirClient(dir)
+
+	blob, err := client.GetBlobByVersionedHashAndBlockTime(context.Background(), batch.BlobVersionedHash, 0)
+	require.NoError(t, err)
+	assert.Equal(t, batch.Blob(), blob)
+
+	// Decode the transactions from the blob as the commit batch calldata would be
+	var encodedChunks [][]byte
+	for _, chunk := range chunks {
+		daChunk, err := codecv1.NewDAChunk(chunk, 0)
+		require.NoError(t, err)
+		encodedChunks = append(encodedChunks, daChunk.Encode())
+	}
+	daChunks, err := GetBatchTransactionsFromBlob(context.Background(), client, encodedChunks, batch.BlobVersionedHash, 0)
+	require.NoError(t, err)
+
+	for i, chunk := range chunks {
+		for j, block := range chunk.Blocks {
+			var want []string
+			for _, tx := range block.Transactions {
+				if tx.Type != types.L1MessageTxType {
+					data, err := encoding.ConvertTxDataToRLPEncoding(tx)
+					require.NoError(t, err)
+					want = append(want, hexutil.Encode(data))
+				}
+			}
+			var have []string
+			for _, tx := range daChunks[i].Transactions[j] {
+				data, err := tx.MarshalBinary()
+				require.NoError(t, err)
+				have = append(have, hexutil.Encode(data))
+			}
+			assert.Equal(t, want, have, "chunk %d block %d", i, j)
+		}
+	}
+
+	// Blobs not matching their versioned hash are rejected
+	corrupt := *blob
+	corrupt[1] ^= 0xff
+	require.NoError(t, os.WriteFile(filepath.Join(dir, batch.BlobVersionedHash.Hex()), corrupt[:], 0644))
+	_, err = client.GetBlobByVersionedHashAndBlockTime(context.Background(), batch.BlobVersionedHash, 0)
+	assert.Error(t, err)
+
+	_, err = client.GetBlobByVersionedHashAndBlockTime(context.Background(), common.Hash{1}, 0)
+	assert.Error(t, err)
+}
+
+func TestBeaconNodeClient(t *testing.T) {
+	_, batch := newTestBlobBatch(t)
+	commitment, err := kzg4844.BlobToCommitment(batch.Blob())
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/eth/v1/beacon/genesis", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"genesis_time":"1000"}}`)
+	})
+	mux.HandleFunc("/eth/v1/config/spec", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"SECONDS_PER_SLOT":"12"}}`)
+	})
+	mux.HandleFunc("/eth/v1/beacon/blob_sidecars/5", func(w http.ResponseWriter, r *http.Request) {
+		var other kzg4844.Blob
+		otherCommitment, _ := kzg4844.BlobToCommitment(&other)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": []map[string]interface{}{
+				{"index": "0", "blob": hexutil.Bytes(other[:]), "kzg_commitment": hexutil.Bytes(otherCommitment[:])},
+				{"index": "1", "blob": hexutil.Bytes(batch.Blob()[:]), "kzg_commitment": hexutil.Bytes(commitment[:])},
+			},
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewBeaconNodeClient(context.Background(), server.URL)
+	require.NoError(t, err)
+
+	// Slot 5 starts at 1000 + 5*12
+	blob, err := client.GetBlobByVersionedHashAndBlockTime(context.Background(), batch.BlobVersionedHash, 1065)
+	require.NoError(t, err)
+	assert.Equal(t, batch.Blob(), blob)
+
+	_, err = client.GetBlobByVersionedHashAndBlockTime(context.Background(), common.Hash{1}, 1065)
+	assert.Error(t, err)
+	_, err = client.GetBlobByVersionedHashAndBlockTime(context.Background(), batch.BlobVersionedHash, 1100)
+	assert.Error(t, err)
+	_, err = client.GetBlobByVersionedHashAndBlockTime(context.Background(), batch.BlobVersionedHash, 500)
+	assert.Error(t, err)
+}
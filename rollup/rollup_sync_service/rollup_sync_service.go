@@ -14,6 +14,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/core/rawdb"
 	"github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/ethdb"
+	"github.com/scroll-tech/go-ethereum/event"
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/scroll-tech/go-ethereum/node"
 	"github.com/scroll-tech/go-ethereum/params"
@@ -45,6 +46,24 @@ const (
 	defaultLogInterval = 5 * time.Minute
 )
 
+// Statuses of the batches reported by BatchEvent.
+const (
+	BatchCommitted = "committed"
+	BatchReverted  = "reverted"
+	BatchFinalized = "finalized"
+)
+
+// BatchEvent is posted when a batch is committed, reverted or finalized on L1.
+type BatchEvent struct {
+	Status        string
+	BatchIndex    uint64
+	BatchHash     common.Hash
+	StartBlock    uint64 // First L2 block of the batch, zero if unknown
+	EndBlock      uint64 // Last L2 block of the batch, zero if unknown
+	L1BlockNumber uint64
+	L1TxHash      common.Hash
+}
+
 // RollupSyncService collects ScrollChain batch commit/revert/finalize events and stores metadata into db.
 type RollupSyncService struct {
 	ctx                           context.Context
@@ -58,6 +77,8 @@ type RollupSyncService struct {
 	l1FinalizeBatchEventSignature common.Hash
 	bc                            *core.BlockChain
 	stack                         *node.Node
+	batchFeed                     event.Feed
+	scope                         event.SubscriptionScope
 }
 
 func NewRollupSyncService(ctx context.Context, genesisConfig *params.ChainConfig, db ethdb.Database, l1Client sync_service.EthClient, bc *core.BlockChain, stack *node.Node) (*RollupSyncService, error) {
@@ -147,11 +168,20 @@ func (s *RollupSyncService) Stop() {
 
 	log.Info("Stopping rollup event sync background service")
 
+	// Unsubscribe all subscriptions registered
+	s.scope.Close()
+
 	if s.cancel != nil {
 		s.cancel()
 	}
 }
 
+// SubscribeBatchEvent registers a subscription of BatchEvent, posted once the
+// batch events of a range of L1 blocks are processed.
+func (s *RollupSyncService) SubscribeBatchEvent(ch chan<- BatchEvent) event.Subscription {
+	return s.scope.Track(s.batchFeed.Subscribe(ch))
+}
+
 func (s *RollupSyncService) fetchRollupEvents() {
 	latestConfirmed, err := s.client.getLatestFinalizedBlockNumber()
 	if err != nil {
@@ -189,6 +219,7 @@ func (s *RollupSyncService) fetchRollupEvents() {
 }
 
 func (s *RollupSyncService) parseAndUpdateRollupEventLogs(logs []types.Log, endBlockNumber uint64) error {
+	events := make([]BatchEvent, 0, len(logs))
 	for _, vLog := range logs {
 		switch vLog.Topics[0] {
 		case s.l1CommitBatchEventSignature:
@@ -204,6 +235,7 @@ func (s *RollupSyncService) parseAndUpdateRollupEventLogs(logs []types.Log, endB
 				return fmt.Errorf("failed to get chunk ranges, batch index: %v, err: %w", batchIndex, err)
 			}
 			rawdb.WriteBatchChunkRanges(s.db, batchIndex, chunkBlockRanges)
+			events = append(events, newBatchEvent(BatchCommitted, batchIndex, event.BatchHash, chunkBlockRanges, &vLog))
 
 		case s.l1RevertBatchEventSignature:
 			event := &L1RevertBatchEvent{}
@@ -213,7 +245,9 @@ func (s *RollupSyncService) parseAndUpdateRollupEventLogs(logs []types.Log, endB
 			batchIndex := event.BatchIndex.Uint64()
 			log.Trace("found new RevertBatch event", "batch index", batchIndex)
 
+			chunkBlockRanges := rawdb.ReadBatchChunkRanges(s.db, batchIndex)
 			rawdb.DeleteBatchChunkRanges(s.db, batchIndex)
+			events = append(events, newBatchEvent(BatchReverted, batchIndex, event.BatchHash, chunkBlockRanges, &vLog))
 
 		case s.l1FinalizeBatchEventSignature:
 			event := &L1FinalizeBatchEvent{}
@@ -235,6 +269,12 @@ func (s *RollupSyncService) parseAndUpdateRollupEventLogs(logs []types.Log, endB
 
 			rawdb.WriteFinalizedL2BlockNumber(s.db, endBlock)
 			rawdb.WriteFinalizedBatchMeta(s.db, batchIndex, finalizedBatchMeta)
+			finalized := newBatchEvent(BatchFinalized, batchIndex, event.BatchHash, nil, &vLog)
+			if len(chunks) > 0 && len(chunks[0].Blocks) > 0 {
+				finalized.StartBlock = chunks[0].Blocks[0].Header.Number.Uint64()
+			}
+			finalized.EndBlock = endBlock
+			events = append(events, finalized)
 
 			if batchIndex%100 == 0 {
 				log.Info("finalized batch progress", "batch index", batchIndex, "finalized l2 block height", endBlock)
@@ -249,9 +289,31 @@ func (s *RollupSyncService) parseAndUpdateRollupEventLogs(logs []types.Log, endB
 	// before this line and reexecute the previous steps, we will
 	// get the same result.
 	rawdb.WriteRollupEventSyncedL1BlockNumber(s.db, endBlockNumber)
+
+	// notify the events only once processed, as they are replayed on failure
+	for _, event := range events {
+		s.batchFeed.Send(event)
+	}
 	return nil
 }
 
+// newBatchEvent returns the event of a batch emitted by the given log. The block
+// range of the batch is known from its chunk block ranges, if any.
+func newBatchEvent(status string, batchIndex uint64, batchHash common.Hash, chunkBlockRanges []*rawdb.ChunkBlockRange, vLog *types.Log) BatchEvent {
+	event := BatchEvent{
+		Status:        status,
+		BatchIndex:    batchIndex,
+		BatchHash:     batchHash,
+		L1BlockNumber: vLog.BlockNumber,
+		L1TxHash:      vLog.TxHash,
+	}
+	if n := len(chunkBlockRanges); n > 0 {
+		event.StartBlock = chunkBlockRanges[0].StartBlockNumber
+		event.EndBlock = chunkBlockRanges[n-1].EndBlockNumber
+	}
+	return event
+}
+
 func (s *RollupSyncService) getLocalInfoForBatch(batchIndex uint64) (*rawdb.FinalizedBatchMeta, []*encoding.Chunk, error) {
 	chunkBlockRanges := rawdb.ReadBatchChunkRanges(s.db, batchIndex)
 	if len(chunkBlockRanges) == 0 {
@@ -486,19 +548,11 @@ func decodeBlockRangesFromEncodedChunks(codecVersion encoding.CodecVersion, chun
 				EndBlockNumber:   daBlocks[len(daBlocks)-1].BlockNumber,
 			})
 		case encoding.CodecV1:
-			if len(chunk) != 1+numBlocks*60 {
-				return nil, fmt.Errorf("invalid chunk byte length, expected: %v, got: %v", 1+numBlocks*60, len(chunk))
-			}
-			daBlocks := make([]*codecv1.DABlock, numBlocks)
-			for i := 0; i < numBlocks; i++ {
-				startIdx := 1 + i*60 // add 1 to skip numBlocks byte
-				endIdx := startIdx + 60
-				daBlock, err := codecv1.DecodeDABlock(chunk[startIdx:endIdx])
-				if err != nil {
-					return nil, err
-				}
-				daBlocks[i] = daBlock
+			daChunks, err := codecv1.DecodeDAChunksRawTx([][]byte{chunk})
+			if err != nil {
+				return nil, err
 			}
+			daBlocks := daChunks[0].Blocks
 
 			chunkBlockRanges = append(chunkBlockRanges, &rawdb.ChunkBlockRange{
 				StartBlockNumber: daBlocks[0].BlockNumber,
@@ -211,6 +211,69 @@ func TestGetChunkRangesCodecv0(t *testing.T) {
 	}
 }
 
+func TestParseRollupEventLogsBatchEvents(t *testing.T) {
+	genesisConfig := &params.ChainConfig{
+		Scroll: params.ScrollConfig{
+			L1Config: &params.L1Config{
+				L1ChainId:          11155111,
+				ScrollChainAddress: common.HexToAddress("0x2D567EcE699Eabe5afCd141eDB7A4f2D0D6ce8a0"),
+			},
+		},
+	}
+	db := rawdb.NewDatabase(memorydb.New())
+
+	rlpData, err := os.ReadFile("./testdata/commitBatch_codecv0.rlp")
+	if err != nil {
+		t.Fatalf("Failed to read RLP data: %v", err)
+	}
+	l1Client := &mockEthClient{
+		commitBatchRLP: rlpData,
+	}
+	bc := &core.BlockChain{}
+	stack, err := node.New(&node.DefaultConfig)
+	if err != nil {
+		t.Fatalf("Failed to new P2P node: %v", err)
+	}
+	defer stack.Close()
+	service, err := NewRollupSyncService(context.Background(), genesisConfig, db, l1Client, bc, stack)
+	if err != nil {
+		t.Fatalf("Failed to new rollup sync service: %v", err)
+	}
+	events := make(chan BatchEvent, 2)
+	sub := service.SubscribeBatchEvent(events)
+	defer sub.Unsubscribe()
+
+	batchIndex := common.BigToHash(big.NewInt(1))
+	batchHash := common.HexToHash("0x1234")
+	logs := []types.Log{
+		{
+			Topics:      []common.Hash{service.l1CommitBatchEventSignature, batchIndex, batchHash},
+			BlockNumber: 100,
+			TxHash:      common.HexToHash("0x0"),
+		},
+		{
+			Topics:      []common.Hash{service.l1RevertBatchEventSignature, batchIndex, batchHash},
+			BlockNumber: 101,
+			TxHash:      common.HexToHash("0x1"),
+		},
+	}
+	require.NoError(t, service.parseAndUpdateRollupEventLogs(logs, 101))
+
+	expected := []BatchEvent{
+		{Status: BatchCommitted, BatchIndex: 1, BatchHash: batchHash, StartBlock: 911145, EndBlock: 911159, L1BlockNumber: 100, L1TxHash: common.HexToHash("0x0")},
+		{Status: BatchReverted, BatchIndex: 1, BatchHash: batchHash, StartBlock: 911145, EndBlock: 911159, L1BlockNumber: 101, L1TxHash: common.HexToHash("0x1")},
+	}
+	for i := range expected {
+		select {
+		case event := <-events:
+			assert.Equal(t, expected[i], event)
+		case <-time.After(time.Second):
+			t.Fatalf("Missing batch event %d", i)
+		}
+	}
+	assert.Nil(t, rawdb.ReadBatchChunkRanges(db, 1))
+}
+
 func TestGetChunkRangesCodecv1(t *testing.T) {
 	genesisConfig := &params.ChainConfig{
 		Scroll: params.ScrollConfig{
@@ -0,0 +1,185 @@
+package sequencer
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/metrics"
+)
+
+const (
+	// DefaultLeaseTTL is the default validity of the sequencer lease, a standby
+	// takes over at most this long after the leader stopped renewing it.
+	DefaultLeaseTTL = 15 * time.Second
+)
+
+var (
+	// ErrNotLeader is returned when sealing a block without holding the lease.
+	ErrNotLeader = errors.New("sequencer lease held by another node")
+
+	// ErrHeightSigned is returned when sealing a block at a height that was
+	// already signed by a (previous) leader.
+	ErrHeightSigned = errors.New("block height already signed by sequencer leader")
+
+	leaderGauge = metrics.NewRegisteredGauge("sequencer/leader", nil)
+)
+
+// Elector keeps acquiring and renewing the sequencer lease in the background
+// and guards block sealing so that only the leader signs new blocks.
+type Elector struct {
+	lease  Lease
+	holder string
+	ttl    time.Duration
+
+	mu         sync.Mutex
+	validUntil time.Time // Local deadline of the held lease, zero if not leader
+	height     uint64    // Highest block number signed by any leader
+	sealed     uint64    // Highest block number signed by this node
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewElector creates an elector competing for the lease as holder.
+func NewElector(lease Lease, holder string, ttl time.Duration) *Elector {
+	if ttl <= 0 {
+		ttl = DefaultLeaseTTL
+	}
+	return &Elector{
+		lease:  lease,
+		holder: holder,
+		ttl:    ttl,
+		quit:   make(chan struct{}),
+	}
+}
+
+// Start starts competing for the lease.
+func (e *Elector) Start() {
+	log.Info("Starting sequencer leader election", "holder", e.holder, "ttl", e.ttl)
+	e.renew()
+
+	e.wg.Add(1)
+	go e.loop()
+}
+
+// Stop stops renewing the lease and releases it if held, letting a standby
+// take over without waiting for the expiry.
+func (e *Elector) Stop() {
+	close(e.quit)
+	e.wg.Wait()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.validUntil.IsZero() {
+		if err := e.lease.Release(e.holder); err != nil {
+			log.Warn("Failed to release sequencer lease", "err", err)
+		}
+		e.validUntil = time.Time{}
+	}
+	log.Info("Sequencer leader election stopped")
+}
+
+func (e *Elector) loop() {
+	defer e.wg.Done()
+
+	// Renew often enough that the lease never lapses between two renewals
+	renew := time.NewTicker(e.ttl / 3)
+	defer renew.Stop()
+
+	for {
+		select {
+		case <-renew.C:
+			e.renew()
+		case <-e.quit:
+			return
+		}
+	}
+}
+
+// renew acquires or extends the lease. The local deadline is shorter than the
+// lease expiry to leave room for clock drift between the nodes.
+func (e *Elector) renew() {
+	start := time.Now()
+	record, acquired, err := e.lease.Acquire(e.holder, start.Add(e.ttl))
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	wasLeader := e.isLeader()
+	switch {
+	case err != nil:
+		log.Warn("Failed to renew sequencer lease", "err", err)
+	case acquired:
+		e.validUntil = start.Add(e.ttl - e.ttl/4)
+		e.height = record.Height
+	default:
+		e.validUntil = time.Time{}
+		e.height = record.Height
+	}
+	if leader := e.isLeader(); leader != wasLeader {
+		if leader {
+			log.Info("Acquired sequencer lease", "holder", e.holder, "height", e.height)
+			leaderGauge.Update(1)
+		} else {
+			log.Warn("Lost sequencer lease", "holder", e.holder, "leader", record.holderOrUnknown())
+			leaderGauge.Update(0)
+		}
+	}
+}
+
+func (e *Elector) isLeader() bool {
+	return time.Now().Before(e.validUntil)
+}
+
+// IsLeader returns whether the local node currently holds the lease.
+func (e *Elector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.isLeader()
+}
+
+// AllowSeal returns an error if the local node must not seal a block with the
+// given number, either because it is not the leader or because the height was
+// already signed.
+func (e *Elector) AllowSeal(number uint64) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.isLeader() {
+		return ErrNotLeader
+	}
+	// Re-sealing our own latest block is not a double-sign, the sealer
+	// makes sure that the header is the same.
+	if number < e.height || (number == e.height && number != e.sealed) {
+		return fmt.Errorf("%w: number %d, signed %d", ErrHeightSigned, number, e.height)
+	}
+	return nil
+}
+
+// Sealed records that a block with the given number was signed, so that no
+// other leader signs the same height.
+func (e *Elector) Sealed(number uint64) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := e.lease.SetHeight(e.holder, number); err != nil {
+		return err
+	}
+	if number > e.height {
+		e.height = number
+	}
+	e.sealed = number
+	return nil
+}
+
+func (r *LeaseRecord) holderOrUnknown() string {
+	if r == nil || r.Holder == "" {
+		return "unknown"
+	}
+	return r.Holder
+}
@@ -0,0 +1,41 @@
+package sequencer
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElectorFailover(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lease")
+	ttl := 300 * time.Millisecond
+
+	active := NewElector(NewFileLease(path), "active", ttl)
+	active.Start()
+	standby := NewElector(NewFileLease(path), "standby", ttl)
+	standby.Start()
+	defer standby.Stop()
+
+	require.True(t, active.IsLeader())
+	require.False(t, standby.IsLeader())
+	assert.ErrorIs(t, standby.AllowSeal(1), ErrNotLeader)
+
+	require.NoError(t, active.AllowSeal(1))
+	require.NoError(t, active.Sealed(1))
+	require.NoError(t, active.AllowSeal(1)) // Re-sealing own block is allowed
+	require.NoError(t, active.AllowSeal(2))
+	require.NoError(t, active.Sealed(2))
+	assert.ErrorIs(t, active.AllowSeal(1), ErrHeightSigned)
+
+	// Stopping the leader releases the lease and the standby takes over
+	active.Stop()
+	assert.Eventually(t, standby.IsLeader, 2*ttl, 10*time.Millisecond)
+
+	// The new leader must not sign heights already signed by the old one
+	assert.ErrorIs(t, standby.AllowSeal(1), ErrHeightSigned)
+	assert.ErrorIs(t, standby.AllowSeal(2), ErrHeightSigned)
+	assert.NoError(t, standby.AllowSeal(3))
+}
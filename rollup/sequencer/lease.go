@@ -0,0 +1,154 @@
+package sequencer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/prometheus/tsdb/fileutil"
+)
+
+const (
+	// lockTimeout is the maximum time to wait for the lease lock file.
+	lockTimeout = time.Second
+
+	// lockRetryInterval is the interval between attempts to take the lease lock file.
+	lockRetryInterval = 10 * time.Millisecond
+)
+
+var (
+	// ErrLeaseNotHeld is returned when updating a lease that is held by another node.
+	ErrLeaseNotHeld = errors.New("sequencer lease not held")
+)
+
+// LeaseRecord is the shared state of the sequencer leader lease.
+type LeaseRecord struct {
+	Holder string    `json:"holder"` // Identifier of the current leader
+	Expiry time.Time `json:"expiry"` // Time after which the lease can be taken over
+	Height uint64    `json:"height"` // Highest block number signed by any leader
+}
+
+// Lease is a backend for the sequencer leader lease. Implementations must make
+// every operation atomic across all nodes sharing the lease.
+type Lease interface {
+	// Acquire acquires the lease for holder until expiry if it is free, expired
+	// or already held by holder, and returns the resulting record.
+	Acquire(holder string, expiry time.Time) (*LeaseRecord, bool, error)
+
+	// SetHeight records the height of a block signed by holder.
+	SetHeight(holder string, height uint64) error
+
+	// Release gives up the lease if it is held by holder.
+	Release(holder string) error
+}
+
+// FileLease is a Lease stored in a file, e.g. on storage shared by the active
+// and the standby sequencer. Updates are serialized by a lock file next to it.
+type FileLease struct {
+	path string
+	lock sync.Mutex // Serializes local updates, file locks are not goroutine-safe
+}
+
+// NewFileLease creates a lease backed by the file at path.
+func NewFileLease(path string) *FileLease {
+	return &FileLease{path: path}
+}
+
+// Acquire implements Lease.
+func (l *FileLease) Acquire(holder string, expiry time.Time) (*LeaseRecord, bool, error) {
+	var acquired bool
+	record, err := l.update(func(record *LeaseRecord) (bool, error) {
+		if record.Holder != holder && record.Holder != "" && time.Now().Before(record.Expiry) {
+			return false, nil
+		}
+		record.Holder, record.Expiry = holder, expiry
+		acquired = true
+		return true, nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return record, acquired, nil
+}
+
+// SetHeight implements Lease.
+func (l *FileLease) SetHeight(holder string, height uint64) error {
+	_, err := l.update(func(record *LeaseRecord) (bool, error) {
+		if record.Holder != holder {
+			return false, ErrLeaseNotHeld
+		}
+		if height > record.Height {
+			record.Height = height
+		}
+		return true, nil
+	})
+	return err
+}
+
+// Release implements Lease.
+func (l *FileLease) Release(holder string) error {
+	_, err := l.update(func(record *LeaseRecord) (bool, error) {
+		if record.Holder != holder {
+			return false, nil
+		}
+		record.Holder, record.Expiry = "", time.Time{}
+		return true, nil
+	})
+	return err
+}
+
+// update applies fn to the stored record while holding the lock file, writing
+// the record back if fn reports a modification.
+func (l *FileLease) update(fn func(record *LeaseRecord) (bool, error)) (*LeaseRecord, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	// Other nodes only hold the lock for a single update, retry for a while
+	var (
+		release  fileutil.Releaser
+		err      error
+		deadline = time.Now().Add(lockTimeout)
+	)
+	for {
+		if release, _, err = fileutil.Flock(l.path + ".lock"); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("failed to lock sequencer lease: %w", err)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+	defer release.Release()
+
+	record := new(LeaseRecord)
+	blob, err := ioutil.ReadFile(l.path)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(blob, record); err != nil {
+			return nil, fmt.Errorf("invalid sequencer lease file: %w", err)
+		}
+	}
+	modified, err := fn(record)
+	if err != nil || !modified {
+		return record, err
+	}
+	if blob, err = json.Marshal(record); err != nil {
+		return nil, err
+	}
+	// Write to a temporary file first so that a crash never leaves a partial record
+	tmp := l.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, blob, 0644); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
@@ -0,0 +1,54 @@
+package sequencer
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileLease(t *testing.T) {
+	lease := NewFileLease(filepath.Join(t.TempDir(), "lease"))
+	expiry := time.Now().Add(time.Minute)
+
+	// The first node acquires the free lease, the second one has to wait
+	record, acquired, err := lease.Acquire("a", expiry)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+	assert.Equal(t, "a", record.Holder)
+
+	record, acquired, err = lease.Acquire("b", expiry)
+	require.NoError(t, err)
+	assert.False(t, acquired)
+	assert.Equal(t, "a", record.Holder)
+
+	// Only the holder may record signed heights, and heights never decrease
+	require.NoError(t, lease.SetHeight("a", 10))
+	require.NoError(t, lease.SetHeight("a", 5))
+	assert.ErrorIs(t, lease.SetHeight("b", 20), ErrLeaseNotHeld)
+
+	// Releasing by a non-holder is a noop, releasing by the holder frees the lease
+	require.NoError(t, lease.Release("b"))
+	require.NoError(t, lease.Release("a"))
+
+	record, acquired, err = lease.Acquire("b", expiry)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+	assert.Equal(t, uint64(10), record.Height)
+}
+
+func TestFileLeaseExpiry(t *testing.T) {
+	lease := NewFileLease(filepath.Join(t.TempDir(), "lease"))
+
+	_, acquired, err := lease.Acquire("a", time.Now().Add(-time.Second))
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	// An expired lease can be taken over
+	record, acquired, err := lease.Acquire("b", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, acquired)
+	assert.Equal(t, "b", record.Holder)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/scroll-tech/go-ethereum/accounts/abi/bind"
 	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
 	"github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/scroll-tech/go-ethereum/rpc"
@@ -52,8 +53,9 @@ func newBridgeClient(ctx context.Context, l1Client EthClient, l1ChainId uint64,
 }
 
 // fetchMessagesInRange retrieves and parses all L1 messages between the
-// provided from and to L1 block numbers (inclusive).
-func (c *BridgeClient) fetchMessagesInRange(ctx context.Context, from, to uint64) ([]types.L1MessageTx, error) {
+// provided from and to L1 block numbers (inclusive), along with the L1
+// transactions which enqueued them.
+func (c *BridgeClient) fetchMessagesInRange(ctx context.Context, from, to uint64) ([]types.L1MessageTx, []rawdb.L1MessageOrigin, error) {
 	log.Trace("BridgeClient fetchMessagesInRange", "fromBlock", from, "toBlock", to)
 
 	opts := bind.FilterOpts{
@@ -63,17 +65,20 @@ func (c *BridgeClient) fetchMessagesInRange(ctx context.Context, from, to uint64
 	}
 	it, err := c.filterer.FilterQueueTransaction(&opts, nil, nil)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	var msgs []types.L1MessageTx
+	var (
+		msgs    []types.L1MessageTx
+		origins []rawdb.L1MessageOrigin
+	)
 
 	for it.Next() {
 		event := it.Event
 		log.Trace("Received new L1 QueueTransaction event", "event", event)
 
 		if !event.GasLimit.IsUint64() {
-			return nil, fmt.Errorf("invalid QueueTransaction event: QueueIndex = %v, GasLimit = %v", event.QueueIndex, event.GasLimit)
+			return nil, nil, fmt.Errorf("invalid QueueTransaction event: QueueIndex = %v, GasLimit = %v", event.QueueIndex, event.GasLimit)
 		}
 
 		msgs = append(msgs, types.L1MessageTx{
@@ -84,13 +89,18 @@ func (c *BridgeClient) fetchMessagesInRange(ctx context.Context, from, to uint64
 			Data:       event.Data,
 			Sender:     event.Sender,
 		})
+		origins = append(origins, rawdb.L1MessageOrigin{
+			TxHash:      event.Raw.TxHash,
+			BlockNumber: event.Raw.BlockNumber,
+			BlockHash:   event.Raw.BlockHash,
+		})
 	}
 
 	if err := it.Error(); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return msgs, nil
+	return msgs, origins, nil
 }
 
 func (c *BridgeClient) getLatestConfirmedBlockNumber(ctx context.Context) (uint64, error) {
@@ -0,0 +1,388 @@
+package sync_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/metrics"
+	"github.com/scroll-tech/go-ethereum/rlp"
+	"github.com/scroll-tech/go-ethereum/trie"
+)
+
+// DefaultL1RequestTimeout is the default timeout of a request to a single L1 endpoint.
+const DefaultL1RequestTimeout = 10 * time.Second
+
+var errNoQuorum = errors.New("no quorum among L1 endpoints")
+
+// l1Endpoint is a single endpoint of a MultiClient along with its health metrics.
+type l1Endpoint struct {
+	name   string
+	client EthClient
+
+	requests   metrics.Counter
+	failures   metrics.Counter
+	mismatches metrics.Counter // Responses outvoted by a quorum of other endpoints
+	latency    metrics.Timer
+	healthy    metrics.Gauge
+}
+
+// call runs a single request against the endpoint, accounting its health.
+func (e *l1Endpoint) call(ctx context.Context, timeout time.Duration, fn func(ctx context.Context, client EthClient) error) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := fn(ctx, e.client)
+	e.requests.Inc(1)
+	e.latency.UpdateSince(start)
+
+	// Missing data is not a fault of the endpoint
+	if err != nil && !errors.Is(err, ethereum.NotFound) {
+		e.failures.Inc(1)
+		e.healthy.Update(0)
+		return err
+	}
+	e.healthy.Update(1)
+	return err
+}
+
+// MultiClient is an EthClient fanning out to multiple L1 endpoints. Plain
+// requests fail over to the next endpoint on errors and timeouts. Logs and
+// headers, which determine the L1 messages and batches the node accepts, must be
+// returned identically by a quorum of endpoints, so that a single faulty or
+// malicious provider cannot poison the synced state.
+type MultiClient struct {
+	endpoints []*l1Endpoint
+	quorum    int
+	timeout   time.Duration
+
+	lock    sync.Mutex
+	primary int // Endpoint tried first by failover requests
+}
+
+// NewMultiClient creates an EthClient over the given L1 endpoints, requiring
+// quorum of them to agree on logs and headers. The names are used in logs and
+// must not contain credentials.
+func NewMultiClient(clients []EthClient, names []string, quorum int, timeout time.Duration) (*MultiClient, error) {
+	if len(clients) == 0 {
+		return nil, errors.New("no L1 endpoints")
+	}
+	if len(names) != len(clients) {
+		return nil, fmt.Errorf("L1 endpoint names mismatch: have %d, want %d", len(names), len(clients))
+	}
+	if quorum < 1 || quorum > len(clients) {
+		return nil, fmt.Errorf("invalid L1 quorum %d for %d endpoints", quorum, len(clients))
+	}
+	if timeout <= 0 {
+		timeout = DefaultL1RequestTimeout
+	}
+	c := &MultiClient{quorum: quorum, timeout: timeout}
+	for i, client := range clients {
+		prefix := fmt.Sprintf("rollup/l1/endpoint/%d/", i)
+		c.endpoints = append(c.endpoints, &l1Endpoint{
+			name:       names[i],
+			client:     client,
+			requests:   metrics.GetOrRegisterCounter(prefix+"requests", nil),
+			failures:   metrics.GetOrRegisterCounter(prefix+"failures", nil),
+			mismatches: metrics.GetOrRegisterCounter(prefix+"mismatches", nil),
+			latency:    metrics.GetOrRegisterTimer(prefix+"latency", nil),
+			healthy:    metrics.GetOrRegisterGauge(prefix+"healthy", nil),
+		})
+	}
+	return c, nil
+}
+
+// failover runs a request against the endpoints in turn, starting from the last
+// endpoint that succeeded, until one of them succeeds.
+func (c *MultiClient) failover(ctx context.Context, fn func(ctx context.Context, client EthClient) error) error {
+	c.lock.Lock()
+	primary := c.primary
+	c.lock.Unlock()
+
+	var err error
+	for i := range c.endpoints {
+		index := (primary + i) % len(c.endpoints)
+		endpoint := c.endpoints[index]
+		if err = endpoint.call(ctx, c.timeout, fn); err == nil {
+			if index != primary {
+				log.Warn("Failed over to L1 endpoint", "endpoint", endpoint.name)
+				c.lock.Lock()
+				c.primary = index
+				c.lock.Unlock()
+			}
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		log.Debug("L1 endpoint request failed", "endpoint", endpoint.name, "err", err)
+	}
+	return err
+}
+
+// l1Response is the response of a single endpoint to a quorum request.
+type l1Response struct {
+	endpoint *l1Endpoint
+	value    interface{}
+	digest   common.Hash
+	err      error
+}
+
+// all runs a request against all endpoints concurrently and returns the responses.
+func (c *MultiClient) all(ctx context.Context, fn func(ctx context.Context, client EthClient) (interface{}, common.Hash, error)) []l1Response {
+	var (
+		responses = make([]l1Response, len(c.endpoints))
+		wg        sync.WaitGroup
+	)
+	for i, endpoint := range c.endpoints {
+		wg.Add(1)
+		go func(res *l1Response, endpoint *l1Endpoint) {
+			defer wg.Done()
+
+			res.endpoint = endpoint
+			res.err = endpoint.call(ctx, c.timeout, func(ctx context.Context, client EthClient) (err error) {
+				res.value, res.digest, err = fn(ctx, client)
+				return err
+			})
+		}(&responses[i], endpoint)
+	}
+	wg.Wait()
+	return responses
+}
+
+// agree runs a request against all endpoints and returns the response a quorum
+// of them agrees on, identified by its digest.
+func (c *MultiClient) agree(ctx context.Context, fn func(ctx context.Context, client EthClient) (interface{}, common.Hash, error)) (interface{}, error) {
+	if c.quorum == 1 {
+		var value interface{}
+		err := c.failover(ctx, func(ctx context.Context, client EthClient) (err error) {
+			value, _, err = fn(ctx, client)
+			return err
+		})
+		return value, err
+	}
+	var (
+		responses = c.all(ctx, fn)
+		votes     = make(map[common.Hash]int)
+		errs      int
+		lastErr   error
+	)
+	for _, res := range responses {
+		if res.err != nil {
+			errs, lastErr = errs+1, res.err
+			continue
+		}
+		votes[res.digest]++
+	}
+	var (
+		best    common.Hash
+		reached int
+	)
+	for digest, count := range votes {
+		if count > votes[best] {
+			best = digest
+		}
+		if count >= c.quorum {
+			reached++
+		}
+	}
+	// With a quorum of at most half the endpoints, conflicting responses can
+	// reach it at the same time. Neither of them can be trusted then.
+	if reached > 1 {
+		return nil, fmt.Errorf("%w: %d conflicting responses reached quorum %d", errNoQuorum, reached, c.quorum)
+	}
+	if votes[best] >= c.quorum {
+		var value interface{}
+		for _, res := range responses {
+			switch {
+			case res.err != nil:
+			case res.digest != best:
+				log.Warn("L1 endpoint outvoted by quorum", "endpoint", res.endpoint.name, "have", res.digest, "want", best)
+				res.endpoint.mismatches.Inc(1)
+			default:
+				value = res.value
+			}
+		}
+		return value, nil
+	}
+	if errs > len(c.endpoints)-c.quorum {
+		return nil, fmt.Errorf("%w: %d of %d endpoints failed, last error: %v", errNoQuorum, errs, len(c.endpoints), lastErr)
+	}
+	return nil, fmt.Errorf("%w: %d distinct responses from %d endpoints", errNoQuorum, len(votes), len(c.endpoints)-errs)
+}
+
+// reached returns the highest block number that a quorum of endpoints reported,
+// so that a single endpoint cannot make the node sync ahead of the others.
+func (c *MultiClient) reached(ctx context.Context, fn func(ctx context.Context, client EthClient) (uint64, error)) (uint64, error) {
+	var (
+		numbers []uint64
+		lastErr error
+	)
+	for _, res := range c.all(ctx, func(ctx context.Context, client EthClient) (interface{}, common.Hash, error) {
+		number, err := fn(ctx, client)
+		return number, common.Hash{}, err
+	}) {
+		if res.err != nil {
+			lastErr = res.err
+			continue
+		}
+		numbers = append(numbers, res.value.(uint64))
+	}
+	if len(numbers) < c.quorum {
+		return 0, fmt.Errorf("%w: %d of %d endpoints failed, last error: %v", errNoQuorum, len(c.endpoints)-len(numbers), len(c.endpoints), lastErr)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] > numbers[j] })
+	return numbers[c.quorum-1], nil
+}
+
+// BlockNumber returns the most recent L1 block number reached by a quorum of endpoints.
+func (c *MultiClient) BlockNumber(ctx context.Context) (uint64, error) {
+	if c.quorum == 1 {
+		var number uint64
+		err := c.failover(ctx, func(ctx context.Context, client EthClient) (err error) {
+			number, err = client.BlockNumber(ctx)
+			return err
+		})
+		return number, err
+	}
+	return c.reached(ctx, func(ctx context.Context, client EthClient) (uint64, error) {
+		return client.BlockNumber(ctx)
+	})
+}
+
+// ChainID retrieves the L1 chain ID.
+func (c *MultiClient) ChainID(ctx context.Context) (*big.Int, error) {
+	var id *big.Int
+	err := c.failover(ctx, func(ctx context.Context, client EthClient) (err error) {
+		id, err = client.ChainID(ctx)
+		return err
+	})
+	return id, err
+}
+
+// logsDigest identifies a log query result, including the log positions which
+// are not part of the consensus encoding of a log.
+func logsDigest(logs []types.Log) (common.Hash, error) {
+	type logEntry struct {
+		Address     common.Address
+		Topics      []common.Hash
+		Data        []byte
+		BlockNumber uint64
+		TxHash      common.Hash
+		TxIndex     uint
+		BlockHash   common.Hash
+		Index       uint
+		Removed     bool
+	}
+	entries := make([]logEntry, len(logs))
+	for i, l := range logs {
+		entries[i] = logEntry{l.Address, l.Topics, l.Data, l.BlockNumber, l.TxHash, l.TxIndex, l.BlockHash, l.Index, l.Removed}
+	}
+	enc, err := rlp.EncodeToBytes(entries)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(enc), nil
+}
+
+// FilterLogs executes a filter query, requiring a quorum of endpoints to return
+// identical logs.
+func (c *MultiClient) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	value, err := c.agree(ctx, func(ctx context.Context, client EthClient) (interface{}, common.Hash, error) {
+		logs, err := client.FilterLogs(ctx, q)
+		if err != nil {
+			return nil, common.Hash{}, err
+		}
+		digest, err := logsDigest(logs)
+		return logs, digest, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]types.Log), nil
+}
+
+// HeaderByNumber returns an L1 block header, requiring a quorum of endpoints to
+// return the same block. Block tags are resolved to the most recent block reached
+// by a quorum of endpoints.
+func (c *MultiClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if c.quorum > 1 && (number == nil || number.Sign() < 0) {
+		reached, err := c.reached(ctx, func(ctx context.Context, client EthClient) (uint64, error) {
+			header, err := client.HeaderByNumber(ctx, number)
+			if err != nil {
+				return 0, err
+			}
+			return header.Number.Uint64(), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		number = new(big.Int).SetUint64(reached)
+	}
+	value, err := c.agree(ctx, func(ctx context.Context, client EthClient) (interface{}, common.Hash, error) {
+		header, err := client.HeaderByNumber(ctx, number)
+		if err != nil {
+			return nil, common.Hash{}, err
+		}
+		return header, header.Hash(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*types.Header), nil
+}
+
+// SubscribeFilterLogs subscribes to the results of a streaming filter query on
+// the first available endpoint.
+func (c *MultiClient) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	var sub ethereum.Subscription
+	err := c.failover(ctx, func(ctx context.Context, client EthClient) (err error) {
+		sub, err = client.SubscribeFilterLogs(ctx, query, ch)
+		return err
+	})
+	return sub, err
+}
+
+// TransactionByHash returns the L1 transaction with the given hash, which is
+// verified against the hash.
+func (c *MultiClient) TransactionByHash(ctx context.Context, txHash common.Hash) (tx *types.Transaction, isPending bool, err error) {
+	err = c.failover(ctx, func(ctx context.Context, client EthClient) (err error) {
+		if tx, isPending, err = client.TransactionByHash(ctx, txHash); err != nil {
+			return err
+		}
+		if tx.Hash() != txHash {
+			return fmt.Errorf("transaction hash mismatch: have %v, want %v", tx.Hash(), txHash)
+		}
+		return nil
+	})
+	return tx, isPending, err
+}
+
+// BlockByHash returns the L1 block with the given hash, which is verified
+// against the hash. The transactions are verified against the transaction root
+// of the header, as the header hash does not cover the body.
+func (c *MultiClient) BlockByHash(ctx context.Context, hash common.Hash) (block *types.Block, err error) {
+	err = c.failover(ctx, func(ctx context.Context, client EthClient) (err error) {
+		if block, err = client.BlockByHash(ctx, hash); err != nil {
+			return err
+		}
+		if block.Hash() != hash {
+			return fmt.Errorf("block hash mismatch: have %v, want %v", block.Hash(), hash)
+		}
+		if txHash := types.DeriveSha(block.Transactions(), trie.NewStackTrie(nil)); txHash != block.TxHash() {
+			return fmt.Errorf("block transaction root mismatch: have %v, want %v", txHash, block.TxHash())
+		}
+		return nil
+	})
+	return block, err
+}
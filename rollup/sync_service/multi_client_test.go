@@ -0,0 +1,205 @@
+package sync_service
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/rpc"
+	"github.com/scroll-tech/go-ethereum/trie"
+)
+
+// testEthClient is an L1 endpoint serving fixed responses.
+type testEthClient struct {
+	head    uint64
+	logs    []types.Log
+	block   *types.Block
+	err     error
+	delay   time.Duration
+	calls   int
+	chainID int64
+}
+
+func (c *testEthClient) wait(ctx context.Context) error {
+	c.calls++
+	select {
+	case <-time.After(c.delay):
+		return c.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *testEthClient) BlockNumber(ctx context.Context) (uint64, error) {
+	return c.head, c.wait(ctx)
+}
+
+func (c *testEthClient) ChainID(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(c.chainID), c.wait(ctx)
+}
+
+func (c *testEthClient) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	return c.logs, c.wait(ctx)
+}
+
+func (c *testEthClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+	n := c.head
+	if number != nil && number.Sign() >= 0 {
+		n = number.Uint64()
+	}
+	if n > c.head {
+		return nil, ethereum.NotFound
+	}
+	return &types.Header{Number: new(big.Int).SetUint64(n), Difficulty: common.Big0}, nil
+}
+
+func (c *testEthClient) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *testEthClient) TransactionByHash(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error) {
+	if err := c.wait(ctx); err != nil {
+		return nil, false, err
+	}
+	return types.NewTx(&types.LegacyTx{Nonce: c.head}), false, nil
+}
+
+func (c *testEthClient) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+	if c.block == nil {
+		return nil, ethereum.NotFound
+	}
+	return c.block, nil
+}
+
+func newTestMultiClient(t *testing.T, quorum int, clients ...*testEthClient) *MultiClient {
+	var (
+		ethClients []EthClient
+		names      []string
+	)
+	for i, client := range clients {
+		ethClients = append(ethClients, client)
+		names = append(names, string(rune('a'+i)))
+	}
+	c, err := NewMultiClient(ethClients, names, quorum, 100*time.Millisecond)
+	require.NoError(t, err)
+	return c
+}
+
+func TestMultiClientFailover(t *testing.T) {
+	var (
+		failing = &testEthClient{err: errors.New("rate limited"), chainID: 1}
+		slow    = &testEthClient{delay: time.Second, chainID: 1}
+		healthy = &testEthClient{head: 10, chainID: 1}
+		c       = newTestMultiClient(t, 1, failing, slow, healthy)
+	)
+	head, err := c.BlockNumber(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), head)
+
+	// The healthy endpoint is tried first from now on
+	_, err = c.ChainID(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, failing.calls)
+	assert.Equal(t, 1, slow.calls)
+	assert.Equal(t, 2, healthy.calls)
+
+	// Transactions not matching the requested hash are rejected
+	tx := types.NewTx(&types.LegacyTx{Nonce: 10})
+	have, _, err := c.TransactionByHash(context.Background(), tx.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, tx.Hash(), have.Hash())
+	_, _, err = c.TransactionByHash(context.Background(), common.Hash{1})
+	assert.Error(t, err)
+
+	// Requests fail once all endpoints failed
+	healthy.err = errors.New("down")
+	_, err = c.BlockNumber(context.Background())
+	assert.Error(t, err)
+}
+
+func TestMultiClientBlockByHash(t *testing.T) {
+	var (
+		txs    = types.Transactions{types.NewTx(&types.LegacyTx{Nonce: 1})}
+		header = &types.Header{Number: big.NewInt(10), Difficulty: common.Big0, TxHash: types.DeriveSha(txs, trie.NewStackTrie(nil))}
+		block  = types.NewBlockWithHeader(header).WithBody(txs, nil)
+
+		// The header hash does not cover the body
+		tampered = &testEthClient{block: types.NewBlockWithHeader(header).WithBody(types.Transactions{types.NewTx(&types.LegacyTx{Nonce: 2})}, nil)}
+		honest   = &testEthClient{block: block}
+		c        = newTestMultiClient(t, 1, tampered, honest)
+	)
+	have, err := c.BlockByHash(context.Background(), block.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, txs[0].Hash(), have.Transactions()[0].Hash())
+	assert.Equal(t, 1, tampered.calls)
+
+	_, err = c.BlockByHash(context.Background(), common.Hash{1})
+	assert.Error(t, err)
+}
+
+func TestMultiClientQuorum(t *testing.T) {
+	var (
+		good = []types.Log{{Address: common.Address{1}, BlockNumber: 5, Index: 0}}
+		bad  = []types.Log{{Address: common.Address{1}, BlockNumber: 5, Index: 0, Data: []byte{1}}}
+
+		honest1 = &testEthClient{head: 100, logs: good}
+		honest2 = &testEthClient{head: 98, logs: good}
+		faulty  = &testEthClient{head: 1000, logs: bad}
+		c       = newTestMultiClient(t, 2, honest1, honest2, faulty)
+	)
+	logs, err := c.FilterLogs(context.Background(), ethereum.FilterQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, good, logs)
+
+	// The head is the most recent block reached by a quorum
+	head, err := c.BlockNumber(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100), head)
+
+	header, err := c.HeaderByNumber(context.Background(), big.NewInt(int64(rpc.FinalizedBlockNumber)))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100), header.Number.Uint64())
+
+	// Without a quorum, the faulty logs must not be accepted
+	honest2.logs = []types.Log{}
+	_, err = c.FilterLogs(context.Background(), ethereum.FilterQuery{})
+	assert.ErrorIs(t, err, errNoQuorum)
+
+	honest2.logs, honest2.err = good, errors.New("down")
+	_, err = c.FilterLogs(context.Background(), ethereum.FilterQuery{})
+	assert.ErrorIs(t, err, errNoQuorum)
+
+	_, err = NewMultiClient([]EthClient{honest1}, []string{"a"}, 2, 0)
+	assert.Error(t, err)
+}
+
+func TestMultiClientQuorumTie(t *testing.T) {
+	var (
+		a = []types.Log{{Address: common.Address{1}, BlockNumber: 5, Index: 0}}
+		b = []types.Log{{Address: common.Address{2}, BlockNumber: 5, Index: 0}}
+
+		c = newTestMultiClient(t, 2,
+			&testEthClient{logs: a}, &testEthClient{logs: a},
+			&testEthClient{logs: b}, &testEthClient{logs: b},
+		)
+	)
+	// Two conflicting responses reaching the quorum must both be rejected
+	for i := 0; i < 10; i++ {
+		_, err := c.FilterLogs(context.Background(), ethereum.FilterQuery{})
+		assert.ErrorIs(t, err, errNoQuorum)
+	}
+}
@@ -209,7 +209,7 @@ func (s *SyncService) fetchMessages() {
 			to = latestConfirmed
 		}
 
-		msgs, err := s.client.fetchMessagesInRange(s.ctx, from, to)
+		msgs, origins, err := s.client.fetchMessagesInRange(s.ctx, from, to)
 		if err != nil {
 			// flush pending writes to database
 			if from > 0 {
@@ -222,6 +222,9 @@ func (s *SyncService) fetchMessages() {
 		if len(msgs) > 0 {
 			log.Debug("Received new L1 events", "fromBlock", from, "toBlock", to, "count", len(msgs))
 			rawdb.WriteL1Messages(batchWriter, msgs) // collect messages in memory
+			for i, origin := range origins {
+				rawdb.WriteL1MessageOrigin(batchWriter, msgs[i].QueueIndex, origin)
+			}
 			numMsgsCollected += len(msgs)
 		}
 
@@ -67,3 +67,17 @@ func (t *MuxTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, e
 		tracer.CaptureEnd(output, gasUsed, d, err)
 	}
 }
+
+// CaptureTxStart runs CaptureTxStart for each tracer in the MuxTracer
+func (t *MuxTracer) CaptureTxStart(gasLimit uint64) {
+	for _, tracer := range t.tracers {
+		tracer.CaptureTxStart(gasLimit)
+	}
+}
+
+// CaptureTxEnd runs CaptureTxEnd for each tracer in the MuxTracer
+func (t *MuxTracer) CaptureTxEnd(restGas uint64) {
+	for _, tracer := range t.tracers {
+		tracer.CaptureTxEnd(restGas)
+	}
+}
@@ -310,17 +310,22 @@ func (env *TraceEnv) getTxResult(state *state.StateDB, index int, block *types.B
 		}
 	}
 
+	l1DataFee, err := fees.CalculateL1DataFee(tx, state, env.chainConfig, block.Number())
+	if err != nil {
+		return err
+	}
 	txContext := core.NewEVMTxContext(msg)
 	tracerContext := tracers.Context{
 		BlockHash: block.Hash(),
 		TxIndex:   index,
 		TxHash:    tx.Hash(),
+		L1DataFee: l1DataFee,
 	}
-	callTracer, err := tracers.New("callTracer", &tracerContext)
+	callTracer, err := tracers.New("callTracer", &tracerContext, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create callTracer: %w", err)
 	}
-	prestateTracer, err := tracers.New("prestateTracer", &tracerContext)
+	prestateTracer, err := tracers.New("prestateTracer", &tracerContext, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create prestateTracer: %w", err)
 	}
@@ -335,10 +340,6 @@ func (env *TraceEnv) getTxResult(state *state.StateDB, index int, block *types.B
 	state.SetTxContext(txctx.TxHash, txctx.TxIndex)
 
 	// Computes the new state by applying the given message.
-	l1DataFee, err := fees.CalculateL1DataFee(tx, state, env.chainConfig, block.Number())
-	if err != nil {
-		return err
-	}
 	result, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.Gas()), l1DataFee)
 	if err != nil {
 		getTxResultApplyMessageTimer.UpdateSince(applyMessageStart)
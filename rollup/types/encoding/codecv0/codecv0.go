@@ -34,6 +34,12 @@ type DAChunk struct {
 	Transactions [][]*types.TransactionData
 }
 
+// DAChunkRawTx groups consecutive DABlocks with their L2 transactions.
+type DAChunkRawTx struct {
+	Blocks       []*DABlock
+	Transactions []types.Transactions
+}
+
 // DABatch contains metadata about a batch of DAChunks.
 type DABatch struct {
 	Version                uint8
@@ -297,10 +303,64 @@ func (b *DABatch) Hash() common.Hash {
 	return crypto.Keccak256Hash(bytes)
 }
 
-// DecodeFromCalldata attempts to decode a DABatch and an array of DAChunks from the provided calldata byte slice.
-func DecodeFromCalldata(data []byte) (*DABatch, []*DAChunk, error) {
-	// TODO: implement this function.
-	return nil, nil, nil
+// DecodeDAChunksRawTx decodes the chunks of a commitBatch call into their
+// block contexts and L2 transactions. L1 messages are not part of the chunk
+// encoding, their number is recorded in each DABlock.
+func DecodeDAChunksRawTx(chunks [][]byte) ([]*DAChunkRawTx, error) {
+	daChunks := make([]*DAChunkRawTx, 0, len(chunks))
+	for _, chunk := range chunks {
+		if len(chunk) < 1 {
+			return nil, errors.New("invalid chunk, length is less than 1")
+		}
+		numBlocks := int(chunk[0])
+		if len(chunk) < 1+numBlocks*60 {
+			return nil, fmt.Errorf("invalid chunk byte length, expected at least: %v, got: %v", 1+numBlocks*60, len(chunk))
+		}
+		blocks := make([]*DABlock, numBlocks)
+		for i := 0; i < numBlocks; i++ {
+			startIdx := 1 + i*60 // add 1 to skip numBlocks byte
+			block, err := DecodeDABlock(chunk[startIdx : startIdx+60])
+			if err != nil {
+				return nil, err
+			}
+			blocks[i] = block
+		}
+
+		// L2 transactions follow the block contexts, each prefixed by its 4 byte length
+		txData := chunk[1+numBlocks*60:]
+		transactions := make([]types.Transactions, numBlocks)
+		for i, block := range blocks {
+			if block.NumTransactions < block.NumL1Messages {
+				return nil, fmt.Errorf("block %d has more L1 messages than transactions", block.BlockNumber)
+			}
+			numL2Transactions := int(block.NumTransactions - block.NumL1Messages)
+			txs := make(types.Transactions, 0, numL2Transactions)
+			for j := 0; j < numL2Transactions; j++ {
+				if len(txData) < 4 {
+					return nil, fmt.Errorf("unexpected end of chunk data, block: %d, transaction: %d", block.BlockNumber, j)
+				}
+				txLen := int(binary.BigEndian.Uint32(txData[:4]))
+				if txLen > len(txData)-4 {
+					return nil, fmt.Errorf("transaction length %d exceeds chunk data, block: %d, transaction: %d", txLen, block.BlockNumber, j)
+				}
+				tx := new(types.Transaction)
+				if err := tx.UnmarshalBinary(txData[4 : 4+txLen]); err != nil {
+					return nil, fmt.Errorf("failed to decode transaction %d of block %d: %w", j, block.BlockNumber, err)
+				}
+				if tx.IsL1MessageTx() {
+					return nil, fmt.Errorf("unexpected L1 message in chunk, block: %d, transaction: %d", block.BlockNumber, j)
+				}
+				txs = append(txs, tx)
+				txData = txData[4+txLen:]
+			}
+			transactions[i] = txs
+		}
+		if len(txData) != 0 {
+			return nil, fmt.Errorf("chunk has %d trailing bytes", len(txData))
+		}
+		daChunks = append(daChunks, &DAChunkRawTx{Blocks: blocks, Transactions: transactions})
+	}
+	return daChunks, nil
 }
 
 // CalldataNonZeroByteGas is the gas consumption per non zero byte in calldata.
@@ -16,6 +16,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/crypto"
 	"github.com/scroll-tech/go-ethereum/crypto/kzg4844"
 	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rlp"
 	"github.com/scroll-tech/go-ethereum/rollup/types/encoding"
 )
 
@@ -72,6 +73,12 @@ type DAChunk struct {
 	Transactions [][]*types.TransactionData
 }
 
+// DAChunkRawTx groups consecutive DABlocks with their L2 transactions.
+type DAChunkRawTx struct {
+	Blocks       []*DABlock
+	Transactions []types.Transactions
+}
+
 // DABatch contains metadata about a batch of DAChunks.
 type DABatch struct {
 	// header
@@ -476,10 +483,125 @@ func (b *DABatch) Blob() *kzg4844.Blob {
 	return b.blob
 }
 
-// DecodeFromCalldata attempts to decode a DABatch and an array of DAChunks from the provided calldata byte slice.
-func DecodeFromCalldata(data []byte) (*DABatch, []*DAChunk, error) {
-	// TODO: implement this function.
-	return nil, nil, nil
+// DecodeDAChunksRawTx decodes the chunks of a commitBatch call into their block contexts.
+// Note: This function only populates the blocks, transactions are committed
+// in the blob and can be recovered with DecodeTxsFromBlob.
+func DecodeDAChunksRawTx(chunks [][]byte) ([]*DAChunkRawTx, error) {
+	daChunks := make([]*DAChunkRawTx, 0, len(chunks))
+	for _, chunk := range chunks {
+		if len(chunk) < 1 {
+			return nil, fmt.Errorf("invalid chunk, length is less than 1")
+		}
+		numBlocks := int(chunk[0])
+		if len(chunk) != 1+numBlocks*60 {
+			return nil, fmt.Errorf("invalid chunk byte length, expected: %v, got: %v", 1+numBlocks*60, len(chunk))
+		}
+		blocks := make([]*DABlock, numBlocks)
+		for i := 0; i < numBlocks; i++ {
+			startIdx := 1 + i*60 // add 1 to skip numBlocks byte
+			block, err := DecodeDABlock(chunk[startIdx : startIdx+60])
+			if err != nil {
+				return nil, err
+			}
+			blocks[i] = block
+		}
+		daChunks = append(daChunks, &DAChunkRawTx{Blocks: blocks})
+	}
+	return daChunks, nil
+}
+
+// DecodeTxsFromBlob decodes the L2 transactions of the given chunks from the
+// blob payload and populates their Transactions field. L1 messages are not
+// part of the blob, their number is recorded in each DABlock.
+func DecodeTxsFromBlob(blob *kzg4844.Blob, chunks []*DAChunkRawTx) error {
+	if len(chunks) > MaxNumChunks {
+		return fmt.Errorf("too many chunks in batch")
+	}
+	blobBytes, err := bytesFromBlobCanonical(blob)
+	if err != nil {
+		return err
+	}
+
+	// blob metadata: num_chunks and chunki_size
+	metadataLength := 2 + MaxNumChunks*4
+	numChunks := int(binary.BigEndian.Uint16(blobBytes[0:2]))
+	if numChunks != len(chunks) {
+		return fmt.Errorf("chunk count mismatch, blob: %d, batch: %d", numChunks, len(chunks))
+	}
+
+	index := metadataLength
+	for chunkID, chunk := range chunks {
+		chunkSize := int(binary.BigEndian.Uint32(blobBytes[2+4*chunkID:]))
+		if index+chunkSize > len(blobBytes) {
+			return fmt.Errorf("chunk %d exceeds blob payload, size: %d", chunkID, chunkSize)
+		}
+		chunkBytes := blobBytes[index : index+chunkSize]
+		index += chunkSize
+
+		chunk.Transactions = make([]types.Transactions, len(chunk.Blocks))
+		for blockID, block := range chunk.Blocks {
+			if block.NumTransactions < block.NumL1Messages {
+				return fmt.Errorf("block %d has more L1 messages than transactions", block.BlockNumber)
+			}
+			numL2Transactions := int(block.NumTransactions - block.NumL1Messages)
+			txs := make(types.Transactions, 0, numL2Transactions)
+			for i := 0; i < numL2Transactions; i++ {
+				tx, size, err := decodeTx(chunkBytes)
+				if err != nil {
+					return fmt.Errorf("failed to decode transaction %d of block %d: %w", i, block.BlockNumber, err)
+				}
+				txs = append(txs, tx)
+				chunkBytes = chunkBytes[size:]
+			}
+			chunk.Transactions[blockID] = txs
+		}
+		if len(chunkBytes) != 0 {
+			return fmt.Errorf("chunk %d has %d trailing bytes", chunkID, len(chunkBytes))
+		}
+	}
+	return nil
+}
+
+// bytesFromBlobCanonical converts the canonical blob representation back into
+// the raw blob payload by dropping the zero byte of every BLSFieldElement.
+func bytesFromBlobCanonical(blob *kzg4844.Blob) ([]byte, error) {
+	blobBytes := make([]byte, 0, 4096*31)
+	for from := 0; from < len(blob); from += 32 {
+		if blob[from] != 0 {
+			return nil, fmt.Errorf("non-canonical blob, field element %d has non-zero first byte", from/32)
+		}
+		blobBytes = append(blobBytes, blob[from+1:from+32]...)
+	}
+	return blobBytes, nil
+}
+
+// decodeTx decodes the first transaction of the given L2 transaction encodings
+// and returns it along with the length of its encoding.
+func decodeTx(data []byte) (*types.Transaction, int, error) {
+	if len(data) == 0 {
+		return nil, 0, errors.New("unexpected end of chunk data")
+	}
+	// legacy transactions are RLP lists, typed transactions are prefixed by their type
+	offset := 0
+	if data[0] < 0xc0 {
+		if data[0] == types.L1MessageTxType {
+			return nil, 0, errors.New("unexpected L1 message in blob")
+		}
+		offset = 1
+	}
+	kind, _, rest, err := rlp.Split(data[offset:])
+	if err != nil {
+		return nil, 0, err
+	}
+	if kind != rlp.List {
+		return nil, 0, errors.New("transaction is not an RLP list")
+	}
+	size := len(data) - len(rest)
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(data[:size]); err != nil {
+		return nil, 0, err
+	}
+	return tx, size, nil
 }
 
 // EstimateChunkL1CommitBlobSize estimates the size of the L1 commit blob for a single chunk.
@@ -0,0 +1,195 @@
+package encoding_test
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/crypto/kzg4844"
+	"github.com/scroll-tech/go-ethereum/rollup/types/encoding"
+	"github.com/scroll-tech/go-ethereum/rollup/types/encoding/codecv0"
+	"github.com/scroll-tech/go-ethereum/rollup/types/encoding/codecv1"
+)
+
+var (
+	testKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testSigner = types.NewLondonSigner(big.NewInt(534352))
+)
+
+// randomBatch generates a batch with up to maxChunks chunks from the given
+// seed, with L2 transactions of all supported types and L1 messages including
+// skipped ones. The L2 transactions of each block are returned along.
+func randomBatch(t *testing.T, seed int64, maxChunks int, payload []byte) (*encoding.Batch, [][]types.Transactions) {
+	var (
+		rnd        = rand.New(rand.NewSource(seed))
+		queueIndex = uint64(rnd.Intn(100))
+		batch      = &encoding.Batch{Index: 1, TotalL1MessagePoppedBefore: queueIndex}
+		l2Txs      [][]types.Transactions
+		number     = uint64(rnd.Intn(1000))
+	)
+	numChunks := 1 + rnd.Intn(maxChunks)
+	for i := 0; i < numChunks; i++ {
+		var (
+			chunk     = new(encoding.Chunk)
+			chunkTxs  []types.Transactions
+			numBlocks = 1 + rnd.Intn(4)
+		)
+		for j := 0; j < numBlocks; j++ {
+			var (
+				txs, blockL2Txs types.Transactions
+				numL1Messages   = rnd.Intn(5)
+				numL2Txs        = rnd.Intn(5)
+			)
+			for k := 0; k < numL1Messages; k++ {
+				queueIndex += uint64(rnd.Intn(3)) // skip some L1 messages
+				txs = append(txs, types.NewTx(&types.L1MessageTx{QueueIndex: queueIndex, Gas: 21000, To: &common.Address{}, Data: payload}))
+				queueIndex++
+			}
+			for k := 0; k < numL2Txs; k++ {
+				var (
+					to    = common.Address{byte(k)}
+					value = big.NewInt(rnd.Int63())
+					inner types.TxData
+				)
+				switch rnd.Intn(3) {
+				case 0:
+					inner = &types.LegacyTx{Nonce: rnd.Uint64(), GasPrice: big.NewInt(rnd.Int63()), Gas: rnd.Uint64(), To: &to, Value: value, Data: payload}
+				case 1:
+					inner = &types.AccessListTx{ChainID: testSigner.ChainID(), Nonce: rnd.Uint64(), GasPrice: big.NewInt(rnd.Int63()), Gas: rnd.Uint64(), Value: value, Data: payload,
+						AccessList: types.AccessList{{Address: to, StorageKeys: []common.Hash{{byte(k)}}}}}
+				default:
+					inner = &types.DynamicFeeTx{ChainID: testSigner.ChainID(), Nonce: rnd.Uint64(), GasTipCap: big.NewInt(rnd.Int63()), GasFeeCap: big.NewInt(rnd.Int63()), Gas: rnd.Uint64(), To: &to, Value: value, Data: payload}
+				}
+				tx, err := types.SignNewTx(testKey, testSigner, inner)
+				require.NoError(t, err)
+				blockL2Txs = append(blockL2Txs, tx)
+			}
+			txs = append(txs, blockL2Txs...)
+
+			header := &types.Header{
+				Number:   new(big.Int).SetUint64(number),
+				Time:     rnd.Uint64(),
+				BaseFee:  new(big.Int).SetUint64(rnd.Uint64()),
+				GasLimit: rnd.Uint64(),
+			}
+			chunk.Blocks = append(chunk.Blocks, &encoding.Block{Header: header, Transactions: encoding.TxsToTxsData(txs)})
+			chunkTxs = append(chunkTxs, blockL2Txs)
+			number++
+		}
+		batch.Chunks = append(batch.Chunks, chunk)
+		l2Txs = append(l2Txs, chunkTxs)
+	}
+	return batch, l2Txs
+}
+
+// checkTxs verifies the decoded L2 transactions against the encoded ones.
+func checkTxs(t *testing.T, want, have types.Transactions) {
+	require.Equal(t, len(want), len(have))
+	for i := range want {
+		assert.Equal(t, want[i].Hash(), have[i].Hash(), "transaction %d", i)
+	}
+}
+
+func FuzzCodecV0RoundTrip(f *testing.F) {
+	f.Add(int64(0), []byte{})
+	f.Add(int64(1), []byte{0x01, 0x02, 0x03})
+	f.Add(int64(42), make([]byte, 300))
+	f.Fuzz(func(t *testing.T, seed int64, payload []byte) {
+		batch, l2Txs := randomBatch(t, seed, 4, payload)
+
+		var (
+			encoded []byte
+			chunks  [][]byte
+			blocks  []*codecv0.DABlock
+			popped  = batch.TotalL1MessagePoppedBefore
+		)
+		for _, chunk := range batch.Chunks {
+			daChunk, err := codecv0.NewDAChunk(chunk, popped)
+			require.NoError(t, err)
+			popped += chunk.NumL1Messages(popped)
+
+			encoded, err = daChunk.Encode()
+			require.NoError(t, err)
+			chunks = append(chunks, encoded)
+			blocks = append(blocks, daChunk.Blocks...)
+		}
+
+		decoded, err := codecv0.DecodeDAChunksRawTx(chunks)
+		require.NoError(t, err)
+		require.Len(t, decoded, len(batch.Chunks))
+		var n int
+		for i, chunk := range decoded {
+			for j, block := range chunk.Blocks {
+				assert.Equal(t, blocks[n].Encode(), block.Encode())
+				checkTxs(t, l2Txs[i][j], chunk.Transactions[j])
+				n++
+			}
+		}
+	})
+}
+
+func FuzzCodecV1RoundTrip(f *testing.F) {
+	f.Add(int64(0), []byte{})
+	f.Add(int64(1), []byte{0x01, 0x02, 0x03})
+	f.Add(int64(42), make([]byte, 300))
+	f.Fuzz(func(t *testing.T, seed int64, payload []byte) {
+		batch, l2Txs := randomBatch(t, seed, codecv1.MaxNumChunks, payload)
+
+		daBatch, err := codecv1.NewDABatch(batch)
+		if err != nil {
+			// the batch may exceed the blob capacity
+			t.Skip(err)
+		}
+		var (
+			chunks [][]byte
+			blocks []*codecv1.DABlock
+			popped = batch.TotalL1MessagePoppedBefore
+		)
+		for _, chunk := range batch.Chunks {
+			daChunk, err := codecv1.NewDAChunk(chunk, popped)
+			require.NoError(t, err)
+			popped += chunk.NumL1Messages(popped)
+
+			chunks = append(chunks, daChunk.Encode())
+			blocks = append(blocks, daChunk.Blocks...)
+		}
+
+		decoded, err := codecv1.DecodeDAChunksRawTx(chunks)
+		require.NoError(t, err)
+		require.NoError(t, codecv1.DecodeTxsFromBlob(daBatch.Blob(), decoded))
+		require.Len(t, decoded, len(batch.Chunks))
+		var n int
+		for i, chunk := range decoded {
+			for j, block := range chunk.Blocks {
+				assert.Equal(t, blocks[n].Encode(), block.Encode())
+				checkTxs(t, l2Txs[i][j], chunk.Transactions[j])
+				n++
+			}
+		}
+
+		// The batch header round-trips as well
+		header, err := codecv1.NewDABatchFromBytes(daBatch.Encode())
+		require.NoError(t, err)
+		assert.Equal(t, daBatch.Hash(), header.Hash())
+	})
+}
+
+func FuzzDecodeDAChunksRawTx(f *testing.F) {
+	f.Add([]byte{1}, []byte{0})
+	f.Add(append([]byte{1}, make([]byte, 60)...), []byte{})
+	f.Fuzz(func(t *testing.T, chunk, blob []byte) {
+		// Decoding arbitrary data must fail gracefully
+		codecv0.DecodeDAChunksRawTx([][]byte{chunk})
+		if decoded, err := codecv1.DecodeDAChunksRawTx([][]byte{chunk}); err == nil {
+			var b kzg4844.Blob
+			copy(b[:], blob)
+			codecv1.DecodeTxsFromBlob(&b, decoded)
+		}
+	})
+}
@@ -19,6 +19,7 @@ package rpc
 import (
 	"context"
 	"encoding/json"
+	"net"
 	"reflect"
 	"strconv"
 	"strings"
@@ -386,13 +387,29 @@ func (h *handler) handleSubscribe(cp *callProc, msg *jsonrpcMessage) *jsonrpcMes
 
 // runMethod runs the Go callback for an RPC method.
 func (h *handler) runMethod(ctx context.Context, msg *jsonrpcMessage, callb *callback, args []reflect.Value) *jsonrpcMessage {
-	result, err := callb.call(ctx, msg.Method, args)
+	call := &Call{Method: msg.Method, Params: msg.Params, Client: h.clientID(ctx)}
+	result, err := h.reg.wrap(func(ctx context.Context, call *Call) (interface{}, error) {
+		return callb.call(ctx, call.Method, args)
+	})(ctx, call)
 	if err != nil {
 		return msg.errorResponse(err)
 	}
 	return msg.response(result)
 }
 
+// clientID returns the identity of the caller, defaulting to the host of the
+// remote address of the connection.
+func (h *handler) clientID(ctx context.Context) string {
+	if id := ClientID(ctx); id != "" {
+		return id
+	}
+	remote := h.conn.remoteAddr()
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		return host
+	}
+	return remote
+}
+
 // unsubscribe is the callback function for all *_unsubscribe calls.
 func (h *handler) unsubscribe(ctx context.Context, id ID) (bool, error) {
 	h.subLock.Lock()
@@ -0,0 +1,229 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/metrics"
+)
+
+// maxLoggedParamsLength is the maximum length of the parameters of a slow call
+// included in its log.
+const maxLoggedParamsLength = 1024
+
+// secretParamsNamespaces are the namespaces of the methods whose parameters may
+// hold secrets, such as keys and passphrases, and are never logged.
+var secretParamsNamespaces = map[string]bool{
+	"personal": true,
+	"account":  true,
+	"clef":     true,
+}
+
+// secretParamsMethods are the methods outside of the secretParamsNamespaces
+// whose parameters may hold secrets or data to sign, and are never logged.
+var secretParamsMethods = map[string]bool{
+	"eth_sign":             true,
+	"eth_signTransaction":  true,
+	"eth_sendTransaction":  true,
+	"eth_signTypedData":    true,
+	"eth_signTypedData_v3": true,
+	"eth_signTypedData_v4": true,
+}
+
+var (
+	slowCallMeter       = metrics.NewRegisteredMeter("rpc/slow", nil)
+	budgetExceededMeter = metrics.NewRegisteredMeter("rpc/budget/exceeded", nil)
+)
+
+// Call is a method call passing through the middlewares of a server.
+type Call struct {
+	Method string          // Name of the called method
+	Params json.RawMessage // Raw parameters of the call
+	Client string          // Identity of the caller, see ClientID
+}
+
+// CallHandler executes a method call, returning its result.
+type CallHandler func(ctx context.Context, call *Call) (interface{}, error)
+
+// Middleware wraps the execution of the method calls served by a server, e.g. to
+// enforce limits or to record metrics. Errors implementing the Error interface
+// are returned to the caller along with their code.
+type Middleware func(next CallHandler) CallHandler
+
+type clientIDKey struct{}
+
+// WithClientID returns a copy of ctx identifying the caller of the requests served
+// with it, such as the subject of its authentication token. Requests without an
+// identity are told apart by the host of their remote address.
+func WithClientID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, clientIDKey{}, id)
+}
+
+// ClientID returns the identity of the caller set by WithClientID, if any.
+func ClientID(ctx context.Context) string {
+	id, _ := ctx.Value(clientIDKey{}).(string)
+	return id
+}
+
+// NewMethodRateLimiter returns a middleware limiting the number of calls per
+// second to the given methods, shared by all clients.
+func NewMethodRateLimiter(limits map[string]float64) Middleware {
+	limiters := make(map[string]*rate.Limiter, len(limits))
+	for method, limit := range limits {
+		burst := int(limit)
+		if burst < 1 {
+			burst = 1
+		}
+		limiters[method] = rate.NewLimiter(rate.Limit(limit), burst)
+	}
+	return func(next CallHandler) CallHandler {
+		return func(ctx context.Context, call *Call) (interface{}, error) {
+			if limiter := limiters[call.Method]; limiter != nil && !limiter.Allow() {
+				return nil, &limitExceededError{fmt.Sprintf("rate limit of method %s exceeded", call.Method)}
+			}
+			return next(ctx, call)
+		}
+	}
+}
+
+// BudgetConfig configures the request budgets of the clients of a server. Every
+// call spends the cost of its method from the budget of its caller, which is
+// refilled at a constant rate. Calls costing more than the burst always fail.
+type BudgetConfig struct {
+	Costs       map[string]int // Cost of the calls to a method by name
+	DefaultCost int            // Cost of the calls to the methods not listed
+	Rate        float64        // Budget refilled per second
+	Burst       int            // Maximum budget of a client
+}
+
+// budget is the remaining request budget of a client.
+type budget struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// BudgetLimiter enforces the request budgets of the clients of a server. It may
+// be shared by several servers, e.g. the HTTP and WebSocket ones, for clients to
+// spend the same budget on all of them.
+type BudgetLimiter struct {
+	config BudgetConfig
+	idle   time.Duration // Time after which the budget of a client is full again
+
+	mu        sync.Mutex
+	budgets   map[string]*budget
+	lastPrune time.Time
+}
+
+// NewBudgetLimiter creates a limiter enforcing the given request budgets.
+func NewBudgetLimiter(config BudgetConfig) *BudgetLimiter {
+	if config.DefaultCost <= 0 {
+		config.DefaultCost = 1
+	}
+	if config.Burst <= 0 {
+		config.Burst = 1
+	}
+	idle := time.Minute
+	if config.Rate > 0 {
+		idle = time.Duration(float64(config.Burst) / config.Rate * float64(time.Second))
+	}
+	return &BudgetLimiter{
+		config:    config,
+		idle:      idle,
+		budgets:   make(map[string]*budget),
+		lastPrune: time.Now(),
+	}
+}
+
+// cost returns the cost of calling the given method.
+func (l *BudgetLimiter) cost(method string) int {
+	if cost, ok := l.config.Costs[method]; ok {
+		return cost
+	}
+	return l.config.DefaultCost
+}
+
+// spend takes the cost of the given call from the budget of its caller,
+// reporting whether the budget sufficed.
+func (l *BudgetLimiter) spend(call *Call, cost int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	// Forget the clients whose budget is full again, as they are the same as new
+	if now.Sub(l.lastPrune) > l.idle {
+		for id, b := range l.budgets {
+			if now.Sub(b.lastSeen) > l.idle {
+				delete(l.budgets, id)
+			}
+		}
+		l.lastPrune = now
+	}
+	b := l.budgets[call.Client]
+	if b == nil {
+		b = &budget{limiter: rate.NewLimiter(rate.Limit(l.config.Rate), l.config.Burst)}
+		l.budgets[call.Client] = b
+	}
+	b.lastSeen = now
+	return b.limiter.AllowN(now, cost)
+}
+
+// Middleware returns the middleware enforcing the request budgets.
+func (l *BudgetLimiter) Middleware() Middleware {
+	return func(next CallHandler) CallHandler {
+		return func(ctx context.Context, call *Call) (interface{}, error) {
+			cost := l.cost(call.Method)
+			if cost > 0 {
+				if !l.spend(call, cost) {
+					budgetExceededMeter.Mark(1)
+					return nil, &limitExceededError{fmt.Sprintf("request budget exceeded by %s (cost %d)", call.Method, cost)}
+				}
+				metrics.GetOrRegisterCounter("rpc/cost/"+call.Method, nil).Inc(int64(cost))
+			}
+			return next(ctx, call)
+		}
+	}
+}
+
+// hasSecretParams reports whether the parameters of the given method may hold
+// secrets, which must not be logged.
+func hasSecretParams(method string) bool {
+	if secretParamsMethods[method] {
+		return true
+	}
+	namespace := strings.SplitN(method, serviceMethodSeparator, 2)[0]
+	return secretParamsNamespaces[namespace]
+}
+
+// NewSlowCallLogger returns a middleware logging the calls taking longer than
+// the given threshold along with their parameters, and counting them by method.
+// The parameters of the methods possibly handling secrets are left out.
+func NewSlowCallLogger(threshold time.Duration) Middleware {
+	return func(next CallHandler) CallHandler {
+		return func(ctx context.Context, call *Call) (interface{}, error) {
+			start := time.Now()
+			result, err := next(ctx, call)
+			if elapsed := time.Since(start); elapsed > threshold {
+				slowCallMeter.Mark(1)
+				metrics.GetOrRegisterMeter("rpc/slow/"+call.Method, nil).Mark(1)
+
+				if hasSecretParams(call.Method) {
+					log.Warn("Slow RPC call", "method", call.Method, "client", call.Client, "elapsed", elapsed, "err", err)
+					return result, err
+				}
+				params := string(call.Params)
+				if len(params) > maxLoggedParamsLength {
+					params = params[:maxLoggedParamsLength] + "..."
+				}
+				log.Warn("Slow RPC call", "method", call.Method, "client", call.Client, "elapsed", elapsed, "params", params, "err", err)
+			}
+			return result, err
+		}
+	}
+}
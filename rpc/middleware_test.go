@@ -0,0 +1,196 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+)
+
+func TestServerMiddlewareOrder(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+
+	var order []string
+	record := func(name string) Middleware {
+		return func(next CallHandler) CallHandler {
+			return func(ctx context.Context, call *Call) (interface{}, error) {
+				order = append(order, name+":"+call.Method)
+				return next(ctx, call)
+			}
+		}
+	}
+	server.Use(record("outer"), record("inner"))
+	client := DialInProc(server)
+	defer client.Close()
+
+	var resp echoResult
+	if err := client.Call(&resp, "test_echo", "hello", 10, &echoArgs{"world"}); err != nil {
+		t.Fatal(err)
+	}
+	if resp.String != "hello" {
+		t.Errorf("incorrect result %#v", resp)
+	}
+	if want := "outer:test_echo,inner:test_echo"; strings.Join(order, ",") != want {
+		t.Errorf("wrong middleware order: have %v, want %v", order, want)
+	}
+}
+
+func TestBudgetLimiter(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+
+	limiter := NewBudgetLimiter(BudgetConfig{
+		Costs: map[string]int{"test_echo": 3, "test_rets": 0},
+		Rate:  0.001,
+		Burst: 5,
+	})
+	server.Use(limiter.Middleware())
+	client := DialInProc(server)
+	defer client.Close()
+
+	// The first call fits in the budget, the second one exceeds it
+	if err := client.Call(nil, "test_echo", "hello", 10, &echoArgs{"world"}); err != nil {
+		t.Fatal(err)
+	}
+	err := client.Call(nil, "test_echo", "hello", 10, &echoArgs{"world"})
+	if err == nil {
+		t.Fatal("expected budget exceeded error")
+	}
+	var rpcErr Error
+	if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != -32005 {
+		t.Fatalf("wrong error: %v", err)
+	}
+	// Cheaper and free calls are still served from the remaining budget
+	if err := client.Call(nil, "test_noArgsRets"); err != nil {
+		t.Fatalf("call within budget failed: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		if err := client.Call(nil, "test_rets"); err != nil {
+			t.Fatalf("free call failed: %v", err)
+		}
+	}
+}
+
+func TestBudgetLimiterClients(t *testing.T) {
+	limiter := NewBudgetLimiter(BudgetConfig{Rate: 0.001, Burst: 2})
+	handler := limiter.Middleware()(func(ctx context.Context, call *Call) (interface{}, error) {
+		return nil, nil
+	})
+	call := func(client string) error {
+		_, err := handler(context.Background(), &Call{Method: "test_echo", Client: client})
+		return err
+	}
+	for i := 0; i < 2; i++ {
+		if err := call("a"); err != nil {
+			t.Fatalf("call %d of client a failed: %v", i, err)
+		}
+	}
+	if err := call("a"); err == nil {
+		t.Fatal("expected budget of client a to be exceeded")
+	}
+	if err := call("b"); err != nil {
+		t.Fatalf("client b charged for the calls of client a: %v", err)
+	}
+}
+
+func TestSlowCallLogger(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+	server.Use(NewSlowCallLogger(50 * time.Millisecond))
+	client := DialInProc(server)
+	defer client.Close()
+
+	var (
+		mu   sync.Mutex
+		slow []string
+	)
+	defer log.Root().SetHandler(log.Root().GetHandler())
+	log.Root().SetHandler(log.FuncHandler(func(r *log.Record) error {
+		if r.Msg == "Slow RPC call" {
+			mu.Lock()
+			slow = append(slow, fmt.Sprint(r.Ctx[1]))
+			mu.Unlock()
+		}
+		return nil
+	}))
+	if err := client.Call(nil, "test_sleep", 100*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Call(nil, "test_noArgsRets"); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(slow) != 1 || slow[0] != "test_sleep" {
+		t.Errorf("wrong slow calls: %v", slow)
+	}
+}
+
+func TestSlowCallLoggerSecretParams(t *testing.T) {
+	handler := NewSlowCallLogger(0)(func(ctx context.Context, call *Call) (interface{}, error) {
+		time.Sleep(time.Millisecond)
+		return nil, nil
+	})
+	var params []interface{}
+	defer log.Root().SetHandler(log.Root().GetHandler())
+	log.Root().SetHandler(log.FuncHandler(func(r *log.Record) error {
+		for i := 0; i+1 < len(r.Ctx); i += 2 {
+			if r.Ctx[i] == "params" {
+				params = append(params, r.Ctx[i+1])
+			}
+		}
+		return nil
+	}))
+	for _, method := range []string{"personal_importRawKey", "personal_unlockAccount", "eth_signTransaction", "account_signData"} {
+		if _, err := handler(context.Background(), &Call{Method: method, Params: []byte(`["secret"]`)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(params) != 0 {
+		t.Fatalf("secret parameters logged: %v", params)
+	}
+	if _, err := handler(context.Background(), &Call{Method: "eth_call", Params: []byte(`[{}]`)}); err != nil {
+		t.Fatal(err)
+	}
+	if len(params) != 1 || params[0] != `[{}]` {
+		t.Errorf("wrong logged parameters: %v", params)
+	}
+}
+
+func TestServerSetRateLimits(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+	client := DialInProc(server)
+	defer client.Close()
+
+	server.SetRateLimits(map[string]float64{"test_echo": 0.001})
+	if err := client.Call(nil, "test_echo", "hello", 10, &echoArgs{"world"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Call(nil, "test_echo", "hello", 10, &echoArgs{"world"}); err == nil {
+		t.Fatal("expected rate limit exceeded error")
+	}
+	// The new limits replace the previous ones instead of adding up
+	server.SetRateLimits(map[string]float64{"test_rets": 0.001})
+	for i := 0; i < 3; i++ {
+		if err := client.Call(nil, "test_echo", "hello", 10, &echoArgs{"world"}); err != nil {
+			t.Fatalf("call %d limited by replaced limits: %v", i, err)
+		}
+	}
+	if err := client.Call(nil, "test_rets"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Call(nil, "test_rets"); err == nil {
+		t.Fatal("expected rate limit exceeded error")
+	}
+	server.SetRateLimits(nil)
+	if err := client.Call(nil, "test_rets"); err != nil {
+		t.Fatalf("call limited by cleared limits: %v", err)
+	}
+}
@@ -71,6 +71,23 @@ func (s *Server) RegisterName(name string, receiver interface{}) error {
 	return s.services.registerName(name, receiver)
 }
 
+// Use adds middlewares wrapping the method calls served by the server, including
+// the creation of subscriptions. The middlewares added first are outermost.
+func (s *Server) Use(middlewares ...Middleware) {
+	s.services.use(middlewares)
+}
+
+// SetRateLimits limits the number of calls per second to the given methods, shared
+// by all clients of the server. Calls exceeding the limit fail with an error. The
+// limits replace the ones set before, and are enforced after the middlewares.
+func (s *Server) SetRateLimits(limits map[string]float64) {
+	if len(limits) == 0 {
+		s.services.setRateLimits(nil)
+		return
+	}
+	s.services.setRateLimits(NewMethodRateLimiter(limits))
+}
+
 // ServeCodec reads incoming requests from codec, calls the appropriate callback and writes
 // the response back using the given codec. It will block until the codec is closed or the
 // server is stopped. In either case the codec is closed.
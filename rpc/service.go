@@ -37,8 +37,40 @@ var (
 )
 
 type serviceRegistry struct {
-	mu       sync.Mutex
-	services map[string]service
+	mu          sync.Mutex
+	services    map[string]service
+	middlewares []Middleware // wrapping the method calls, outermost first
+	rateLimits  Middleware   // limiting the calls per method, innermost
+}
+
+// use appends the given middlewares to the ones wrapping the method calls.
+func (r *serviceRegistry) use(middlewares []Middleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.middlewares = append(r.middlewares, middlewares...)
+}
+
+// setRateLimits replaces the middleware limiting the calls per method.
+func (r *serviceRegistry) setRateLimits(rateLimits Middleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rateLimits = rateLimits
+}
+
+// wrap returns the given call handler wrapped by the middlewares.
+func (r *serviceRegistry) wrap(handler CallHandler) CallHandler {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.rateLimits != nil {
+		handler = r.rateLimits(handler)
+	}
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		handler = r.middlewares[i](handler)
+	}
+	return handler
 }
 
 // service represents a registered object.
@@ -68,6 +68,7 @@ func (s *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
 			_ = conn.SetCompressionLevel(s.compressionLevel)
 		}
 		codec := newWebsocketCodec(conn)
+		codec.(*websocketCodec).clientID = ClientID(r.Context())
 		s.ServeCodec(codec, 0)
 	})
 }
@@ -241,7 +242,8 @@ func wsClientHeaders(endpoint, origin string) (string, http.Header, error) {
 
 type websocketCodec struct {
 	*jsonCodec
-	conn *websocket.Conn
+	conn     *websocket.Conn
+	clientID string // identity of the client set on the upgrade request, if any
 
 	wg        sync.WaitGroup
 	pingReset chan struct{}
@@ -258,6 +260,9 @@ func newWebsocketCodec(conn *websocket.Conn) ServerCodec {
 		conn:      conn,
 		pingReset: make(chan struct{}, 1),
 	}
+	if addr := conn.RemoteAddr(); addr != nil {
+		wc.remote = addr.String()
+	}
 	wc.wg.Add(1)
 	go wc.pingLoop()
 	return wc
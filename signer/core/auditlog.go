@@ -20,8 +20,10 @@ import (
 	"context"
 	"encoding/json"
 
+	"github.com/scroll-tech/go-ethereum/accounts"
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/consensus/clique"
 	"github.com/scroll-tech/go-ethereum/internal/ethapi"
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/scroll-tech/go-ethereum/signer/core/apitypes"
@@ -68,6 +70,14 @@ func (l *AuditLogger) SignData(ctx context.Context, contentType string, addr com
 		"addr", addr.String(), "data", marshalledData, "content-type", contentType)
 	b, e := l.api.SignData(ctx, contentType, addr, data)
 	l.log.Info("SignData", "type", "response", "data", common.Bytes2Hex(b), "error", e)
+
+	// Keep a readable trail of every clique header signed, e.g. by a sequencer
+	if contentType == accounts.MimetypeClique && e == nil {
+		if header, err := decodeCliqueHeader(data); err == nil {
+			l.log.Info("SignCliqueHeader", "addr", addr.String(), "number", header.Number, "parent", header.ParentHash,
+				"sealhash", clique.SealHash(header), "time", header.Time, "signature", common.Bytes2Hex(b))
+		}
+	}
 	return b, e
 }
 
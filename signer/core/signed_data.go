@@ -227,25 +227,10 @@ func (api *SignerAPI) determineSignatureFormat(ctx context.Context, contentType
 		req = &SignDataRequest{ContentType: mediaType, Rawdata: []byte(msg), Messages: messages, Hash: sighash}
 	case ApplicationClique.Mime:
 		// Clique is the Ethereum PoA standard
-		stringData, ok := data.(string)
-		if !ok {
-			return nil, useEthereumV, fmt.Errorf("input for %v must be an hex-encoded string", ApplicationClique.Mime)
-		}
-		cliqueData, err := hexutil.Decode(stringData)
+		header, err := decodeCliqueHeader(data)
 		if err != nil {
 			return nil, useEthereumV, err
 		}
-		header := &types.Header{}
-		if err := rlp.DecodeBytes(cliqueData, header); err != nil {
-			return nil, useEthereumV, err
-		}
-		// The incoming clique header is already truncated, sent to us with a extradata already shortened
-		if len(header.Extra) < 65 {
-			// Need to add it back, to get a suitable length for hashing
-			newExtra := make([]byte, len(header.Extra)+65)
-			copy(newExtra, header.Extra)
-			header.Extra = newExtra
-		}
 		// Get back the rlp data, encoded by us
 		sighash, cliqueRlp, err := cliqueHeaderHashAndRlp(header)
 		if err != nil {
@@ -260,7 +245,15 @@ func (api *SignerAPI) determineSignatureFormat(ctx context.Context, contentType
 		}
 		// Clique uses V on the form 0 or 1
 		useEthereumV = false
-		req = &SignDataRequest{ContentType: mediaType, Rawdata: cliqueRlp, Messages: messages, Hash: sighash}
+		req = &SignDataRequest{ContentType: mediaType, Rawdata: cliqueRlp, Messages: messages, Hash: sighash,
+			Clique: &CliqueHeaderInfo{
+				Number:     header.Number.Uint64(),
+				ParentHash: header.ParentHash,
+				SealHash:   common.BytesToHash(sighash),
+				Time:       header.Time,
+				Coinbase:   header.Coinbase,
+			},
+		}
 	default: // also case TextPlain.Mime:
 		// Calculates an Ethereum ECDSA signature for:
 		// hash = keccak256("\x19${byteVersion}Ethereum Signed Message:\n${message length}${message}")
@@ -296,6 +289,30 @@ func SignTextValidator(validatorData ValidatorData) (hexutil.Bytes, string) {
 	return crypto.Keccak256([]byte(msg)), msg
 }
 
+// decodeCliqueHeader decodes the hex-encoded clique header of a signing request.
+func decodeCliqueHeader(data interface{}) (*types.Header, error) {
+	stringData, ok := data.(string)
+	if !ok {
+		return nil, fmt.Errorf("input for %v must be an hex-encoded string", ApplicationClique.Mime)
+	}
+	cliqueData, err := hexutil.Decode(stringData)
+	if err != nil {
+		return nil, err
+	}
+	header := &types.Header{}
+	if err := rlp.DecodeBytes(cliqueData, header); err != nil {
+		return nil, err
+	}
+	// The incoming clique header is already truncated, sent to us with a extradata already shortened
+	if len(header.Extra) < 65 {
+		// Need to add it back, to get a suitable length for hashing
+		newExtra := make([]byte, len(header.Extra)+65)
+		copy(newExtra, header.Extra)
+		header.Extra = newExtra
+	}
+	return header, nil
+}
+
 // cliqueHeaderHashAndRlp returns the hash which is used as input for the proof-of-authority
 // signing. It is the hash of the entire header apart from the 65 byte signature
 // contained at the end of the extra data.
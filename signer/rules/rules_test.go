@@ -626,3 +626,70 @@ function ApproveSignData(r){
 		t.Fatalf("Expected approved")
 	}
 }
+
+// cliqueRules approves clique headers with strictly increasing heights and at
+// most three signatures per minute, re-signing the last header is allowed.
+const cliqueRules = `
+function ApproveSignData(r) {
+	if (r.content_type != "application/x-clique-header" || !r.clique) {
+		return
+	}
+	var now = new Date().getTime()
+	var state = {number: 0, seal_hash: "", signed: []}
+	var stored = storage.get("clique")
+	if (stored != "") {
+		state = JSON.parse(stored)
+	}
+	if (r.clique.number == state.number && r.clique.seal_hash == state.seal_hash) {
+		return "Approve"
+	}
+	if (r.clique.number <= state.number) {
+		return "Reject"
+	}
+	state.signed = state.signed.filter(function(t) { return t > now - 60000 })
+	if (state.signed.length >= 3) {
+		return "Reject"
+	}
+	state.number = r.clique.number
+	state.seal_hash = r.clique.seal_hash
+	state.signed.push(now)
+	storage.put("clique", JSON.stringify(state))
+	return "Approve"
+}`
+
+func TestCliqueSignDataRequest(t *testing.T) {
+	r, err := initRuleEngine(cliqueRules)
+	if err != nil {
+		t.Fatalf("Couldn't create evaluator %v", err)
+	}
+	addr, _ := mixAddr("0x694267f14675d7e1b9494fd8d72fefe1755710fa")
+	approve := func(number uint64, sealHash common.Hash) bool {
+		resp, err := r.ApproveSignData(&core.SignDataRequest{
+			ContentType: accounts.MimetypeClique,
+			Address:     *addr,
+			Clique:      &core.CliqueHeaderInfo{Number: number, SealHash: sealHash},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+		return resp.Approved
+	}
+	tests := []struct {
+		number   uint64
+		sealHash common.Hash
+		approved bool
+	}{
+		{1, common.Hash{1}, true},
+		{1, common.Hash{1}, true},  // Re-signing the same header
+		{1, common.Hash{2}, false}, // Different header at a signed height
+		{2, common.Hash{3}, true},
+		{1, common.Hash{4}, false}, // Lower height
+		{3, common.Hash{5}, true},
+		{4, common.Hash{6}, false}, // Rate limited
+	}
+	for i, tt := range tests {
+		if have := approve(tt.number, tt.sealHash); have != tt.approved {
+			t.Errorf("test %d: approval mismatch: have %v, want %v", i, have, tt.approved)
+		}
+	}
+}
@@ -3,11 +3,10 @@ package trie
 import (
 	"math/big"
 
-	"github.com/syndtr/goleveldb/leveldb"
-
 	zktrie "github.com/scroll-tech/zktrie/trie"
 
 	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/core/rawdb"
 	"github.com/scroll-tech/go-ethereum/ethdb"
 )
 
@@ -64,7 +63,7 @@ func (l *ZktrieDatabase) Get(key []byte) ([]byte, error) {
 	}
 
 	v, err := l.db.diskdb.Get(concatKey)
-	if err == leveldb.ErrNotFound {
+	if rawdb.IsNotFoundErr(err) {
 		return nil, zktrie.ErrKeyNotFound
 	}
 	if l.db.cleans != nil {
@@ -4,7 +4,11 @@ import (
 	"bytes"
 	"testing"
 
+	zktrie "github.com/scroll-tech/zktrie/trie"
+
 	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/ethdb/leveldb"
+	"github.com/scroll-tech/go-ethereum/ethdb/memorydb"
 )
 
 // grep from `feat/snap`
@@ -67,3 +71,20 @@ func TestBitDoubleReverse(t *testing.T) {
 	}
 
 }
+
+func TestZktrieDatabaseKeyNotFound(t *testing.T) {
+	diskdb, err := leveldb.New(t.TempDir(), 16, 16, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer diskdb.Close()
+
+	for _, db := range []*ZktrieDatabase{
+		NewZktrieDatabase(memorydb.New()),
+		NewZktrieDatabase(diskdb),
+	} {
+		if _, err := db.Get(common.FromHex("7b908cce3bc16abb3eac5dff6c136856526f15225f74ce860a2bec47912a5492")); err != zktrie.ErrKeyNotFound {
+			t.Errorf("missing key error mismatch: have %v, want %v", err, zktrie.ErrKeyNotFound)
+		}
+	}
+}